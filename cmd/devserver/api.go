@@ -0,0 +1,238 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"diagrams/sqlschema"
+	"store"
+)
+
+// Error codes returned by the diagrams and query Lambdas
+const (
+	errInvalidJSON             = "INVALID_JSON"
+	errInvalidOptimizationType = "INVALID_OPTIMIZATION_TYPE"
+	errInternalServerError     = "INTERNAL_SERVER_ERROR"
+	errInvalidRetentionPeriod  = "INVALID_RETENTION_PERIOD"
+	errInvalidSchemaMapping    = "INVALID_SCHEMA_MAPPING"
+	errInvalidOutputLanguage   = "INVALID_OUTPUT_LANGUAGE"
+	errNotFound                = "NOT_FOUND"
+)
+
+// convertRequest is the body of POST /api/v1/schemas
+type convertRequest struct {
+	SQLContent       string `json:"sqlContent"`
+	OptimizationType string `json:"optimizationType,omitempty"`
+	RetentionDays    int    `json:"retentionDays,omitempty"`
+	SchemaMapping    string `json:"schemaMapping,omitempty"`
+	OutputLanguage   string `json:"outputLanguage,omitempty"`
+}
+
+type errorResponse struct {
+	Error   string                       `json:"error"`
+	Message string                       `json:"message"`
+	Details []sqlschema.ValidationDetail `json:"details,omitempty"`
+}
+
+// server serves the routes API Gateway maps to the diagrams Lambda (POST) and
+// the query handler (GET), with the same request and response contract.
+type server struct {
+	store store.ConversionStore
+	queue *memoryQueue
+}
+
+func (s *server) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/v1/schemas", s.handleCreate)
+	mux.HandleFunc("GET /api/v1/schemas", s.handleList)
+	mux.HandleFunc("GET /api/v1/schemas/{id}", s.handleGet)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusNotFound, errorResponse{Error: errNotFound, Message: "Route not found"})
+	})
+	return withCORS(withLogging(mux))
+}
+
+func (s *server) handleCreate(w http.ResponseWriter, r *http.Request) {
+	var body convertRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: errInvalidJSON, Message: "Request body is not valid JSON"})
+		return
+	}
+
+	if strings.TrimSpace(body.SQLContent) == "" {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: sqlschema.ErrEmptySQLContent, Message: "Field sqlContent is required"})
+		return
+	}
+	if body.OptimizationType == "" {
+		body.OptimizationType = "balanced"
+	}
+	if !sqlschema.ValidOptimizationTypes[body.OptimizationType] {
+		writeJSON(w, http.StatusBadRequest, errorResponse{
+			Error:   errInvalidOptimizationType,
+			Message: "Invalid optimization type. Valid values: read_heavy, write_heavy, balanced",
+		})
+		return
+	}
+	if body.RetentionDays < 0 || body.RetentionDays > sqlschema.MaxRetentionDays {
+		writeJSON(w, http.StatusBadRequest, errorResponse{
+			Error:   errInvalidRetentionPeriod,
+			Message: fmt.Sprintf("Invalid retentionDays. Must be between 1 and %d, or 0 to omit it", sqlschema.MaxRetentionDays),
+		})
+		return
+	}
+	if body.SchemaMapping != "" && !sqlschema.ValidSchemaMappings[body.SchemaMapping] {
+		writeJSON(w, http.StatusBadRequest, errorResponse{
+			Error:   errInvalidSchemaMapping,
+			Message: "Invalid schema mapping. Valid values: prefix, entity",
+		})
+		return
+	}
+	if body.OutputLanguage == "" {
+		body.OutputLanguage = sqlschema.DefaultOutputLanguage
+	}
+	if !sqlschema.ValidOutputLanguages[body.OutputLanguage] {
+		writeJSON(w, http.StatusBadRequest, errorResponse{
+			Error:   errInvalidOutputLanguage,
+			Message: "Invalid output language. Valid values: es, en",
+		})
+		return
+	}
+
+	result := sqlschema.ValidateSQL(body.SQLContent)
+	if !result.IsValid {
+		writeJSON(w, http.StatusBadRequest, errorResponse{
+			Error:   sqlschema.ErrInvalidSQLSyntax,
+			Message: result.Errors[0].Message,
+			Details: result.Errors,
+		})
+		return
+	}
+	sqlschema.ApplyRetentionPeriod(result.Hints, body.RetentionDays)
+	sqlschema.ApplySchemaMapping(&result, body.SchemaMapping)
+
+	var hintsJSON []byte
+	if len(result.Hints) > 0 {
+		hintsJSON, _ = json.Marshal(result.Hints)
+	}
+	record := store.NewConversion(body.SQLContent, body.OptimizationType, len(result.Tables), hintsJSON)
+	record.OutputLanguage = body.OutputLanguage
+
+	if err := s.store.Create(r.Context(), record); err != nil {
+		log.Printf("ERROR: Failed to create record: %v", err)
+		writeJSON(w, http.StatusInternalServerError, errorResponse{Error: errInternalServerError, Message: "Failed to create conversion"})
+		return
+	}
+
+	msg, err := queueMessageFor(record)
+	if err != nil {
+		log.Printf("ERROR: Failed to build queue message: %v", err)
+		writeJSON(w, http.StatusInternalServerError, errorResponse{Error: errInternalServerError, Message: "Failed to create conversion"})
+		return
+	}
+	if !s.queue.Send(msg) {
+		// Same as the Lambda: the record stays PENDING
+		log.Printf("WARN: Queue full, conversion %s stays PENDING", record.ConversionID)
+	}
+
+	response := map[string]interface{}{
+		"conversionId": record.ConversionID,
+		"status":       record.Status,
+		"createdAt":    record.CreatedAt,
+		"expiresAt":    record.ExpiresAt,
+	}
+	if len(result.Warnings) > 0 {
+		response["warnings"] = result.Warnings
+	}
+	if len(result.Hints) > 0 {
+		response["designHints"] = result.Hints
+	}
+	writeJSON(w, http.StatusAccepted, response)
+}
+
+func (s *server) handleList(w http.ResponseWriter, r *http.Request) {
+	conversions, err := s.store.List(r.Context())
+	if err != nil {
+		log.Printf("ERROR: List conversions failed: %v", err)
+		writeJSON(w, http.StatusInternalServerError, errorResponse{Error: errInternalServerError, Message: "Failed to list conversions"})
+		return
+	}
+
+	records := make([]conversionResponse, 0, len(conversions))
+	for _, c := range conversions {
+		records = append(records, newConversionResponse(c))
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"conversions": records,
+		"count":       len(records),
+	})
+}
+
+func (s *server) handleGet(w http.ResponseWriter, r *http.Request) {
+	c, err := s.store.Get(r.Context(), r.PathValue("id"))
+	if errors.Is(err, store.ErrNotFound) {
+		writeJSON(w, http.StatusNotFound, errorResponse{Error: errNotFound, Message: "Conversion not found"})
+		return
+	}
+	if err != nil {
+		log.Printf("ERROR: Get conversion failed: %v", err)
+		writeJSON(w, http.StatusInternalServerError, errorResponse{Error: errInternalServerError, Message: "Failed to retrieve conversion"})
+		return
+	}
+	writeJSON(w, http.StatusOK, newConversionResponse(*c))
+}
+
+// conversionResponse renders a conversion like the query handler, with
+// noSqlSchema parsed when it is valid JSON.
+type conversionResponse struct {
+	store.Conversion
+	NoSQLSchema interface{} `json:"noSqlSchema,omitempty"`
+}
+
+func newConversionResponse(c store.Conversion) conversionResponse {
+	// The cached model response is internal to the worker
+	c.ModelResponse = ""
+
+	resp := conversionResponse{Conversion: c}
+	if c.NoSQLSchema == "" {
+		return resp
+	}
+
+	var parsed interface{}
+	if err := json.Unmarshal([]byte(c.NoSQLSchema), &parsed); err == nil {
+		resp.NoSQLSchema = parsed
+	} else {
+		resp.NoSQLSchema = c.NoSQLSchema
+	}
+	return resp
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func withLogging(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		log.Printf("Request: %s %s", r.Method, r.URL.Path)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// withCORS lets a frontend served from another local port call the API
+func withCORS(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
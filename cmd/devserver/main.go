@@ -0,0 +1,105 @@
+// Command devserver runs the whole conversion flow on a laptop, without AWS,
+// LocalStack or Docker: the diagrams and query endpoints over net/http, an
+// in-memory (optionally file-persisted) schemas table and queue, and the
+// conversion worker in-process.
+//
+//	go run ./cmd/devserver -addr :8080 -data .devserver.json
+//
+// The model is mocked unless -bedrock is set, in which case the provider
+// selected by MODEL_PROVIDER is used: Bedrock with the local AWS credentials
+// and BEDROCK_MODEL_ID by default, or a local model server with openai.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"conversion-worker/converter"
+	"store"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "listen address")
+	dataFile := flag.String("data", "", "JSON file to persist conversions (default in-memory only)")
+	workers := flag.Int("workers", 2, "in-process conversion workers")
+	queueSize := flag.Int("queue-size", 100, "queue capacity")
+	useBedrock := flag.Bool("bedrock", false, "invoke the MODEL_PROVIDER model (default Bedrock) instead of the mock response")
+	flag.Parse()
+
+	if *useBedrock {
+		os.Setenv("USE_MOCK_BEDROCK", "false")
+		provider, err := converter.NewProviderFromEnv()
+		if err != nil {
+			log.Fatalf("-bedrock: %v", err)
+		}
+		converter.SetProvider(provider)
+	} else {
+		converter.SetProvider(converter.NewMockProvider())
+	}
+
+	conversions := store.NewMemoryStore()
+	if *dataFile != "" {
+		var err error
+		if conversions, err = store.OpenFileStore(*dataFile); err != nil {
+			log.Fatal(err)
+		}
+	}
+	queue := newMemoryQueue(*queueSize)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	wg := runWorkers(ctx, *workers, queue, conversions)
+	requeuePending(ctx, conversions, queue)
+
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           (&server{store: conversions, queue: queue}).routes(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdownCtx)
+	}()
+
+	log.Printf("Dev server listening on %s (bedrock: %v, workers: %d)", *addr, *useBedrock, *workers)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatal(err)
+	}
+	wg.Wait()
+}
+
+// requeuePending re-enqueues conversions a previous run left PENDING or
+// PROCESSING, the way SQS redelivers unacknowledged messages.
+func requeuePending(ctx context.Context, conversions store.ConversionStore, queue *memoryQueue) {
+	pending, err := conversions.List(ctx)
+	if err != nil {
+		log.Printf("WARN: Failed to list conversions: %v", err)
+		return
+	}
+
+	for i := range pending {
+		c := &pending[i]
+		if c.Status != store.StatusPending && c.Status != store.StatusProcessing {
+			continue
+		}
+		body, err := queueMessageFor(c)
+		if err != nil {
+			log.Printf("[%s] WARN: Failed to re-enqueue: %v", c.ConversionID, err)
+			continue
+		}
+		if queue.Send(body) {
+			log.Printf("[%s] Re-enqueued %s conversion", c.ConversionID, c.Status)
+		}
+	}
+}
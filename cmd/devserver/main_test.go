@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"conversion-worker/converter"
+	"diagrams/sqlschema"
+	"store"
+)
+
+func newTestServer(t *testing.T, conversions store.ConversionStore) *httptest.Server {
+	t.Helper()
+	converter.SetProvider(converter.NewMockProvider())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	queue := newMemoryQueue(10)
+	wg := runWorkers(ctx, 1, queue, conversions)
+
+	srv := httptest.NewServer((&server{store: conversions, queue: queue}).routes())
+	t.Cleanup(func() {
+		srv.Close()
+		cancel()
+		wg.Wait()
+		converter.SetProvider(nil)
+	})
+	return srv
+}
+
+func getJSON(t *testing.T, url string, v interface{}) int {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode
+}
+
+func TestLifecycle_PendingToCompleted(t *testing.T) {
+	srv := newTestServer(t, store.NewMemoryStore())
+
+	body := `{"sqlContent": "CREATE TABLE users (id SERIAL PRIMARY KEY, email VARCHAR(100));"}`
+	resp, err := http.Post(srv.URL+"/api/v1/schemas", "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d", resp.StatusCode)
+	}
+
+	var created map[string]interface{}
+	json.NewDecoder(resp.Body).Decode(&created)
+	id, _ := created["conversionId"].(string)
+	if id == "" || created["status"] != "PENDING" {
+		t.Fatalf("unexpected response: %v", created)
+	}
+
+	var record map[string]interface{}
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if code := getJSON(t, srv.URL+"/api/v1/schemas/"+id, &record); code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", code)
+		}
+		if record["status"] == "COMPLETED" {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if record["status"] != "COMPLETED" {
+		t.Fatalf("conversion did not complete: %v", record)
+	}
+	if _, ok := record["noSqlSchema"].(map[string]interface{}); !ok {
+		t.Errorf("noSqlSchema should be returned parsed, got %T", record["noSqlSchema"])
+	}
+
+	var list map[string]interface{}
+	getJSON(t, srv.URL+"/api/v1/schemas", &list)
+	if list["count"] != float64(1) {
+		t.Errorf("expected 1 conversion, got %v", list["count"])
+	}
+}
+
+func TestHandleCreate_Errors(t *testing.T) {
+	srv := newTestServer(t, store.NewMemoryStore())
+
+	tests := []struct {
+		name     string
+		body     string
+		wantCode string
+	}{
+		{"json inválido", `{`, errInvalidJSON},
+		{"sqlContent vacío", `{"sqlContent": "  "}`, sqlschema.ErrEmptySQLContent},
+		{"optimizationType inválido", `{"sqlContent": "CREATE TABLE t (id INT);", "optimizationType": "fast"}`, errInvalidOptimizationType},
+		{"sql sin tablas", `{"sqlContent": "SELECT 1;"}`, sqlschema.ErrInvalidSQLSyntax},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := http.Post(srv.URL+"/api/v1/schemas", "application/json", strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			var errResp errorResponse
+			json.NewDecoder(resp.Body).Decode(&errResp)
+			if resp.StatusCode != http.StatusBadRequest || errResp.Error != tt.wantCode {
+				t.Errorf("got %d %s, want 400 %s", resp.StatusCode, errResp.Error, tt.wantCode)
+			}
+		})
+	}
+}
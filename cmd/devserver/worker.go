@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"conversion-worker/worker"
+	"store"
+)
+
+// Redelivery of failed messages, like the SQS redrive policy: a message is
+// received at most maxReceiveCount times, with a growing delay in between,
+// and then its conversion is failed as the DLQ handler does.
+const (
+	maxReceiveCount           = 3
+	baseRedeliveryDelay       = 1 * time.Second
+	maxRedeliveryDelay        = 10 * time.Second
+	errCodeMaxRetriesExceeded = "MAX_RETRIES_EXCEEDED"
+)
+
+// delivery is a queued message body and how many times it was received
+type delivery struct {
+	body         string
+	receiveCount int
+}
+
+// memoryQueue replaces the SQS conversion queue. Messages travel as JSON, as
+// they do through SQS, so the hints take the same diagrams → worker path.
+type memoryQueue struct {
+	messages chan delivery
+}
+
+func newMemoryQueue(size int) *memoryQueue {
+	return &memoryQueue{messages: make(chan delivery, size)}
+}
+
+// Send enqueues a message body; it fails instead of blocking when full
+func (q *memoryQueue) Send(body string) bool {
+	return q.send(delivery{body: body})
+}
+
+func (q *memoryQueue) send(d delivery) bool {
+	select {
+	case q.messages <- d:
+		return true
+	default:
+		return false
+	}
+}
+
+// queueMessageFor builds the queue message body of a stored conversion
+func queueMessageFor(c *store.Conversion) (string, error) {
+	body, err := json.Marshal(store.NewQueueMessage(c))
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal queue message: %w", err)
+	}
+	return string(body), nil
+}
+
+// runWorkers consumes the queue with n in-process conversion workers until
+// ctx is cancelled. Each message goes through the same processing as in the
+// conversion worker Lambda.
+func runWorkers(ctx context.Context, n int, queue *memoryQueue, conversions store.ConversionStore) *sync.WaitGroup {
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case d := <-queue.messages:
+					d.receiveCount++
+					if err := worker.Process(ctx, conversions, d.body, nil); err != nil {
+						redeliver(ctx, queue, conversions, d, err)
+					}
+				}
+			}
+		}()
+	}
+	return &wg
+}
+
+// redeliver puts a failed message back on the queue after a backoff, or
+// dead-letters it once it reached maxReceiveCount.
+func redeliver(ctx context.Context, queue *memoryQueue, conversions store.ConversionStore, d delivery, err error) {
+	if d.receiveCount >= maxReceiveCount {
+		deadLetter(ctx, conversions, d)
+		return
+	}
+
+	delay := worker.JitteredBackoff(baseRedeliveryDelay, maxRedeliveryDelay, d.receiveCount)
+	log.Printf("Message failed (receive %d/%d), redelivering in %s: %v", d.receiveCount, maxReceiveCount, delay, err)
+	time.AfterFunc(delay, func() {
+		if ctx.Err() == nil && !queue.send(d) {
+			log.Printf("WARN: Queue full, dropping redelivered message")
+		}
+	})
+}
+
+// deadLetter marks the conversion of an exhausted message as FAILED with the
+// context of its failed deliveries, like the DLQ handler Lambda.
+func deadLetter(ctx context.Context, conversions store.ConversionStore, d delivery) {
+	var msg store.QueueMessage
+	if err := json.Unmarshal([]byte(d.body), &msg); err != nil {
+		log.Printf("ERROR: Discarding message with invalid body: %v", err)
+		return
+	}
+
+	current, err := conversions.Get(ctx, msg.ConversionID)
+	if err != nil {
+		log.Printf("[%s] Discarding message: %v", msg.ConversionID, err)
+		return
+	}
+	if store.IsTerminal(current.Status) {
+		return
+	}
+
+	failure := &store.Failure{
+		ReceiveCount:  d.receiveCount,
+		LastErrorCode: current.LastErrorCode,
+		LastError:     current.LastError,
+	}
+	errMsg := fmt.Sprintf("Max retries exceeded after %d deliveries", d.receiveCount)
+	if current.LastError != "" {
+		errMsg += ": " + current.LastError
+	}
+
+	err = conversions.Fail(ctx, msg.ConversionID, errCodeMaxRetriesExceeded, errMsg, failure)
+	if err != nil && !errors.Is(err, store.ErrInvalidTransition) {
+		log.Printf("[%s] ERROR: Failed to update status to FAILED: %v", msg.ConversionID, err)
+		return
+	}
+	log.Printf("[%s] Marked as FAILED after %d deliveries", msg.ConversionID, d.receiveCount)
+}
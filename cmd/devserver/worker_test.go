@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"conversion-worker/converter"
+	"store"
+)
+
+func TestRunWorkers_ReusesCachedModelResponse(t *testing.T) {
+	// Sin proveedor: invocar el modelo fallaría
+	converter.SetProvider(nil)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	conversions := store.NewMemoryStore()
+	queue := newMemoryQueue(10)
+	wg := runWorkers(ctx, 1, queue, conversions)
+	t.Cleanup(func() {
+		cancel()
+		wg.Wait()
+	})
+
+	// Corrida anterior: obtuvo la respuesta pero no llegó a completar
+	c := store.NewConversion("CREATE TABLE t (id INT);", "balanced", 1, nil)
+	conversions.Create(ctx, c)
+	conversions.Transition(ctx, c.ConversionID, store.StatusProcessing)
+	conversions.SaveModelResponse(ctx, c.ConversionID, `{"tables":[]}`, store.ModelInfo{})
+
+	body, err := queueMessageFor(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	queue.Send(body)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if got, _ := conversions.Get(ctx, c.ConversionID); store.IsTerminal(got.Status) {
+			if got.Status != store.StatusCompleted || got.NoSQLSchema != `{"tables":[]}` {
+				t.Errorf("expected the cached response to complete the conversion, got %+v", got)
+			}
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("conversion did not finish")
+}
+
+func TestDeadLetter(t *testing.T) {
+	ctx := context.Background()
+	conversions := store.NewMemoryStore()
+
+	c := store.NewConversion("CREATE TABLE t (id INT);", "balanced", 1, nil)
+	conversions.Create(ctx, c)
+	conversions.Transition(ctx, c.ConversionID, store.StatusProcessing)
+	conversions.RecordError(ctx, c.ConversionID, converter.ErrCodeThrottled, "throttled")
+
+	body, _ := queueMessageFor(c)
+	deadLetter(ctx, conversions, delivery{body: body, receiveCount: maxReceiveCount})
+
+	got, _ := conversions.Get(ctx, c.ConversionID)
+	if got.Status != store.StatusFailed || got.ErrorCode != errCodeMaxRetriesExceeded {
+		t.Fatalf("expected FAILED with %s, got %s %s", errCodeMaxRetriesExceeded, got.Status, got.ErrorCode)
+	}
+	if got.Failure == nil || got.Failure.ReceiveCount != maxReceiveCount || got.Failure.LastErrorCode != converter.ErrCodeThrottled {
+		t.Errorf("unexpected failure context: %+v", got.Failure)
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"diagrams/sqlschema"
+)
+
+// Design is the DynamoDB design produced by a conversion. It mirrors the JSON
+// structure the conversion prompt asks the model for (see converter package).
+type Design struct {
+	Tables []TableDesign `json:"tables"`
+}
+
+// TableDesign describes one DynamoDB table.
+type TableDesign struct {
+	TableName              string           `json:"tableName"`
+	PartitionKey           KeyAttribute     `json:"partitionKey"`
+	SortKey                *KeyAttribute    `json:"sortKey"`
+	Attributes             []Attribute      `json:"attributes"`
+	GlobalSecondaryIndexes []IndexDesign    `json:"globalSecondaryIndexes"`
+	BillingMode            string           `json:"billingMode"`
+	TTLAttribute           string           `json:"ttlAttribute,omitempty"`
+	ValidationRules        []ValidationRule `json:"validationRules,omitempty"`
+}
+
+// KeyAttribute is a key attribute of a table or index (type S, N or B).
+type KeyAttribute struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// Attribute documents an item attribute.
+type Attribute struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Description string `json:"description,omitempty"`
+}
+
+// IndexDesign describes a global secondary index.
+type IndexDesign struct {
+	IndexName        string        `json:"indexName"`
+	PartitionKey     KeyAttribute  `json:"partitionKey"`
+	SortKey          *KeyAttribute `json:"sortKey"`
+	Projection       string        `json:"projection"`
+	NonKeyAttributes []string      `json:"nonKeyAttributes,omitempty"`
+}
+
+// ValidationRule is an application-side rule derived from a CHECK constraint.
+type ValidationRule struct {
+	Attribute string `json:"attribute"`
+	Rule      string `json:"rule"`
+}
+
+// parseDesign decodes a model response into a Design. Models sometimes wrap
+// the JSON in a markdown fence or add a sentence around it, so only the
+// outermost object is decoded.
+func parseDesign(text string) (Design, error) {
+	start := strings.Index(text, "{")
+	end := strings.LastIndex(text, "}")
+	if start == -1 || end < start {
+		return Design{}, fmt.Errorf("response does not contain a JSON object")
+	}
+
+	var design Design
+	if err := json.Unmarshal([]byte(text[start:end+1]), &design); err != nil {
+		return Design{}, fmt.Errorf("failed to parse design JSON: %w", err)
+	}
+	if len(design.Tables) == 0 {
+		return Design{}, fmt.Errorf("design has no tables")
+	}
+
+	for _, table := range design.Tables {
+		if table.TableName == "" || table.PartitionKey.Name == "" {
+			return Design{}, fmt.Errorf("design table %q has no name or partition key", table.TableName)
+		}
+	}
+	return design, nil
+}
+
+// reservedAttributes warns about DynamoDB reserved words among the attribute
+// names of the final design, including generated ones (composite keys, TTL)
+// that the SQL validation cannot see.
+func reservedAttributes(design Design) []sqlschema.ValidationDetail {
+	var warnings []sqlschema.ValidationDetail
+	for _, table := range design.Tables {
+		names := []string{table.PartitionKey.Name}
+		if table.SortKey != nil {
+			names = append(names, table.SortKey.Name)
+		}
+		for _, attr := range table.Attributes {
+			names = append(names, attr.Name)
+		}
+		for _, gsi := range table.GlobalSecondaryIndexes {
+			names = append(names, gsi.PartitionKey.Name)
+			if gsi.SortKey != nil {
+				names = append(names, gsi.SortKey.Name)
+			}
+		}
+		names = append(names, table.TTLAttribute)
+
+		seen := make(map[string]bool)
+		for _, name := range names {
+			if name == "" || seen[name] || !sqlschema.IsDynamoDBReservedWord(name) {
+				continue
+			}
+			seen[name] = true
+			warnings = append(warnings, sqlschema.ValidationDetail{
+				Code:     sqlschema.WarnDynamoDBReservedWord,
+				Message:  fmt.Sprintf("Attribute %q of table %q is a DynamoDB reserved word; use ExpressionAttributeNames (e.g. #%s) in expressions", name, table.TableName, name),
+				Severity: sqlschema.SeverityWarning,
+				Table:    table.TableName,
+			})
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"redrive"
+	"store"
+)
+
+// dlqOptions are the flags shared by dlq list and dlq redrive
+type dlqOptions struct {
+	queueURL    string
+	table       string
+	maxMessages int
+	asJSON      bool
+}
+
+func (o *dlqOptions) register(fs *flag.FlagSet) {
+	fs.StringVar(&o.queueURL, "queue-url", os.Getenv("SQS_QUEUE_URL"), "conversion queue URL")
+	fs.StringVar(&o.table, "table", os.Getenv("DYNAMODB_TABLE_NAME"), "conversions table")
+	fs.IntVar(&o.maxMessages, "max", redrive.DefaultMaxMessages, "maximum dead-lettered conversions to read")
+	fs.BoolVar(&o.asJSON, "json", false, "print the result as JSON")
+}
+
+func (o dlqOptions) validate() string {
+	switch {
+	case o.queueURL == "":
+		return "--queue-url or SQS_QUEUE_URL is required"
+	case o.table == "":
+		return "--table or DYNAMODB_TABLE_NAME is required"
+	case o.maxMessages <= 0:
+		return "--max must be positive"
+	}
+	return ""
+}
+
+// newRedriver connects to SQS and the conversions table with the local AWS
+// credentials, honoring SQS_ENDPOINT and DYNAMODB_ENDPOINT.
+func newRedriver(ctx context.Context, o dlqOptions) (*redrive.Redriver, error) {
+	// The store reads the table from the environment, like the Lambdas
+	os.Setenv("DYNAMODB_TABLE_NAME", o.table)
+	conversions, err := store.NewDynamoStoreFromEnv(ctx)
+	if err != nil {
+		return nil, err
+	}
+	client, err := redrive.NewSQSClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return redrive.New(client, conversions, o.queueURL), nil
+}
+
+func runDLQ(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprintln(stderr, "dlq: expected list or redrive")
+		return exitUsage
+	}
+
+	switch args[0] {
+	case "list":
+		return runDLQList(args[1:], stdout, stderr)
+	case "redrive":
+		return runDLQRedrive(args[1:], stdout, stderr)
+	default:
+		fmt.Fprintf(stderr, "dlq: unknown subcommand %q (list, redrive)\n", args[0])
+		return exitUsage
+	}
+}
+
+func runDLQList(args []string, stdout, stderr io.Writer) int {
+	var opts dlqOptions
+	fs := flag.NewFlagSet("dlq list", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	opts.register(fs)
+
+	if rest, err := parseInterspersed(fs, args); err != nil || len(rest) > 0 {
+		if err == nil {
+			fmt.Fprintln(stderr, "dlq list: unexpected arguments")
+		}
+		return exitUsage
+	}
+	if msg := opts.validate(); msg != "" {
+		fmt.Fprintf(stderr, "dlq list: %s\n", msg)
+		return exitUsage
+	}
+
+	ctx := context.Background()
+	r, err := newRedriver(ctx, opts)
+	if err != nil {
+		fmt.Fprintf(stderr, "dlq list: %v\n", err)
+		return exitUsage
+	}
+	messages, err := r.List(ctx, opts.maxMessages)
+	if err != nil {
+		fmt.Fprintf(stderr, "dlq list: %v\n", err)
+		return exitConversion
+	}
+
+	if opts.asJSON {
+		if messages == nil {
+			messages = []redrive.Message{}
+		}
+		if err := writeJSON(stdout, messages); err != nil {
+			fmt.Fprintf(stderr, "dlq list: %v\n", err)
+			return exitUsage
+		}
+		return exitOK
+	}
+
+	fmt.Fprintf(stdout, "%d conversion(s) failed after exhausting their retries\n", len(messages))
+	for _, m := range messages {
+		fmt.Fprintf(stdout, "  %s  %-10s receives=%d", m.ConversionID, orDash(m.Status), m.ReceiveCount)
+		if m.Failure != nil && m.Failure.LastErrorCode != "" {
+			fmt.Fprintf(stdout, "  last error: %s", m.Failure.LastErrorCode)
+		} else if m.ErrorCode != "" {
+			fmt.Fprintf(stdout, "  error: %s", m.ErrorCode)
+		}
+		fmt.Fprintln(stdout)
+	}
+	return exitOK
+}
+
+func runDLQRedrive(args []string, stdout, stderr io.Writer) int {
+	var opts dlqOptions
+	var all, dryRun bool
+	fs := flag.NewFlagSet("dlq redrive", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	opts.register(fs)
+	fs.BoolVar(&all, "all", false, "redrive every dead-lettered conversion")
+	fs.BoolVar(&dryRun, "dry-run", false, "report what would be redriven without changing anything")
+
+	ids, err := parseInterspersed(fs, args)
+	if err != nil {
+		return exitUsage
+	}
+	if all == (len(ids) > 0) {
+		fmt.Fprintln(stderr, "dlq redrive: pass conversion IDs or --all")
+		return exitUsage
+	}
+	if msg := opts.validate(); msg != "" {
+		fmt.Fprintf(stderr, "dlq redrive: %s\n", msg)
+		return exitUsage
+	}
+
+	ctx := context.Background()
+	r, err := newRedriver(ctx, opts)
+	if err != nil {
+		fmt.Fprintf(stderr, "dlq redrive: %v\n", err)
+		return exitUsage
+	}
+	results, err := r.Redrive(ctx, redrive.Options{ConversionIDs: ids, All: all, DryRun: dryRun, MaxMessages: opts.maxMessages})
+	if err != nil {
+		fmt.Fprintf(stderr, "dlq redrive: %v\n", err)
+		return exitConversion
+	}
+
+	code := exitOK
+	for _, result := range results {
+		if result.Action == redrive.ActionFailed {
+			code = exitConversion
+		}
+	}
+
+	if opts.asJSON {
+		if results == nil {
+			results = []redrive.Result{}
+		}
+		if err := writeJSON(stdout, results); err != nil {
+			fmt.Fprintf(stderr, "dlq redrive: %v\n", err)
+			return exitUsage
+		}
+		return code
+	}
+
+	if dryRun {
+		fmt.Fprintln(stdout, "dry run: nothing was changed")
+	}
+	for _, result := range results {
+		fmt.Fprintf(stdout, "  %s  %s", result.ConversionID, result.Action)
+		if result.Reason != "" {
+			fmt.Fprintf(stdout, " (%s)", result.Reason)
+		}
+		fmt.Fprintln(stdout)
+	}
+	return code
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
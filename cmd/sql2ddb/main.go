@@ -0,0 +1,349 @@
+// Command sql2ddb validates PostgreSQL schemas and converts them to DynamoDB
+// designs offline, without deploying the Lambda stack:
+//
+//	sql2ddb validate schema.sql
+//	sql2ddb convert --format terraform schema.sql -o out/
+//	sql2ddb dlq redrive --dry-run --all
+//
+// Exit codes are meant for CI pipelines: 0 success, 1 invalid schema (or
+// warnings with --strict), 2 usage or I/O error, 3 conversion (or redrive)
+// failure.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"conversion-worker/converter"
+	"diagrams/sqlschema"
+)
+
+const (
+	exitOK         = 0
+	exitInvalid    = 1
+	exitUsage      = 2
+	exitConversion = 3
+)
+
+// Conversion engines
+const (
+	engineRules   = "rules"
+	engineBedrock = "bedrock"
+	engineMock    = "mock"
+)
+
+// Output formats of convert
+const (
+	formatJSON      = "json"
+	formatTerraform = "terraform"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return exitUsage
+	}
+
+	switch args[0] {
+	case "validate":
+		return runValidate(args[1:], stdout, stderr)
+	case "convert":
+		return runConvert(args[1:], stdout, stderr)
+	case "dlq":
+		return runDLQ(args[1:], stdout, stderr)
+	case "help", "-h", "--help":
+		usage(stdout)
+		return exitOK
+	default:
+		fmt.Fprintf(stderr, "unknown command %q\n\n", args[0])
+		usage(stderr)
+		return exitUsage
+	}
+}
+
+func usage(w io.Writer) {
+	fmt.Fprint(w, `Usage:
+  sql2ddb validate [--json] [--strict] <file.sql>...
+  sql2ddb convert [flags] <file.sql> [-o <dir>]
+  sql2ddb dlq list [flags]
+  sql2ddb dlq redrive [flags] (--all | <conversion-id>...)
+
+Convert flags:
+  --format json|terraform      output format (default json)
+  --engine rules|bedrock|mock  conversion engine (default rules)
+  --optimization TYPE          read_heavy, write_heavy or balanced (default balanced)
+  --retention-days N           retention for time-series tables (TTL)
+  --schema-mapping MODE        prefix or entity, for scripts with several schemas
+  --model ID                   Bedrock model ID (default $BEDROCK_MODEL_ID)
+  --language es|en             language of descriptions and rules, bedrock engine (default es)
+  -o, --out DIR                write the artifact to DIR instead of stdout
+
+DLQ flags:
+  --queue-url URL              conversion queue (default $SQS_QUEUE_URL)
+  --table NAME                 conversions table (default $DYNAMODB_TABLE_NAME)
+  --max N                      maximum dead-lettered conversions to read (default 100)
+  --dry-run                    redrive: report without changing anything
+  --json                       print the result as JSON
+
+Exit codes: 0 ok, 1 invalid schema, 2 usage error, 3 conversion or redrive failed.
+`)
+}
+
+// ============================================================================
+// validate
+// ============================================================================
+
+// fileResult is the --json output of validate for one file
+type fileResult struct {
+	File   string                     `json:"file"`
+	Result sqlschema.ValidationResult `json:"result"`
+}
+
+func runValidate(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("validate", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	asJSON := fs.Bool("json", false, "print the validation result as JSON")
+	strict := fs.Bool("strict", false, "treat warnings as failures")
+
+	files, err := parseInterspersed(fs, args)
+	if err != nil {
+		return exitUsage
+	}
+	if len(files) == 0 {
+		fmt.Fprintln(stderr, "validate: at least one .sql file is required")
+		return exitUsage
+	}
+
+	code := exitOK
+	var results []fileResult
+
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			fmt.Fprintf(stderr, "validate: %v\n", err)
+			return exitUsage
+		}
+
+		result := sqlschema.ValidateSQL(string(content))
+		if !result.IsValid || (*strict && len(result.Warnings) > 0) {
+			code = exitInvalid
+		}
+
+		if *asJSON {
+			results = append(results, fileResult{File: file, Result: result})
+			continue
+		}
+		printValidation(stdout, file, result)
+	}
+
+	if *asJSON {
+		if err := writeJSON(stdout, results); err != nil {
+			fmt.Fprintf(stderr, "validate: %v\n", err)
+			return exitUsage
+		}
+	}
+	return code
+}
+
+func printValidation(w io.Writer, file string, result sqlschema.ValidationResult) {
+	if result.IsValid {
+		fmt.Fprintf(w, "%s: valid (%d tables, %d warnings, %d design hints)\n",
+			file, len(result.Tables), len(result.Warnings), len(result.Hints))
+	} else {
+		fmt.Fprintf(w, "%s: invalid (%d errors)\n", file, len(result.Errors))
+	}
+
+	for _, detail := range append(result.Errors, result.Warnings...) {
+		fmt.Fprintf(w, "  %-7s %s: %s\n", detail.Severity, detail.Code, detail.Message)
+	}
+}
+
+// ============================================================================
+// convert
+// ============================================================================
+
+type convertOptions struct {
+	format        string
+	engine        string
+	optimization  string
+	retentionDays int
+	schemaMapping string
+	model         string
+	language      string
+	outDir        string
+}
+
+func runConvert(args []string, stdout, stderr io.Writer) int {
+	var opts convertOptions
+	fs := flag.NewFlagSet("convert", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(&opts.format, "format", formatJSON, "output format: json or terraform")
+	fs.StringVar(&opts.engine, "engine", engineRules, "conversion engine: rules, bedrock or mock")
+	fs.StringVar(&opts.optimization, "optimization", "balanced", "read_heavy, write_heavy or balanced")
+	fs.IntVar(&opts.retentionDays, "retention-days", 0, "retention in days for time-series tables")
+	fs.StringVar(&opts.schemaMapping, "schema-mapping", "", "prefix or entity")
+	fs.StringVar(&opts.model, "model", os.Getenv("BEDROCK_MODEL_ID"), "Bedrock model ID")
+	fs.StringVar(&opts.language, "language", sqlschema.DefaultOutputLanguage, "language of the design descriptions: es or en")
+	fs.StringVar(&opts.outDir, "o", "", "output directory")
+	fs.StringVar(&opts.outDir, "out", "", "output directory")
+
+	files, err := parseInterspersed(fs, args)
+	if err != nil {
+		return exitUsage
+	}
+	if len(files) != 1 {
+		fmt.Fprintln(stderr, "convert: exactly one .sql file is required")
+		return exitUsage
+	}
+	if msg := opts.validate(); msg != "" {
+		fmt.Fprintf(stderr, "convert: %s\n", msg)
+		return exitUsage
+	}
+
+	content, err := os.ReadFile(files[0])
+	if err != nil {
+		fmt.Fprintf(stderr, "convert: %v\n", err)
+		return exitUsage
+	}
+
+	result := sqlschema.ValidateSQL(string(content))
+	if !result.IsValid {
+		printValidation(stderr, files[0], result)
+		return exitInvalid
+	}
+	sqlschema.ApplyRetentionPeriod(result.Hints, opts.retentionDays)
+	sqlschema.ApplySchemaMapping(&result, opts.schemaMapping)
+
+	design, err := convert(context.Background(), string(content), result, opts)
+	if err != nil {
+		fmt.Fprintf(stderr, "convert: %v\n", err)
+		return exitConversion
+	}
+	for _, detail := range reservedAttributes(design) {
+		fmt.Fprintf(stderr, "  %-7s %s: %s\n", detail.Severity, detail.Code, detail.Message)
+	}
+
+	artifact, name := renderArtifact(design, opts.format, filepath.Base(files[0]))
+	if opts.outDir == "" {
+		fmt.Fprint(stdout, artifact)
+		return exitOK
+	}
+
+	if err := os.MkdirAll(opts.outDir, 0o755); err != nil {
+		fmt.Fprintf(stderr, "convert: %v\n", err)
+		return exitUsage
+	}
+	path := filepath.Join(opts.outDir, name)
+	if err := os.WriteFile(path, []byte(artifact), 0o644); err != nil {
+		fmt.Fprintf(stderr, "convert: %v\n", err)
+		return exitUsage
+	}
+	fmt.Fprintf(stdout, "wrote %s (%d tables)\n", path, len(design.Tables))
+	return exitOK
+}
+
+func (o convertOptions) validate() string {
+	switch {
+	case o.format != formatJSON && o.format != formatTerraform:
+		return fmt.Sprintf("invalid --format %q (json, terraform)", o.format)
+	case o.engine != engineRules && o.engine != engineBedrock && o.engine != engineMock:
+		return fmt.Sprintf("invalid --engine %q (rules, bedrock, mock)", o.engine)
+	case !sqlschema.ValidOptimizationTypes[o.optimization]:
+		return fmt.Sprintf("invalid --optimization %q (read_heavy, write_heavy, balanced)", o.optimization)
+	case o.retentionDays < 0 || o.retentionDays > sqlschema.MaxRetentionDays:
+		return fmt.Sprintf("--retention-days must be between 1 and %d, or 0 to omit it", sqlschema.MaxRetentionDays)
+	case o.schemaMapping != "" && !sqlschema.ValidSchemaMappings[o.schemaMapping]:
+		return fmt.Sprintf("invalid --schema-mapping %q (prefix, entity)", o.schemaMapping)
+	case !sqlschema.ValidOutputLanguages[o.language]:
+		return fmt.Sprintf("invalid --language %q (es, en)", o.language)
+	case o.engine == engineBedrock && o.model == "":
+		return "--model or BEDROCK_MODEL_ID is required with --engine bedrock"
+	}
+	return ""
+}
+
+// convert runs the selected engine and returns the design
+func convert(ctx context.Context, sqlContent string, result sqlschema.ValidationResult, opts convertOptions) (Design, error) {
+	switch opts.engine {
+	case engineMock:
+		return parseDesign(converter.MockResponse())
+	case engineBedrock:
+		// The converter reads the model from the environment, like the worker
+		os.Setenv("BEDROCK_MODEL_ID", opts.model)
+		converter.InitProvider()
+
+		hints, err := converterHints(result.Hints)
+		if err != nil {
+			return Design{}, err
+		}
+		text, err := converter.InvokeConversion(ctx, sqlContent, opts.optimization, opts.language, hints)
+		if err != nil {
+			return Design{}, err
+		}
+		return parseDesign(text)
+	default:
+		return buildRuleBasedDesign(result, opts.optimization, opts.schemaMapping), nil
+	}
+}
+
+// converterHints passes the hints to the converter the same way the process
+// handler does through SQS: as JSON.
+func converterHints(hints []sqlschema.DesignHint) ([]converter.DesignHint, error) {
+	b, err := json.Marshal(hints)
+	if err != nil {
+		return nil, err
+	}
+	var converted []converter.DesignHint
+	if err := json.Unmarshal(b, &converted); err != nil {
+		return nil, err
+	}
+	return converted, nil
+}
+
+// renderArtifact returns the artifact content and its file name in -o
+func renderArtifact(design Design, format, source string) (string, string) {
+	if format == formatTerraform {
+		return renderTerraform(design, source), "main.tf"
+	}
+	b, _ := json.MarshalIndent(design, "", "  ")
+	return string(b) + "\n", "design.json"
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+// parseInterspersed parses flags placed before and after the positional
+// arguments (convert schema.sql -o out/), which flag.Parse alone stops at.
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		rest := fs.Args()
+		if len(rest) == 0 {
+			return positional, nil
+		}
+		if rest[0] == "--" {
+			return append(positional, rest[1:]...), nil
+		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+}
+
+func writeJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
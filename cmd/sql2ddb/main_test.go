@@ -0,0 +1,244 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"diagrams/sqlschema"
+)
+
+const testSchema = `
+CREATE TABLE users (
+    id SERIAL PRIMARY KEY,
+    email VARCHAR(100) NOT NULL,
+    age INT CHECK (age >= 18)
+);
+CREATE TABLE orders (
+    user_id INT REFERENCES users(id),
+    order_id BIGINT,
+    total NUMERIC(10,2),
+    PRIMARY KEY (user_id, order_id)
+);`
+
+func writeSchema(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "schema.sql")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRun_ExitCodes(t *testing.T) {
+	valid := writeSchema(t, testSchema)
+	invalid := writeSchema(t, "SELECT 1;")
+
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{"sin argumentos", nil, exitUsage},
+		{"comando desconocido", []string{"deploy"}, exitUsage},
+		{"validate esquema válido", []string{"validate", valid}, exitOK},
+		{"validate con warnings en modo strict", []string{"validate", "--strict", valid}, exitInvalid},
+		{"validate esquema inválido", []string{"validate", invalid}, exitInvalid},
+		{"validate archivo inexistente", []string{"validate", "missing.sql"}, exitUsage},
+		{"convert con reglas", []string{"convert", valid}, exitOK},
+		{"convert con mock", []string{"convert", "--engine", "mock", valid}, exitOK},
+		{"convert esquema inválido", []string{"convert", invalid}, exitInvalid},
+		{"convert formato inválido", []string{"convert", "--format", "yaml", valid}, exitUsage},
+		{"convert sin archivo", []string{"convert"}, exitUsage},
+		{"dlq sin subcomando", []string{"dlq"}, exitUsage},
+		{"dlq subcomando desconocido", []string{"dlq", "purge"}, exitUsage},
+		{"dlq list sin URL de la cola", []string{"dlq", "list", "--queue-url", "", "--table", "t"}, exitUsage},
+		{"dlq redrive sin selección", []string{"dlq", "redrive", "--dry-run", "--queue-url", "q", "--table", "t"}, exitUsage},
+		{"dlq redrive con IDs y --all", []string{"dlq", "redrive", "--all", "abc", "--queue-url", "q", "--table", "t"}, exitUsage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if got := run(tt.args, &stdout, &stderr); got != tt.want {
+				t.Errorf("exit code = %d, want %d (stderr: %s)", got, tt.want, stderr.String())
+			}
+		})
+	}
+}
+
+func TestRun_ConvertWritesTerraform(t *testing.T) {
+	outDir := t.TempDir()
+	var stdout, stderr bytes.Buffer
+
+	// Los flags después del archivo también se deben reconocer
+	code := run([]string{"convert", "--format", "terraform", writeSchema(t, testSchema), "-o", outDir}, &stdout, &stderr)
+	if code != exitOK {
+		t.Fatalf("exit code = %d, stderr: %s", code, stderr.String())
+	}
+
+	tf, err := os.ReadFile(filepath.Join(outDir, "main.tf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`resource "aws_dynamodb_table" "users"`,
+		`resource "aws_dynamodb_table" "orders"`,
+		`range_key    = "order_id"`,
+		`# Validate in the application: age >= 18`,
+	} {
+		if !strings.Contains(string(tf), want) {
+			t.Errorf("main.tf missing %q\n%s", want, tf)
+		}
+	}
+}
+
+func TestBuildRuleBasedDesign(t *testing.T) {
+	result := sqlschema.ValidateSQL(testSchema)
+	design := buildRuleBasedDesign(result, "write_heavy", "")
+
+	if len(design.Tables) != 2 {
+		t.Fatalf("expected 2 tables, got %d", len(design.Tables))
+	}
+
+	orders := design.Tables[1]
+	if orders.PartitionKey != (KeyAttribute{Name: "user_id", Type: "N"}) {
+		t.Errorf("partition key = %+v", orders.PartitionKey)
+	}
+	if orders.SortKey == nil || orders.SortKey.Name != "order_id" {
+		t.Errorf("sort key = %+v", orders.SortKey)
+	}
+	// La FK coincide con la partition key: no requiere GSI
+	if len(orders.GlobalSecondaryIndexes) != 0 {
+		t.Errorf("expected no GSI, got %+v", orders.GlobalSecondaryIndexes)
+	}
+}
+
+func TestAttributeType(t *testing.T) {
+	tests := []struct {
+		name     string
+		dataType string
+		want     string
+	}{
+		{"entero", "INTEGER", "N"},
+		{"numeric con precisión", "NUMERIC(10,2)", "N"},
+		{"interval no es numérico", "INTERVAL", "S"},
+		{"arreglo", "TEXT[]", "L"},
+		{"jsonb", "JSONB", "M"},
+		{"booleano", "BOOLEAN", "BOOL"},
+		{"binario", "BYTEA", "B"},
+		{"texto", "VARCHAR(50)", "S"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := attributeType(sqlschema.ColumnInfo{DataType: tt.dataType}); got != tt.want {
+				t.Errorf("attributeType(%q) = %q, want %q", tt.dataType, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDesign(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		wantErr bool
+	}{
+		{"json envuelto en markdown", "```json\n{\"tables\":[{\"tableName\":\"t\",\"partitionKey\":{\"name\":\"id\",\"type\":\"S\"}}]}\n```", false},
+		{"sin objeto json", "no design", true},
+		{"sin tablas", `{"tables":[]}`, true},
+		{"tabla sin partition key", `{"tables":[{"tableName":"t"}]}`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseDesign(tt.text)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseDesign() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReservedAttributes(t *testing.T) {
+	// Revisa el diseño final: atributos, clave compuesta generada (name_date) y TTL
+	result := sqlschema.ValidateSQL(`CREATE TABLE readings (
+		sensor INT,
+		name TEXT,
+		date DATE,
+		value NUMERIC,
+		PRIMARY KEY (sensor, name, date)
+	);`)
+	design := buildRuleBasedDesign(result, "balanced", "")
+	design.Tables[0].TTLAttribute = "ttl"
+
+	var got []string
+	for _, w := range reservedAttributes(design) {
+		if w.Code != sqlschema.WarnDynamoDBReservedWord || w.Table != "readings" {
+			t.Errorf("unexpected warning %+v", w)
+		}
+		got = append(got, w.Message[strings.Index(w.Message, `"`)+1:strings.Index(w.Message, `" of`)])
+	}
+	if strings.Join(got, ",") != "name,date,value,ttl" {
+		t.Errorf("reserved attributes = %v", got)
+	}
+}
+
+func TestBuildRuleBasedDesign_SchemaMapping(t *testing.T) {
+	result := sqlschema.ValidateSQL(`
+		CREATE TABLE sales.orders (id INT PRIMARY KEY, customer_id INT, status TEXT);
+		CREATE TABLE archive.orders (id INT, customer_id INT, archived_on DATE, PRIMARY KEY (id, archived_on));
+		CREATE VIEW sales.orders_by_customer AS SELECT * FROM sales.orders WHERE customer_id = 1;
+	`)
+	if !result.IsValid {
+		t.Fatalf("invalid schema: %+v", result.Errors)
+	}
+
+	tests := []struct {
+		name       string
+		mapping    string
+		wantTables string
+		wantPK     string
+		wantSK     string
+	}{
+		{"prefix por defecto", "", "sales_orders,archive_orders", "id", ""},
+		{"prefix", "prefix", "sales_orders,archive_orders", "id", ""},
+		{"entity comparte la tabla", "entity", "orders", "PK", "SK"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			design := buildRuleBasedDesign(result, "balanced", tt.mapping)
+
+			var names []string
+			for _, table := range design.Tables {
+				names = append(names, table.TableName)
+			}
+			if strings.Join(names, ",") != tt.wantTables {
+				t.Fatalf("tables = %v, want %s", names, tt.wantTables)
+			}
+
+			first := design.Tables[0]
+			if first.PartitionKey.Name != tt.wantPK {
+				t.Errorf("partition key = %+v, want %s", first.PartitionKey, tt.wantPK)
+			}
+			if sk := first.SortKey; (sk == nil) != (tt.wantSK == "") || sk != nil && sk.Name != tt.wantSK {
+				t.Errorf("sort key = %+v, want %q", sk, tt.wantSK)
+			}
+
+			// El hint de la vista es de sales.orders: su GSI no aplica a archive.orders
+			var gsis []string
+			for _, table := range design.Tables {
+				for _, gsi := range table.GlobalSecondaryIndexes {
+					gsis = append(gsis, table.TableName+":"+gsi.IndexName)
+				}
+			}
+			if len(gsis) != 1 || gsis[0] != first.TableName+":customer_id-index" {
+				t.Errorf("indexes = %v", gsis)
+			}
+		})
+	}
+}
@@ -0,0 +1,272 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"diagrams/sqlschema"
+)
+
+// buildRuleBasedDesign converts the validated schema without calling a model:
+// one DynamoDB table per SQL table, keyed by its primary key, with a GSI per
+// foreign key and per view access pattern. It is deterministic, which makes it
+// a useful baseline in CI; the Bedrock engine produces richer designs.
+//
+// Scripts with several schemas follow schemaMapping: prefix (the default)
+// names each table schema_table, entity keeps the table name and moves the
+// schema into the partition key, so same-named tables share one table.
+func buildRuleBasedDesign(result sqlschema.ValidationResult, optimizationType, schemaMapping string) Design {
+	projection := "ALL"
+	if optimizationType == "write_heavy" {
+		// Every GSI write replicates the projected attributes
+		projection = "KEYS_ONLY"
+	}
+
+	mapping := ""
+	if len(hintsOfType(result.Hints, sqlschema.HintMultiSchema)) > 0 {
+		mapping = sqlschema.SchemaMappingPrefix
+		if schemaMapping == sqlschema.SchemaMappingEntity {
+			mapping = sqlschema.SchemaMappingEntity
+		}
+	}
+
+	var design Design
+	byName := make(map[string]int)
+	for _, table := range result.Tables {
+		td := tableDesign(table, result.Hints, projection, mapping)
+		if i, ok := byName[td.TableName]; ok {
+			mergeTableDesign(&design.Tables[i], td)
+			continue
+		}
+		byName[td.TableName] = len(design.Tables)
+		design.Tables = append(design.Tables, td)
+	}
+	return design
+}
+
+// entityKey is the partition key of entity-mapped tables: SCHEMA#TABLE#<id>
+const entityKey = "PK"
+
+func tableDesign(table sqlschema.TableInfo, hints []sqlschema.DesignHint, projection, mapping string) TableDesign {
+	schema := table.Schema
+	if schema == "" {
+		schema = "public"
+	}
+	name := table.Name
+	if mapping == sqlschema.SchemaMappingPrefix {
+		name = schema + "_" + table.Name
+	}
+
+	td := TableDesign{
+		TableName:   name,
+		BillingMode: "PAY_PER_REQUEST",
+	}
+
+	for _, col := range table.Columns {
+		td.Attributes = append(td.Attributes, Attribute{
+			Name:        col.Name,
+			Type:        attributeType(col),
+			Description: col.Description,
+		})
+	}
+
+	// Primary key: first column as partition key, the rest as sort key
+	keyCols := table.PrimaryKey
+	if len(keyCols) == 0 && len(table.Columns) > 0 {
+		keyCols = []string{table.Columns[0].Name}
+	}
+	if len(keyCols) > 0 {
+		td.PartitionKey = keyAttribute(table, keyCols[0])
+	}
+	switch {
+	case len(keyCols) == 2:
+		sk := keyAttribute(table, keyCols[1])
+		td.SortKey = &sk
+	case len(keyCols) > 2:
+		sk := KeyAttribute{Name: strings.Join(keyCols[1:], "_"), Type: "S"}
+		td.SortKey = &sk
+		td.Attributes = append(td.Attributes, Attribute{
+			Name:        sk.Name,
+			Type:        "S",
+			Description: fmt.Sprintf("Composite sort key: %s joined with #", strings.Join(keyCols[1:], ", ")),
+		})
+	}
+
+	seenIndexes := make(map[string]bool)
+	addIndex := func(pk, sk string) {
+		if pk == "" || pk == td.PartitionKey.Name || !hasColumn(table, pk) {
+			return
+		}
+		index := IndexDesign{
+			IndexName:    pk + "-index",
+			PartitionKey: keyAttribute(table, pk),
+			Projection:   projection,
+		}
+		if sk != "" && sk != pk && hasColumn(table, sk) {
+			key := keyAttribute(table, sk)
+			index.SortKey = &key
+			index.IndexName = pk + "-" + sk + "-index"
+		}
+		if seenIndexes[index.IndexName] {
+			return
+		}
+		seenIndexes[index.IndexName] = true
+		td.GlobalSecondaryIndexes = append(td.GlobalSecondaryIndexes, index)
+	}
+
+	for _, fk := range table.ForeignKeys {
+		if len(fk.Columns) == 1 {
+			addIndex(fk.Columns[0], "")
+		}
+	}
+
+	for _, hint := range hints {
+		if hint.QualifiedTable() != table.QualifiedName() {
+			continue
+		}
+		if hint.Type == sqlschema.HintViewAccess && len(hint.Columns) > 0 {
+			sk := ""
+			if len(hint.Columns) > 1 {
+				sk = hint.Columns[1]
+			}
+			addIndex(hint.Columns[0], sk)
+		}
+		if hint.TTLAttribute != "" {
+			td.TTLAttribute = hint.TTLAttribute
+		}
+	}
+
+	for _, rule := range table.ValidationRules {
+		td.ValidationRules = append(td.ValidationRules, ValidationRule{Attribute: rule.Column, Rule: rule.String()})
+	}
+
+	if mapping == sqlschema.SchemaMappingEntity {
+		entity := strings.ToUpper(schema + "#" + table.Name)
+		td.Attributes = append(td.Attributes, Attribute{
+			Name:        entityKey,
+			Type:        "S",
+			Description: fmt.Sprintf("Entity type and partition key: %s#<%s>", entity, td.PartitionKey.Name),
+		})
+		td.PartitionKey = KeyAttribute{Name: entityKey, Type: "S"}
+	}
+
+	return td
+}
+
+// mergeTableDesign adds the attributes, indexes and rules of an entity-mapped
+// table to the table of the same name. Items of both share the table, so
+// different sort keys become a generic string sort key.
+func mergeTableDesign(dst *TableDesign, src TableDesign) {
+	if !sameKey(dst.SortKey, src.SortKey) {
+		dst.SortKey = &KeyAttribute{Name: "SK", Type: "S"}
+		src.Attributes = append(src.Attributes, Attribute{
+			Name:        "SK",
+			Type:        "S",
+			Description: "Sort key of the entities sharing the table: their own sort key, or their id when they have none",
+		})
+	}
+
+	attributes := make(map[string]bool)
+	for _, attr := range dst.Attributes {
+		attributes[attr.Name] = true
+	}
+	for _, attr := range src.Attributes {
+		if !attributes[attr.Name] {
+			attributes[attr.Name] = true
+			dst.Attributes = append(dst.Attributes, attr)
+		}
+	}
+
+	indexes := make(map[string]bool)
+	for _, gsi := range dst.GlobalSecondaryIndexes {
+		indexes[gsi.IndexName] = true
+	}
+	for _, gsi := range src.GlobalSecondaryIndexes {
+		if !indexes[gsi.IndexName] {
+			indexes[gsi.IndexName] = true
+			dst.GlobalSecondaryIndexes = append(dst.GlobalSecondaryIndexes, gsi)
+		}
+	}
+
+	if dst.TTLAttribute == "" {
+		dst.TTLAttribute = src.TTLAttribute
+	}
+	dst.ValidationRules = append(dst.ValidationRules, src.ValidationRules...)
+}
+
+func sameKey(a, b *KeyAttribute) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// keyAttribute returns a column as key attribute; keys only accept S, N or B
+func keyAttribute(table sqlschema.TableInfo, name string) KeyAttribute {
+	for _, col := range table.Columns {
+		if col.Name != name {
+			continue
+		}
+		switch t := attributeType(col); t {
+		case "N", "B":
+			return KeyAttribute{Name: name, Type: t}
+		}
+		break
+	}
+	return KeyAttribute{Name: name, Type: "S"}
+}
+
+var numericTypes = map[string]bool{
+	"smallint": true, "int2": true, "integer": true, "int": true, "int4": true,
+	"bigint": true, "int8": true, "decimal": true, "numeric": true,
+	"real": true, "float4": true, "double precision": true, "float8": true, "float": true,
+	"smallserial": true, "serial2": true, "serial": true, "serial4": true,
+	"bigserial": true, "serial8": true, "money": true,
+}
+
+// attributeType maps a PostgreSQL type to a DynamoDB attribute type
+func attributeType(col sqlschema.ColumnInfo) string {
+	dt := strings.ToLower(col.DataType)
+	if col.BaseType != "" {
+		dt = strings.ToLower(col.BaseType)
+	}
+	if strings.HasSuffix(dt, "[]") {
+		return "L"
+	}
+	if idx := strings.Index(dt, "("); idx != -1 {
+		dt = dt[:idx]
+	}
+	dt = strings.TrimSpace(dt)
+
+	switch {
+	case dt == "json" || dt == "jsonb":
+		return "M"
+	case dt == "bytea":
+		return "B"
+	case dt == "boolean" || dt == "bool":
+		return "BOOL"
+	case numericTypes[dt]:
+		return "N"
+	default:
+		return "S"
+	}
+}
+
+func hasColumn(table sqlschema.TableInfo, name string) bool {
+	for _, col := range table.Columns {
+		if col.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func hintsOfType(hints []sqlschema.DesignHint, hintType string) []sqlschema.DesignHint {
+	var filtered []sqlschema.DesignHint
+	for _, h := range hints {
+		if h.Type == hintType {
+			filtered = append(filtered, h)
+		}
+	}
+	return filtered
+}
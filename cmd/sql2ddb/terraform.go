@@ -0,0 +1,146 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var terraformNameRegex = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// Capacity used for PROVISIONED tables; tune it before applying
+const defaultProvisionedCapacity = 5
+
+// renderTerraform renders one aws_dynamodb_table resource per table. Only key
+// attributes become attribute blocks: Terraform rejects attributes that no key
+// or index uses.
+func renderTerraform(design Design, source string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Generated by sql2ddb from %s\n", source)
+
+	for _, table := range design.Tables {
+		b.WriteString("\n")
+		renderTable(&b, table)
+	}
+	return b.String()
+}
+
+func renderTable(b *strings.Builder, table TableDesign) {
+	provisioned := table.BillingMode == "PROVISIONED"
+	billingMode := table.BillingMode
+	if billingMode == "" {
+		billingMode = "PAY_PER_REQUEST"
+	}
+
+	for _, rule := range table.ValidationRules {
+		fmt.Fprintf(b, "# Validate in the application: %s\n", rule.Rule)
+	}
+
+	fmt.Fprintf(b, "resource \"aws_dynamodb_table\" %q {\n", terraformName(table.TableName))
+
+	args := [][2]string{
+		{"name", quote(table.TableName)},
+		{"billing_mode", quote(billingMode)},
+		{"hash_key", quote(table.PartitionKey.Name)},
+	}
+	if table.SortKey != nil {
+		args = append(args, [2]string{"range_key", quote(table.SortKey.Name)})
+	}
+	if provisioned {
+		capacity := fmt.Sprint(defaultProvisionedCapacity)
+		args = append(args, [2]string{"read_capacity", capacity}, [2]string{"write_capacity", capacity})
+	}
+	writeArguments(b, "  ", args)
+
+	for _, attr := range keyAttributes(table) {
+		b.WriteString("\n  attribute {\n")
+		writeArguments(b, "    ", [][2]string{{"name", quote(attr.Name)}, {"type", quote(attr.Type)}})
+		b.WriteString("  }\n")
+	}
+
+	for _, index := range table.GlobalSecondaryIndexes {
+		projection := index.Projection
+		if projection == "" || (projection == "INCLUDE" && len(index.NonKeyAttributes) == 0) {
+			projection = "ALL"
+		}
+
+		args := [][2]string{
+			{"name", quote(index.IndexName)},
+			{"hash_key", quote(index.PartitionKey.Name)},
+		}
+		if index.SortKey != nil {
+			args = append(args, [2]string{"range_key", quote(index.SortKey.Name)})
+		}
+		args = append(args, [2]string{"projection_type", quote(projection)})
+		if projection == "INCLUDE" {
+			quoted := make([]string, len(index.NonKeyAttributes))
+			for i, attr := range index.NonKeyAttributes {
+				quoted[i] = quote(attr)
+			}
+			args = append(args, [2]string{"non_key_attributes", "[" + strings.Join(quoted, ", ") + "]"})
+		}
+		if provisioned {
+			capacity := fmt.Sprint(defaultProvisionedCapacity)
+			args = append(args, [2]string{"read_capacity", capacity}, [2]string{"write_capacity", capacity})
+		}
+
+		b.WriteString("\n  global_secondary_index {\n")
+		writeArguments(b, "    ", args)
+		b.WriteString("  }\n")
+	}
+
+	if table.TTLAttribute != "" {
+		b.WriteString("\n  ttl {\n")
+		writeArguments(b, "    ", [][2]string{{"attribute_name", quote(table.TTLAttribute)}, {"enabled", "true"}})
+		b.WriteString("  }\n")
+	}
+
+	b.WriteString("}\n")
+}
+
+// keyAttributes collects the table and index key attributes without duplicates
+func keyAttributes(table TableDesign) []KeyAttribute {
+	var attrs []KeyAttribute
+	seen := make(map[string]bool)
+
+	add := func(key *KeyAttribute) {
+		if key == nil || key.Name == "" || seen[key.Name] {
+			return
+		}
+		seen[key.Name] = true
+		attrs = append(attrs, *key)
+	}
+
+	add(&table.PartitionKey)
+	add(table.SortKey)
+	for i := range table.GlobalSecondaryIndexes {
+		add(&table.GlobalSecondaryIndexes[i].PartitionKey)
+		add(table.GlobalSecondaryIndexes[i].SortKey)
+	}
+	return attrs
+}
+
+// writeArguments writes name = value lines aligned the way terraform fmt does
+func writeArguments(b *strings.Builder, indent string, args [][2]string) {
+	width := 0
+	for _, arg := range args {
+		if len(arg[0]) > width {
+			width = len(arg[0])
+		}
+	}
+	for _, arg := range args {
+		fmt.Fprintf(b, "%s%-*s = %s\n", indent, width, arg[0], arg[1])
+	}
+}
+
+func terraformName(tableName string) string {
+	name := strings.Trim(terraformNameRegex.ReplaceAllString(strings.ToLower(tableName), "_"), "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "table_" + name
+	}
+	return name
+}
+
+func quote(s string) string {
+	return fmt.Sprintf("%q", s)
+}
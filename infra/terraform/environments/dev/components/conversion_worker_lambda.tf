@@ -24,7 +24,15 @@ module "conversion_worker" {
     DYNAMODB_ENDPOINT   = var.dynamodb_endpoint
     SQS_QUEUE_URL       = var.sqs_queue_url
     SQS_ENDPOINT        = var.sqs_endpoint
-    BEDROCK_MODEL_ID      = "us.anthropic.claude-sonnet-4-20250514-v1:0"
+    PAYLOAD_BUCKET      = var.payload_bucket
+    S3_ENDPOINT         = var.s3_endpoint
+    MODEL_PROVIDER        = local.model_provider
+    BEDROCK_MODEL_ID      = local.bedrock_model_id
+    PROMPT_VERSION        = local.prompt_version
+    # Large schemas are converted in parallel chunks to fit the worker timeout.
+    # A parallel chunk only knows the keys of the tables it references, not the
+    # designs of the other chunks; "1" shares them but converts sequentially.
+    CHUNK_CONCURRENCY     = "4"
     USE_MOCK_BEDROCK      = tostring(var.use_mock_bedrock)
     BEDROCK_ENDPOINT      = "https://bedrock-runtime.${var.aws_region}.amazonaws.com"
     BEDROCK_AWS_ACCESS_KEY_ID     = var.aws_access_key_id
@@ -57,4 +65,7 @@ resource "aws_lambda_event_source_mapping" "sqs_to_conversion_worker" {
   function_name    = module.conversion_worker.function_arn
   batch_size       = 1
   enabled          = true
+
+  # The handler returns the failed messages; the rest of the batch is deleted
+  function_response_types = ["ReportBatchItemFailures"]
 }
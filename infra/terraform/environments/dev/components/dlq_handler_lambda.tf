@@ -49,4 +49,7 @@ resource "aws_lambda_event_source_mapping" "dlq_to_dlq_handler" {
   function_name    = module.dlq_handler.function_arn
   batch_size       = 1
   enabled          = true
+
+  # The handler returns the failed messages; the rest of the batch is deleted
+  function_response_types = ["ReportBatchItemFailures"]
 }
@@ -20,6 +20,12 @@ module "process_handler" {
     DYNAMODB_ENDPOINT   = var.dynamodb_endpoint
     SQS_QUEUE_URL       = var.sqs_queue_url
     SQS_ENDPOINT        = var.sqs_endpoint
+    PAYLOAD_BUCKET      = var.payload_bucket
+    S3_ENDPOINT         = var.s3_endpoint
+    MODEL_PROVIDER      = local.model_provider
+    BEDROCK_MODEL_ID    = local.bedrock_model_id
+    PROMPT_VERSION      = local.prompt_version
+    ADMIN_TOKEN         = var.admin_token
   }
 
   # Logging
@@ -21,6 +21,8 @@ module "query_handler" {
     DYNAMODB_ENDPOINT   = var.dynamodb_endpoint
     SQS_QUEUE_URL       = var.sqs_queue_url
     SQS_ENDPOINT        = var.sqs_endpoint
+    PAYLOAD_BUCKET      = var.payload_bucket
+    S3_ENDPOINT         = var.s3_endpoint
   }
 
   # Logging
@@ -54,6 +54,25 @@ variable "sqs_dlq_arn" {
   default     = ""
 }
 
+variable "s3_endpoint" {
+  description = "S3 endpoint URL (for LocalStack)"
+  type        = string
+  default     = ""
+}
+
+variable "payload_bucket" {
+  description = "S3 bucket for SQL content and results too large to store inline"
+  type        = string
+  default     = ""
+}
+
+variable "admin_token" {
+  description = "Bearer token for the DLQ admin endpoint (empty disables it)"
+  type        = string
+  default     = ""
+  sensitive   = true
+}
+
 variable "use_mock_bedrock" {
   description = "Use mock Bedrock responses (for LocalStack)"
   type        = bool
@@ -129,7 +129,7 @@ module "conversion_queue" {
   queue_name = "${var.environment}-conversion-queue"
   dlq_name   = "${var.environment}-conversion-dlq"
 
-  visibility_timeout_seconds = 180
+  visibility_timeout_seconds = 360    # Above the worker timeout
   message_retention_seconds  = 345600 # 4 days
   receive_wait_time_seconds  = 20     # Long polling
   max_receive_count          = 3
@@ -137,6 +137,21 @@ module "conversion_queue" {
   tags = local.common_tags
 }
 
+# ============================================
+# S3 (Payloads too large for DynamoDB or SQS)
+# ============================================
+
+module "payload_bucket" {
+  source = "../../modules/s3"
+
+  bucket_name        = "${local.project_name}-${var.environment}-conversion-payloads-${data.aws_caller_identity.current.account_id}"
+  force_destroy      = true
+  versioning_enabled = false
+  expiration_days    = 2 # Conversions expire after 24 hours
+
+  tags = local.common_tags
+}
+
 module "lambda_components" {
   source      = "./components"
   environment = var.environment
@@ -149,6 +164,9 @@ module "lambda_components" {
   sqs_queue_arn       = module.conversion_queue.queue_arn
   sqs_endpoint        = var.use_localstack ? var.localstack_lambda_endpoint : ""
   sqs_dlq_arn         = module.conversion_queue.dlq_arn
+  payload_bucket      = module.payload_bucket.bucket_name
+  s3_endpoint         = var.use_localstack ? var.localstack_lambda_endpoint : ""
+  admin_token         = var.admin_token
   use_mock_bedrock      = var.use_mock_bedrock
   aws_access_key_id     = var.aws_access_key_id
   aws_secret_access_key = var.aws_secret_access_key
@@ -188,4 +206,7 @@ module "shared" {
 
   # SQS ARN for IAM policies
   sqs_queue_arn = module.conversion_queue.queue_arn
+
+  # S3 payload bucket (claim check)
+  payload_bucket_arn = module.payload_bucket.bucket_arn
 }
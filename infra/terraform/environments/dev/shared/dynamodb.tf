@@ -9,10 +9,12 @@ module "schemas_table" {
   billing_mode = "PAY_PER_REQUEST"
   hash_key     = "conversionId"
 
-  # GSI attributes (conversionDate + createdAt for date-based queries)
+  # GSI attributes (conversionDate + createdAt for date-based queries,
+  # contentHash for the result cache)
   gsi_attributes = [
     { name = "conversionDate", type = "S" },
-    { name = "createdAt", type = "S" }
+    { name = "createdAt", type = "S" },
+    { name = "contentHash", type = "S" }
   ]
 
   global_secondary_indexes = [
@@ -21,6 +23,14 @@ module "schemas_table" {
       hash_key        = "conversionDate"
       range_key       = "createdAt"
       projection_type = "ALL"
+    },
+    {
+      # Result cache: newest COMPLETED conversion of the same normalized SQL
+      name               = "contentHash-createdAt-index"
+      hash_key           = "contentHash"
+      range_key          = "createdAt"
+      projection_type    = "INCLUDE"
+      non_key_attributes = ["status", "expiresAt"]
     }
   ]
 
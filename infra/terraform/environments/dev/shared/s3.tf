@@ -0,0 +1,25 @@
+# ============================================
+# Shared Module - S3 IAM Policies (Payload Bucket)
+# ============================================
+
+# IAM Policy - Lambda S3 Payloads (claim check for large SQL and results)
+resource "aws_iam_role_policy" "lambda_s3_payloads" {
+  name = "${var.environment}-lambda-s3-payloads"
+  role = var.lambda_role_name
+
+  policy = jsonencode({
+    Version = "2012-10-17"
+    Statement = [
+      {
+        Effect = "Allow"
+        Action = [
+          "s3:PutObject",
+          "s3:GetObject"
+        ]
+        Resource = [
+          "${var.payload_bucket_arn}/*"
+        ]
+      }
+    ]
+  })
+}
@@ -84,6 +84,14 @@ variable "schemas_table_name" {
   default     = "schemas"
 }
 
+# DLQ admin endpoint
+variable "admin_token" {
+  description = "Bearer token for the DLQ admin endpoint (empty disables it)"
+  type        = string
+  default     = ""
+  sensitive   = true
+}
+
 # Common tags
 variable "common_tags" {
   description = "Common tags for all resources"
@@ -22,7 +22,14 @@ module "conversion_worker" {
   environment_variables = {
     DYNAMODB_TABLE_NAME   = var.dynamodb_table_name
     SQS_QUEUE_URL         = var.sqs_queue_url
-    BEDROCK_MODEL_ID      = "us.anthropic.claude-3-5-sonnet-20241022-v2:0"
+    PAYLOAD_BUCKET        = var.payload_bucket
+    MODEL_PROVIDER        = local.model_provider
+    BEDROCK_MODEL_ID      = local.bedrock_model_id
+    PROMPT_VERSION        = local.prompt_version
+    # Large schemas are converted in parallel chunks to fit the worker timeout.
+    # A parallel chunk only knows the keys of the tables it references, not the
+    # designs of the other chunks; "1" shares them but converts sequentially.
+    CHUNK_CONCURRENCY     = "4"
     BEDROCK_AWS_REGION    = "us-east-1"
   }
 
@@ -51,4 +58,7 @@ resource "aws_lambda_event_source_mapping" "sqs_to_conversion_worker" {
   function_name    = module.conversion_worker.function_arn
   batch_size       = 1
   enabled          = true
+
+  # The handler returns the failed messages; the rest of the batch is deleted
+  function_response_types = ["ReportBatchItemFailures"]
 }
@@ -1,6 +1,13 @@
 locals {
   component_name = "lambda-core"
 
+  # Model provider, model and prompt template version used by the worker; the
+  # process handler includes them in the result cache key, so changing them
+  # invalidates cached conversions
+  model_provider   = "bedrock"
+  prompt_version   = "v2"
+  bedrock_model_id = "us.anthropic.claude-3-5-sonnet-20241022-v2:0"
+
   lambda_configs = {
     process_handler = {
       description                    = "process_handler"
@@ -17,7 +24,7 @@ locals {
       use_case                       = "UC-conversion_worker-001"
       api_operation                  = "conversion_worker"
       memory_size                    = 256
-      timeout                        = 120
+      timeout                        = 300 # large schemas are converted in chunks
       reserved_concurrent_executions = -1 # unreserved (use account default)
       log_retention_days             = 30
     }
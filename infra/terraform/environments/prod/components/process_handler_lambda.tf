@@ -18,6 +18,11 @@ module "process_handler" {
   environment_variables = {
     DYNAMODB_TABLE_NAME = var.dynamodb_table_name
     SQS_QUEUE_URL       = var.sqs_queue_url
+    PAYLOAD_BUCKET      = var.payload_bucket
+    MODEL_PROVIDER      = local.model_provider
+    BEDROCK_MODEL_ID    = local.bedrock_model_id
+    PROMPT_VERSION      = local.prompt_version
+    ADMIN_TOKEN         = var.admin_token
   }
 
   # Logging
@@ -18,6 +18,7 @@ module "query_handler" {
   environment_variables = {
     DYNAMODB_TABLE_NAME = var.dynamodb_table_name
     SQS_QUEUE_URL       = var.sqs_queue_url
+    PAYLOAD_BUCKET      = var.payload_bucket
   }
 
   # Logging
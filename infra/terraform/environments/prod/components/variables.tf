@@ -56,3 +56,16 @@ variable "sqs_dlq_arn" {
   type        = string
   default     = ""
 }
+
+variable "payload_bucket" {
+  description = "S3 bucket for SQL content and results too large to store inline"
+  type        = string
+  default     = ""
+}
+
+variable "admin_token" {
+  description = "Bearer token for the DLQ admin endpoint (empty disables it)"
+  type        = string
+  default     = ""
+  sensitive   = true
+}
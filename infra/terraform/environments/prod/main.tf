@@ -150,7 +150,7 @@ module "conversion_queue" {
   queue_name = "${var.environment}-conversion-queue"
   dlq_name   = "${var.environment}-conversion-dlq"
 
-  visibility_timeout_seconds = 180
+  visibility_timeout_seconds = 360    # Above the worker timeout
   message_retention_seconds  = 345600 # 4 days
   receive_wait_time_seconds  = 20     # Long polling
   max_receive_count          = 3
@@ -158,6 +158,21 @@ module "conversion_queue" {
   tags = local.common_tags
 }
 
+# ============================================
+# S3 (Payloads too large for DynamoDB or SQS)
+# ============================================
+
+module "payload_bucket" {
+  source = "../../modules/s3"
+
+  bucket_name        = "${local.project_name}-${var.environment}-conversion-payloads-${data.aws_caller_identity.current.account_id}"
+  force_destroy      = false
+  versioning_enabled = false
+  expiration_days    = 2 # Conversions expire after 24 hours
+
+  tags = local.common_tags
+}
+
 module "lambda_components" {
   source      = "./components"
   environment = var.environment
@@ -172,6 +187,8 @@ module "lambda_components" {
   sqs_queue_url       = module.conversion_queue.queue_url
   sqs_queue_arn       = module.conversion_queue.queue_arn
   sqs_dlq_arn         = module.conversion_queue.dlq_arn
+  payload_bucket      = module.payload_bucket.bucket_name
+  admin_token         = var.admin_token
 }
 
 # ============================================
@@ -211,6 +228,9 @@ module "shared" {
   # SQS ARN for IAM policies
   sqs_queue_arn = module.conversion_queue.queue_arn
   sqs_dlq_arn   = module.conversion_queue.dlq_arn
+
+  # S3 payload bucket (claim check)
+  payload_bucket_arn = module.payload_bucket.bucket_arn
 }
 
 # ============================================
@@ -20,6 +20,10 @@ module "api_gateway" {
     "POST /api/v1/schemas"     = { lambda_invoke_arn = var.lambda_invoke_arn, lambda_name = var.lambda_function_name }
     "GET /api/v1/schemas"      = { lambda_invoke_arn = var.query_handler_invoke_arn, lambda_name = var.query_handler_function_name }
     "GET /api/v1/schemas/{id}" = { lambda_invoke_arn = var.query_handler_invoke_arn, lambda_name = var.query_handler_function_name }
+
+    # DLQ admin (disabled unless admin_token is set)
+    "GET /api/v1/admin/dlq"          = { lambda_invoke_arn = var.lambda_invoke_arn, lambda_name = var.lambda_function_name }
+    "POST /api/v1/admin/dlq/redrive" = { lambda_invoke_arn = var.lambda_invoke_arn, lambda_name = var.lambda_function_name }
   }
 
   tags = var.common_tags
@@ -9,10 +9,12 @@ module "schemas_table" {
   billing_mode = "PAY_PER_REQUEST"
   hash_key     = "conversionId"
 
-  # GSI attributes (conversionDate + createdAt for date-based queries)
+  # GSI attributes (conversionDate + createdAt for date-based queries,
+  # contentHash for the result cache)
   gsi_attributes = [
     { name = "conversionDate", type = "S" },
-    { name = "createdAt", type = "S" }
+    { name = "createdAt", type = "S" },
+    { name = "contentHash", type = "S" }
   ]
 
   global_secondary_indexes = [
@@ -21,6 +23,14 @@ module "schemas_table" {
       hash_key        = "conversionDate"
       range_key       = "createdAt"
       projection_type = "ALL"
+    },
+    {
+      # Result cache: newest COMPLETED conversion of the same normalized SQL
+      name               = "contentHash-createdAt-index"
+      hash_key           = "contentHash"
+      range_key          = "createdAt"
+      projection_type    = "INCLUDE"
+      non_key_attributes = ["status", "expiresAt"]
     }
   ]
 
@@ -34,7 +44,8 @@ module "schemas_table" {
 }
 
 # ============================================
-# IAM Policy - process_handler: DynamoDB PutItem
+# IAM Policy - process_handler: DynamoDB PutItem (GetItem, UpdateItem and
+# Scan for the DLQ redrive)
 # ============================================
 
 resource "aws_iam_role_policy" "process_handler_dynamodb" {
@@ -47,10 +58,15 @@ resource "aws_iam_role_policy" "process_handler_dynamodb" {
       {
         Effect = "Allow"
         Action = [
-          "dynamodb:PutItem"
+          "dynamodb:PutItem",
+          "dynamodb:GetItem",
+          "dynamodb:UpdateItem",
+          "dynamodb:Query",
+          "dynamodb:Scan"
         ]
         Resource = [
-          module.schemas_table.table_arn
+          module.schemas_table.table_arn,
+          "${module.schemas_table.table_arn}/index/*"
         ]
       }
     ]
@@ -58,7 +74,7 @@ resource "aws_iam_role_policy" "process_handler_dynamodb" {
 }
 
 # ============================================
-# IAM Policy - conversion_worker: DynamoDB UpdateItem
+# IAM Policy - conversion_worker: DynamoDB GetItem, UpdateItem
 # ============================================
 
 resource "aws_iam_role_policy" "conversion_worker_dynamodb" {
@@ -71,6 +87,7 @@ resource "aws_iam_role_policy" "conversion_worker_dynamodb" {
       {
         Effect = "Allow"
         Action = [
+          "dynamodb:GetItem",
           "dynamodb:UpdateItem"
         ]
         Resource = [
@@ -109,7 +126,7 @@ resource "aws_iam_role_policy" "query_handler_dynamodb" {
 }
 
 # ============================================
-# IAM Policy - dlq_handler: DynamoDB UpdateItem
+# IAM Policy - dlq_handler: DynamoDB GetItem, UpdateItem
 # ============================================
 
 resource "aws_iam_role_policy" "dlq_handler_dynamodb" {
@@ -122,6 +139,7 @@ resource "aws_iam_role_policy" "dlq_handler_dynamodb" {
       {
         Effect = "Allow"
         Action = [
+          "dynamodb:GetItem",
           "dynamodb:UpdateItem"
         ]
         Resource = [
@@ -0,0 +1,68 @@
+# ============================================
+# Shared Module - S3 IAM Policies (Production)
+# ============================================
+
+# IAM Policy - process_handler: store large SQL content, copy cached results
+resource "aws_iam_role_policy" "lambda_s3_payloads_write" {
+  name = "${var.environment}-lambda-s3-payloads-write"
+  role = var.process_handler_role_name
+
+  policy = jsonencode({
+    Version = "2012-10-17"
+    Statement = [
+      {
+        Effect = "Allow"
+        Action = [
+          "s3:PutObject",
+          "s3:GetObject"
+        ]
+        Resource = [
+          "${var.payload_bucket_arn}/*"
+        ]
+      }
+    ]
+  })
+}
+
+# IAM Policy - conversion_worker: read SQL content, store large results
+resource "aws_iam_role_policy" "lambda_s3_payloads_worker" {
+  name = "${var.environment}-lambda-s3-payloads-worker"
+  role = var.conversion_worker_role_name
+
+  policy = jsonencode({
+    Version = "2012-10-17"
+    Statement = [
+      {
+        Effect = "Allow"
+        Action = [
+          "s3:PutObject",
+          "s3:GetObject"
+        ]
+        Resource = [
+          "${var.payload_bucket_arn}/*"
+        ]
+      }
+    ]
+  })
+}
+
+# IAM Policy - query_handler: read large results
+resource "aws_iam_role_policy" "lambda_s3_payloads_read" {
+  name = "${var.environment}-lambda-s3-payloads-read"
+  role = var.query_handler_role_name
+
+  policy = jsonencode({
+    Version = "2012-10-17"
+    Statement = [
+      {
+        Effect = "Allow"
+        Action = [
+          "s3:GetObject"
+        ]
+        Resource = [
+          "${var.payload_bucket_arn}/*"
+        ]
+      }
+    ]
+  })
+}
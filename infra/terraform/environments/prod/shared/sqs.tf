@@ -36,6 +36,7 @@ resource "aws_iam_role_policy" "lambda_sqs_consume" {
         Action = [
           "sqs:ReceiveMessage",
           "sqs:DeleteMessage",
+          "sqs:ChangeMessageVisibility",
           "sqs:GetQueueAttributes"
         ]
         Resource = [
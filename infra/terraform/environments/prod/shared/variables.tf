@@ -99,3 +99,8 @@ variable "schemas_table_name" {
   description = "Name for the schemas DynamoDB table"
   type        = string
 }
+
+variable "payload_bucket_arn" {
+  description = "ARN of the S3 payload bucket (for IAM policies)"
+  type        = string
+}
@@ -38,7 +38,8 @@ variable "global_secondary_indexes" {
     name            = string
     hash_key        = string
     range_key       = string
-    projection_type = string
+    projection_type    = string
+    non_key_attributes = optional(list(string))
   }))
   default = []
 }
@@ -37,6 +37,23 @@ resource "aws_s3_bucket_server_side_encryption_configuration" "this" {
   }
 }
 
+# Expiración de objetos (opcional, para datos efímeros)
+resource "aws_s3_bucket_lifecycle_configuration" "this" {
+  count  = var.expiration_days > 0 ? 1 : 0
+  bucket = aws_s3_bucket.this.id
+
+  rule {
+    id     = "expire-objects"
+    status = "Enabled"
+
+    filter {}
+
+    expiration {
+      days = var.expiration_days
+    }
+  }
+}
+
 # Tipo de contenido correcto para HTML (opcional, recomendado)
 resource "aws_s3_object" "html_objects" {
   for_each = var.html_objects
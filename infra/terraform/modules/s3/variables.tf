@@ -21,6 +21,12 @@ variable "enable_encryption" {
   default     = true
 }
 
+variable "expiration_days" {
+  description = "Delete objects this many days after creation (0 disables the rule)"
+  type        = number
+  default     = 0
+}
+
 variable "html_objects" {
   description = <<EOF
 Map of HTML objects to upload to S3.
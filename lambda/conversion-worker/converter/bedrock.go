@@ -0,0 +1,312 @@
+// Package converter builds the conversion prompt and invokes the model that
+// turns a SQL schema into a DynamoDB design. Shared by the conversion worker
+// and the sql2ddb CLI.
+package converter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime/document"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime/types"
+)
+
+// newBedrockClient creates the Bedrock client from the default AWS config
+// (local profile or Lambda role).
+func newBedrockClient(ctx context.Context) (*bedrockruntime.Client, error) {
+	var opts []func(*config.LoadOptions) error
+
+	// Use dedicated Bedrock credentials if available (needed in LocalStack
+	// where AWS_ACCESS_KEY_ID is overwritten with dummy values).
+	if ak := os.Getenv("BEDROCK_AWS_ACCESS_KEY_ID"); ak != "" {
+		sk := os.Getenv("BEDROCK_AWS_SECRET_ACCESS_KEY")
+		opts = append(opts, config.WithCredentialsProvider(
+			credentials.NewStaticCredentialsProvider(ak, sk, ""),
+		))
+	}
+
+	if region := os.Getenv("BEDROCK_AWS_REGION"); region != "" {
+		opts = append(opts, config.WithRegion(region))
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config for Bedrock: %v: %w", err, errNotConfigured)
+	}
+
+	endpoint := os.Getenv("BEDROCK_ENDPOINT")
+	if endpoint != "" {
+		return bedrockruntime.NewFromConfig(cfg, func(o *bedrockruntime.Options) {
+			o.BaseEndpoint = aws.String(endpoint)
+		}), nil
+	}
+	return bedrockruntime.NewFromConfig(cfg), nil
+}
+
+// bedrockModelID reads BEDROCK_MODEL_ID, required by both Bedrock providers
+func bedrockModelID() (string, error) {
+	modelID := os.Getenv("BEDROCK_MODEL_ID")
+	if modelID == "" {
+		return "", fmt.Errorf("BEDROCK_MODEL_ID not set: %w", errNotConfigured)
+	}
+	return modelID, nil
+}
+
+// BedrockInvokeProvider calls an Anthropic model on Bedrock through
+// InvokeModel, with the Messages API request body.
+type BedrockInvokeProvider struct {
+	client  *bedrockruntime.Client
+	modelID string
+}
+
+// NewBedrockInvokeProvider creates the provider for BEDROCK_MODEL_ID.
+func NewBedrockInvokeProvider(ctx context.Context) (*BedrockInvokeProvider, error) {
+	modelID, err := bedrockModelID()
+	if err != nil {
+		return nil, err
+	}
+	client, err := newBedrockClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &BedrockInvokeProvider{client: client, modelID: modelID}, nil
+}
+
+func (p *BedrockInvokeProvider) ModelID() string { return p.modelID }
+
+func (p *BedrockInvokeProvider) Complete(ctx context.Context, prompt string, opts CompletionOptions) (Completion, error) {
+	request := map[string]interface{}{
+		"anthropic_version": "bedrock-2023-05-31",
+		"max_tokens":        opts.MaxTokens,
+		"messages": []map[string]string{
+			{"role": "user", "content": prompt},
+		},
+	}
+	if opts.Tool != nil {
+		request["tools"] = []map[string]interface{}{{
+			"name":         opts.Tool.Name,
+			"description":  opts.Tool.Description,
+			"input_schema": opts.Tool.InputSchema,
+		}}
+		request["tool_choice"] = map[string]string{"type": "tool", "name": opts.Tool.Name}
+	}
+
+	requestBody, err := json.Marshal(request)
+	if err != nil {
+		return Completion{}, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	output, err := p.client.InvokeModel(ctx, &bedrockruntime.InvokeModelInput{
+		ModelId:     aws.String(p.modelID),
+		ContentType: aws.String("application/json"),
+		Body:        requestBody,
+	})
+	if err != nil {
+		return Completion{}, fmt.Errorf("Bedrock InvokeModel failed: %w", err)
+	}
+
+	var response struct {
+		Content []struct {
+			Type  string          `json:"type"`
+			Text  string          `json:"text"`
+			Name  string          `json:"name"`
+			Input json.RawMessage `json:"input"`
+		} `json:"content"`
+		StopReason string `json:"stop_reason"`
+		Usage      struct {
+			InputTokens  int `json:"input_tokens"`
+			OutputTokens int `json:"output_tokens"`
+		} `json:"usage"`
+	}
+	if err := json.Unmarshal(output.Body, &response); err != nil {
+		return Completion{}, fmt.Errorf("failed to parse Bedrock response: %v: %w", err, errInvalidOutput)
+	}
+
+	completion := Completion{
+		StopReason: response.StopReason,
+		Usage:      Usage{InputTokens: response.Usage.InputTokens, OutputTokens: response.Usage.OutputTokens},
+	}
+	// The answer may mix text blocks (the model's reasoning) and the tool call
+	var texts []string
+	for _, block := range response.Content {
+		switch block.Type {
+		case "text":
+			texts = append(texts, block.Text)
+		case "tool_use":
+			if opts.Tool != nil && block.Name == opts.Tool.Name {
+				completion.ToolInput = block.Input
+			}
+		}
+	}
+	completion.Text = strings.Join(texts, "")
+
+	if completion.Text == "" && completion.ToolInput == nil {
+		return Completion{}, fmt.Errorf("empty response from Bedrock: %w", errInvalidOutput)
+	}
+	return completion, nil
+}
+
+// BedrockConverseProvider calls any Bedrock text model through the Converse
+// API, which has the same request shape for every model family.
+type BedrockConverseProvider struct {
+	client  *bedrockruntime.Client
+	modelID string
+}
+
+// NewBedrockConverseProvider creates the provider for BEDROCK_MODEL_ID.
+func NewBedrockConverseProvider(ctx context.Context) (*BedrockConverseProvider, error) {
+	modelID, err := bedrockModelID()
+	if err != nil {
+		return nil, err
+	}
+	client, err := newBedrockClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &BedrockConverseProvider{client: client, modelID: modelID}, nil
+}
+
+func (p *BedrockConverseProvider) ModelID() string { return p.modelID }
+
+func (p *BedrockConverseProvider) Complete(ctx context.Context, prompt string, opts CompletionOptions) (Completion, error) {
+	input := &bedrockruntime.ConverseInput{
+		ModelId: aws.String(p.modelID),
+		Messages: []types.Message{{
+			Role:    types.ConversationRoleUser,
+			Content: []types.ContentBlock{&types.ContentBlockMemberText{Value: prompt}},
+		}},
+		InferenceConfig: &types.InferenceConfiguration{
+			MaxTokens: aws.Int32(int32(opts.MaxTokens)),
+		},
+	}
+	if opts.Tool != nil {
+		input.ToolConfig = &types.ToolConfiguration{
+			Tools: []types.Tool{&types.ToolMemberToolSpec{Value: types.ToolSpecification{
+				Name:        aws.String(opts.Tool.Name),
+				Description: aws.String(opts.Tool.Description),
+				InputSchema: &types.ToolInputSchemaMemberJson{Value: document.NewLazyDocument(opts.Tool.InputSchema)},
+			}}},
+			ToolChoice: &types.ToolChoiceMemberTool{Value: types.SpecificToolChoice{Name: aws.String(opts.Tool.Name)}},
+		}
+	}
+
+	output, err := p.client.Converse(ctx, input)
+	if err != nil {
+		return Completion{}, fmt.Errorf("Bedrock Converse failed: %w", err)
+	}
+
+	message, ok := output.Output.(*types.ConverseOutputMemberMessage)
+	if !ok {
+		return Completion{}, fmt.Errorf("Bedrock Converse returned no message: %w", errInvalidOutput)
+	}
+
+	completion := Completion{StopReason: string(output.StopReason)}
+	var texts []string
+	for _, block := range message.Value.Content {
+		switch block := block.(type) {
+		case *types.ContentBlockMemberText:
+			texts = append(texts, block.Value)
+		case *types.ContentBlockMemberToolUse:
+			if opts.Tool == nil || aws.ToString(block.Value.Name) != opts.Tool.Name || block.Value.Input == nil {
+				continue
+			}
+			toolInput, err := block.Value.Input.MarshalSmithyDocument()
+			if err != nil {
+				return Completion{}, fmt.Errorf("failed to read tool input: %v: %w", err, errInvalidOutput)
+			}
+			completion.ToolInput = toolInput
+		}
+	}
+	completion.Text = strings.Join(texts, "")
+	if completion.Text == "" && completion.ToolInput == nil {
+		return Completion{}, fmt.Errorf("empty response from Bedrock Converse: %w", errInvalidOutput)
+	}
+
+	if output.Usage != nil {
+		completion.Usage = Usage{
+			InputTokens:  int(aws.ToInt32(output.Usage.InputTokens)),
+			OutputTokens: int(aws.ToInt32(output.Usage.OutputTokens)),
+		}
+	}
+	return completion, nil
+}
+
+// InvokeConversion calls the model provider to convert SQL schema to DynamoDB
+// JSON, with the prompt of PromptVersion and the output language (es or en).
+func InvokeConversion(ctx context.Context, sqlContent, optimizationType, language string, hints []DesignHint) (string, error) {
+	prompt, err := buildPrompt(sqlContent, optimizationType, language, hints, schemaContext{})
+	if err != nil {
+		return "", err
+	}
+	return InvokePrompt(ctx, prompt)
+}
+
+// InvokePrompt sends a rendered prompt to the configured model provider,
+// forcing a call to the design tool, and returns the design JSON once it is
+// validated against the design schema.
+func InvokePrompt(ctx context.Context, prompt string) (string, error) {
+	if provider == nil {
+		return "", fmt.Errorf("model provider not initialized: %w", errNotConfigured)
+	}
+
+	tool := DesignTool()
+	completion, err := provider.Complete(ctx, prompt, CompletionOptions{MaxTokens: defaultMaxTokens, Tool: &tool})
+	if err != nil {
+		return "", err
+	}
+	log.Printf("Model usage: %d input tokens, %d output tokens", completion.Usage.InputTokens, completion.Usage.OutputTokens)
+
+	// A truncated design may still be valid JSON with tables missing
+	if completion.StopReason == StopMaxTokens {
+		return "", fmt.Errorf("response reached the %d token limit: %w", defaultMaxTokens, errTruncated)
+	}
+
+	design := []byte(completion.ToolInput)
+	if len(design) == 0 {
+		// Providers without tool use (fixtures) answer with the JSON as text
+		if design, err = jsonObject(completion.Text); err != nil {
+			return "", err
+		}
+	}
+	if err := validateDesign(design); err != nil {
+		return "", err
+	}
+	return string(design), nil
+}
+
+// MockResponse returns the fixed design of the mock provider.
+func MockResponse() string {
+	mock := map[string]interface{}{
+		"tables": []map[string]interface{}{
+			{
+				"tableName":    "users",
+				"partitionKey": map[string]string{"name": "userId", "type": "S"},
+				"sortKey":      nil,
+				"attributes": []map[string]string{
+					{"name": "userId", "type": "S"},
+					{"name": "email", "type": "S"},
+					{"name": "username", "type": "S"},
+				},
+				"globalSecondaryIndexes": []map[string]interface{}{
+					{
+						"indexName":    "email-index",
+						"partitionKey": map[string]string{"name": "email", "type": "S"},
+						"sortKey":      nil,
+						"projection":   "ALL",
+					},
+				},
+				"billingMode": "PAY_PER_REQUEST",
+			},
+		},
+	}
+	b, _ := json.Marshal(mock)
+	return string(b)
+}
@@ -0,0 +1,354 @@
+package converter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+)
+
+// Chunk is a group of related tables converted in one model call, so the
+// design of a large schema is not truncated by the response token limit.
+type Chunk struct {
+	// Tables are the chunk's tables
+	Tables []Table
+	// SQL holds their CREATE TABLE statements
+	SQL string
+	// References are the tables of other chunks this chunk references
+	References []TableRef
+}
+
+// Table identifies a table of the script by schema and name, as in
+// DesignHint. Tables of different schemas may share a name.
+type Table struct {
+	Schema string
+	Name   string
+}
+
+// Key is schema.name; a table without schema is in public.
+func (t Table) Key() string {
+	schema := t.Schema
+	if schema == "" {
+		schema = "public"
+	}
+	return schema + "." + t.Name
+}
+
+// designedAs reports whether a designed table is this table: the model
+// names it after the table, or schema_table under the prefix schema mapping.
+func (t Table) designedAs(tableName string) bool {
+	return strings.EqualFold(tableName, t.Name) ||
+		(t.Schema != "" && strings.EqualFold(tableName, t.Schema+"_"+t.Name))
+}
+
+// TableRef describes a table of another chunk by its primary key.
+type TableRef struct {
+	Schema     string
+	Table      string
+	PrimaryKey []string
+}
+
+// ChunkOptions configures InvokeChunkedConversion.
+type ChunkOptions struct {
+	OptimizationType string
+	Language         string
+	Hints            []DesignHint
+	// Concurrency above 1 converts chunks in parallel, and each chunk only
+	// knows the keys of the tables it references. Sequential chunks also see
+	// the designs of the earlier ones.
+	Concurrency int
+	// Invoke sends one prompt; InvokePrompt when nil. The worker wraps it
+	// with its retry policy.
+	Invoke func(ctx context.Context, prompt string) (string, error)
+}
+
+// InvokeChunkedConversion converts each chunk in its own model call and
+// merges the partial designs into one {"tables": [...]} document. A table
+// designed by more than one chunk keeps the version of the chunk that owns
+// it; shared tables no chunk owns (single-table designs) are merged.
+func InvokeChunkedConversion(ctx context.Context, chunks []Chunk, opts ChunkOptions) (string, error) {
+	if opts.Invoke == nil {
+		opts.Invoke = InvokePrompt
+	}
+
+	partials := make([][]map[string]interface{}, len(chunks))
+	var err error
+	if opts.Concurrency > 1 {
+		err = convertChunksParallel(ctx, chunks, opts, partials)
+	} else {
+		err = convertChunksSequential(ctx, chunks, opts, partials)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	merged := mergeDesigns(chunks, partials)
+	if len(merged) == 0 {
+		return "", fmt.Errorf("chunked conversion produced no tables: %w", errInvalidOutput)
+	}
+	out, err := json.Marshal(map[string]interface{}{"tables": merged})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal merged design: %w", err)
+	}
+	return string(out), nil
+}
+
+func convertChunksSequential(ctx context.Context, chunks []Chunk, opts ChunkOptions, partials [][]map[string]interface{}) error {
+	var designed []map[string]interface{}
+	for i, chunk := range chunks {
+		tables, err := convertChunk(ctx, i, len(chunks), chunk, opts, designed)
+		if err != nil {
+			return err
+		}
+		partials[i] = tables
+		designed = append(designed, tables...)
+	}
+	return nil
+}
+
+func convertChunksParallel(ctx context.Context, chunks []Chunk, opts ChunkOptions, partials [][]map[string]interface{}) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	sem := make(chan struct{}, opts.Concurrency)
+
+	for i, chunk := range chunks {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, chunk Chunk) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			tables, err := convertChunk(ctx, i, len(chunks), chunk, opts, nil)
+			if err != nil {
+				// Keep the first error, not the cancellations it causes
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+				mu.Unlock()
+				return
+			}
+			partials[i] = tables
+		}(i, chunk)
+	}
+	wg.Wait()
+	return firstErr
+}
+
+// convertChunk invokes the model for one chunk and parses its tables
+func convertChunk(ctx context.Context, i, total int, chunk Chunk, opts ChunkOptions, designed []map[string]interface{}) ([]map[string]interface{}, error) {
+	log.Printf("Converting chunk %d/%d (%d tables)", i+1, total, len(chunk.Tables))
+
+	prompt, err := buildPrompt(chunk.SQL, opts.OptimizationType, opts.Language, chunkHints(opts.Hints, chunk.Tables), chunkContext(chunk, designed))
+	if err != nil {
+		return nil, err
+	}
+	text, err := opts.Invoke(ctx, prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	tables, err := parseDesignTables(text)
+	if err != nil {
+		return nil, fmt.Errorf("chunk %d/%d: %w", i+1, total, err)
+	}
+	return tables, nil
+}
+
+// chunkHints keeps the hints of the chunk's tables, matched by schema and
+// name
+func chunkHints(hints []DesignHint, tables []Table) []DesignHint {
+	keys := make(map[string]bool, len(tables))
+	for _, table := range tables {
+		keys[table.Key()] = true
+	}
+
+	var kept []DesignHint
+	for _, h := range hints {
+		if keys[Table{Schema: h.Schema, Name: h.Table}.Key()] {
+			kept = append(kept, h)
+		}
+	}
+	return kept
+}
+
+// chunkContext describes the tables designed by earlier chunks and the
+// referenced tables of other chunks, so the model links to them by key
+// instead of designing them again.
+func chunkContext(chunk Chunk, designed []map[string]interface{}) schemaContext {
+	var schema schemaContext
+	for _, table := range designed {
+		schema.Designed = append(schema.Designed, summarizeTable(table))
+	}
+	for _, ref := range chunk.References {
+		if !designedTable(designed, Table{Schema: ref.Schema, Name: ref.Table}) {
+			schema.References = append(schema.References, ref)
+		}
+	}
+	return schema
+}
+
+// summarizeTable renders a designed table as one line: name, keys and GSIs
+func summarizeTable(table map[string]interface{}) string {
+	summary := tableName(table)
+	if pk := keyName(table["partitionKey"]); pk != "" {
+		summary += " (PK: " + pk
+		if sk := keyName(table["sortKey"]); sk != "" {
+			summary += ", SK: " + sk
+		}
+		summary += ")"
+	}
+
+	gsis, _ := table["globalSecondaryIndexes"].([]interface{})
+	var names []string
+	for _, gsi := range gsis {
+		if m, ok := gsi.(map[string]interface{}); ok {
+			if name, _ := m["indexName"].(string); name != "" {
+				names = append(names, name)
+			}
+		}
+	}
+	if len(names) > 0 {
+		summary += " GSIs: " + strings.Join(names, ", ")
+	}
+	return summary
+}
+
+// parseDesignTables extracts the tables of a {"tables": [...]} response,
+// tolerating text or markdown around the JSON object.
+func parseDesignTables(text string) ([]map[string]interface{}, error) {
+	raw, err := jsonObject(text)
+	if err != nil {
+		return nil, err
+	}
+
+	var design struct {
+		Tables []map[string]interface{} `json:"tables"`
+	}
+	if err := json.Unmarshal(raw, &design); err != nil {
+		return nil, fmt.Errorf("failed to parse design JSON (truncated response?): %v: %w", err, errInvalidOutput)
+	}
+	for _, table := range design.Tables {
+		if tableName(table) == "" {
+			return nil, fmt.Errorf("design table without tableName: %w", errInvalidOutput)
+		}
+	}
+	return design.Tables, nil
+}
+
+// mergeDesigns reconciles the partial designs: one entry per table name,
+// in chunk order. The version of the chunk that owns the source table wins
+// over other chunks' versions. A table no chunk owns is merged, joining its
+// attributes, indexes and rules, and so is a table that stands for source
+// tables of several schemas (the entity schema mapping).
+func mergeDesigns(chunks []Chunk, partials [][]map[string]interface{}) []map[string]interface{} {
+	var merged []map[string]interface{}
+	index := make(map[string]int)
+	owners := make(map[string]string)
+
+	for i, tables := range partials {
+		for _, table := range tables {
+			name := strings.ToLower(tableName(table))
+			owner := chunks[i].owner(name)
+
+			j, seen := index[name]
+			switch {
+			case !seen:
+				index[name] = len(merged)
+				merged = append(merged, table)
+				owners[name] = owner
+			case owner != "" && owners[name] == "":
+				merged[j] = table
+				owners[name] = owner
+			case owner != "" || owners[name] == "":
+				mergeTable(merged[j], table)
+			}
+		}
+	}
+	return merged
+}
+
+// owner returns the key of the chunk's table a designed table stands for,
+// or "" when it is not one of the chunk's tables.
+func (c Chunk) owner(tableName string) string {
+	for _, table := range c.Tables {
+		if table.designedAs(tableName) {
+			return table.Key()
+		}
+	}
+	return ""
+}
+
+// mergeListFields are the list attributes joined when merging a shared
+// table, with the field that identifies an element ("" compares the whole
+// element)
+var mergeListFields = map[string]string{
+	"attributes":             "name",
+	"globalSecondaryIndexes": "indexName",
+	"validationRules":        "",
+}
+
+// mergeTable appends to dst the list elements of src it does not have
+func mergeTable(dst, src map[string]interface{}) {
+	for field, idField := range mergeListFields {
+		dstList, _ := dst[field].([]interface{})
+		srcList, _ := src[field].([]interface{})
+
+		seen := make(map[string]bool)
+		for _, item := range dstList {
+			seen[elementID(item, idField)] = true
+		}
+		for _, item := range srcList {
+			if id := elementID(item, idField); !seen[id] {
+				seen[id] = true
+				dstList = append(dstList, item)
+			}
+		}
+		if len(dstList) > 0 {
+			dst[field] = dstList
+		}
+	}
+}
+
+// elementID identifies a list element by idField, or by its whole JSON
+func elementID(item interface{}, idField string) string {
+	if m, ok := item.(map[string]interface{}); ok && idField != "" {
+		if id, _ := m[idField].(string); id != "" {
+			return id
+		}
+	}
+	b, _ := json.Marshal(item)
+	return string(b)
+}
+
+func tableName(table map[string]interface{}) string {
+	name, _ := table["tableName"].(string)
+	return name
+}
+
+func keyName(key interface{}) string {
+	if m, ok := key.(map[string]interface{}); ok {
+		name, _ := m["name"].(string)
+		return name
+	}
+	return ""
+}
+
+func designedTable(designed []map[string]interface{}, ref Table) bool {
+	for _, table := range designed {
+		if ref.designedAs(tableName(table)) {
+			return true
+		}
+	}
+	return false
+}
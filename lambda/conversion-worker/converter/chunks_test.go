@@ -0,0 +1,220 @@
+package converter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeInvoke answers each chunk prompt with the design for the first table
+// of responses found in the prompt's SQL, and records the prompts.
+type fakeInvoke struct {
+	mu        sync.Mutex
+	responses map[string]string
+	prompts   []string
+}
+
+func (f *fakeInvoke) invoke(ctx context.Context, prompt string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.prompts = append(f.prompts, prompt)
+
+	sql := prompt[strings.Index(prompt, "SQL Schema:"):]
+	for table, response := range f.responses {
+		if strings.Contains(sql, "CREATE TABLE "+table+" ") {
+			return response, nil
+		}
+	}
+	return "", fmt.Errorf("unexpected prompt")
+}
+
+func TestInvokeChunkedConversion(t *testing.T) {
+	chunks := []Chunk{
+		{Tables: []Table{{Name: "users"}, {Name: "orders"}}, SQL: "CREATE TABLE users (id INT);\nCREATE TABLE orders (id INT);"},
+		{Tables: []Table{{Name: "payments"}}, SQL: "CREATE TABLE payments (id INT);", References: []TableRef{{Table: "orders", PrimaryKey: []string{"id"}}}},
+	}
+	responses := map[string]string{
+		"users": "```json\n" + `{"tables": [
+			{"tableName": "users", "partitionKey": {"name": "userId"}, "attributes": [{"name": "userId"}]},
+			{"tableName": "orders", "partitionKey": {"name": "orderId"}, "globalSecondaryIndexes": [{"indexName": "user-index"}]},
+			{"tableName": "app", "partitionKey": {"name": "PK"}, "attributes": [{"name": "PK"}, {"name": "userId"}]}
+		]}` + "\n```",
+		"payments": `{"tables": [
+			{"tableName": "payments", "partitionKey": {"name": "paymentId"}},
+			{"tableName": "orders", "partitionKey": {"name": "wrong"}},
+			{"tableName": "app", "partitionKey": {"name": "PK"}, "attributes": [{"name": "PK"}, {"name": "paymentId"}]}
+		]}`,
+	}
+
+	for _, concurrency := range []int{1, 2} {
+		t.Run(fmt.Sprintf("concurrencia %d", concurrency), func(t *testing.T) {
+			fake := &fakeInvoke{responses: responses}
+			out, err := InvokeChunkedConversion(context.Background(), chunks, ChunkOptions{
+				OptimizationType: "balanced",
+				Concurrency:      concurrency,
+				Invoke:           fake.invoke,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var design struct {
+				Tables []struct {
+					TableName    string `json:"tableName"`
+					PartitionKey struct {
+						Name string `json:"name"`
+					} `json:"partitionKey"`
+					Attributes []struct {
+						Name string `json:"name"`
+					} `json:"attributes"`
+				} `json:"tables"`
+			}
+			if err := json.Unmarshal([]byte(out), &design); err != nil {
+				t.Fatalf("merged design is not JSON: %v", err)
+			}
+
+			var names []string
+			for _, table := range design.Tables {
+				names = append(names, table.TableName)
+			}
+			if fmt.Sprint(names) != "[users orders app payments]" {
+				t.Fatalf("merged tables = %v", names)
+			}
+			// orders pertenece a la primera parte: su versión gana
+			if design.Tables[1].PartitionKey.Name != "orderId" {
+				t.Errorf("orders redesigned by a non-owner chunk: %+v", design.Tables[1])
+			}
+			// app no pertenece a ninguna parte: se combinan sus atributos
+			if len(design.Tables[2].Attributes) != 3 {
+				t.Errorf("shared table attributes not merged: %+v", design.Tables[2].Attributes)
+			}
+
+			var second string
+			for _, prompt := range fake.prompts {
+				if strings.Contains(prompt, "CREATE TABLE payments") {
+					second = prompt
+				}
+			}
+			if concurrency == 1 && !strings.Contains(second, "- orders (PK: orderId) GSIs: user-index") {
+				t.Errorf("sequential chunk missing earlier designs:\n%s", second)
+			}
+			// En paralelo no hay diseños previos, pero sí las claves de las
+			// tablas referenciadas
+			if concurrency > 1 && !strings.Contains(second, "- orders (PK: id)") {
+				t.Errorf("parallel chunk missing its references:\n%s", second)
+			}
+		})
+	}
+}
+
+// Tablas homónimas de distintos schemas: cada parte recibe solo sus hints y
+// los diseños se combinan según el mapeo de schemas que eligió el modelo
+func TestInvokeChunkedConversion_MultiSchema(t *testing.T) {
+	chunks := []Chunk{
+		{Tables: []Table{{Schema: "billing", Name: "invoices"}}, SQL: "CREATE TABLE billing.invoices (id INT);"},
+		{Tables: []Table{{Schema: "sales", Name: "invoices"}}, SQL: "CREATE TABLE sales.invoices (id INT);"},
+	}
+	hints := []DesignHint{
+		{Type: "TIME_SERIES", Schema: "billing", Table: "invoices", Description: "billing invoices hint"},
+		{Type: "TIME_SERIES", Schema: "sales", Table: "invoices", Description: "sales invoices hint"},
+	}
+
+	tests := []struct {
+		name       string
+		tableNames [2]string
+		wantTables string
+		wantAttrs  int
+	}{
+		{"prefix: una tabla por schema", [2]string{"billing_invoices", "sales_invoices"}, "[billing_invoices sales_invoices]", 1},
+		{"entity: tabla compartida", [2]string{"invoices", "invoices"}, "[invoices]", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeInvoke{responses: map[string]string{
+				"billing.invoices": `{"tables": [{"tableName": "` + tt.tableNames[0] + `", "partitionKey": {"name": "id"}, "attributes": [{"name": "billingId"}]}]}`,
+				"sales.invoices":   `{"tables": [{"tableName": "` + tt.tableNames[1] + `", "partitionKey": {"name": "id"}, "attributes": [{"name": "salesId"}]}]}`,
+			}}
+			out, err := InvokeChunkedConversion(context.Background(), chunks, ChunkOptions{Hints: hints, Invoke: fake.invoke})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for i, prompt := range fake.prompts {
+				own, other := "billing invoices hint", "sales invoices hint"
+				if i == 1 {
+					own, other = other, own
+				}
+				if !strings.Contains(prompt, own) || strings.Contains(prompt, other) {
+					t.Errorf("chunk %d got the wrong hints:\n%s", i, prompt)
+				}
+			}
+
+			var design struct {
+				Tables []struct {
+					TableName  string        `json:"tableName"`
+					Attributes []interface{} `json:"attributes"`
+				} `json:"tables"`
+			}
+			if err := json.Unmarshal([]byte(out), &design); err != nil {
+				t.Fatal(err)
+			}
+			var names []string
+			for _, table := range design.Tables {
+				names = append(names, table.TableName)
+			}
+			if fmt.Sprint(names) != tt.wantTables {
+				t.Fatalf("merged tables = %v, want %s", names, tt.wantTables)
+			}
+			if len(design.Tables[0].Attributes) != tt.wantAttrs {
+				t.Errorf("attributes = %v, want %d", design.Tables[0].Attributes, tt.wantAttrs)
+			}
+		})
+	}
+}
+
+func TestInvokeChunkedConversion_Errors(t *testing.T) {
+	chunks := []Chunk{
+		{Tables: []Table{{Name: "users"}}, SQL: "CREATE TABLE users (id INT);"},
+		{Tables: []Table{{Name: "orders"}}, SQL: "CREATE TABLE orders (id INT);", References: []TableRef{{Table: "users", PrimaryKey: []string{"id"}}}},
+	}
+	throttled := errors.New("throttled")
+
+	tests := []struct {
+		name     string
+		response string
+		err      error
+		wantErr  error
+		wantCode string
+	}{
+		{"respuesta truncada", `{"tables": [{"tableName": "orders", "partitionKey": {"na`, nil, nil, ErrCodeInvalidOutput},
+		{"tabla sin nombre", `{"tables": [{"partitionKey": {"name": "id"}}]}`, nil, nil, ErrCodeInvalidOutput},
+		{"error del modelo", "", throttled, throttled, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			invoke := func(ctx context.Context, prompt string) (string, error) {
+				if strings.Contains(prompt, "CREATE TABLE users") {
+					return `{"tables": [{"tableName": "users", "partitionKey": {"name": "id"}}]}`, nil
+				}
+				return tt.response, tt.err
+			}
+
+			_, err := InvokeChunkedConversion(context.Background(), chunks, ChunkOptions{Invoke: invoke})
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantCode != "" && ClassifyError(err).Code != tt.wantCode {
+				t.Errorf("code = %s, want %s", ClassifyError(err).Code, tt.wantCode)
+			}
+		})
+	}
+}
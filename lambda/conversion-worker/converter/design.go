@@ -0,0 +1,190 @@
+package converter
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Tool is a function the model is forced to call, so its answer arrives as
+// structured input matching InputSchema instead of free text.
+type Tool struct {
+	Name        string
+	Description string
+	// InputSchema is a JSON Schema object
+	InputSchema map[string]interface{}
+}
+
+// DesignToolName is the tool the model calls with the DynamoDB design
+const DesignToolName = "save_dynamodb_design"
+
+// DesignTool describes the DynamoDB design document the worker stores. The
+// tool definition is in English whatever the output language: the prompt
+// templates carry the language.
+func DesignTool() Tool {
+	key := map[string]interface{}{
+		"type":     "object",
+		"required": []string{"name", "type"},
+		"properties": map[string]interface{}{
+			"name": map[string]interface{}{"type": "string"},
+			"type": map[string]interface{}{"type": "string", "enum": attributeTypes},
+		},
+	}
+
+	return Tool{
+		Name:        DesignToolName,
+		Description: "Saves the DynamoDB design that results from converting the SQL schema.",
+		InputSchema: map[string]interface{}{
+			"type":     "object",
+			"required": []string{"tables"},
+			"properties": map[string]interface{}{
+				"tables": map[string]interface{}{
+					"type":     "array",
+					"minItems": 1,
+					"items": map[string]interface{}{
+						"type":     "object",
+						"required": []string{"tableName", "partitionKey", "attributes", "billingMode"},
+						"properties": map[string]interface{}{
+							"tableName":    map[string]interface{}{"type": "string"},
+							"partitionKey": key,
+							"sortKey":      key,
+							"attributes": map[string]interface{}{
+								"type": "array",
+								"items": map[string]interface{}{
+									"type":     "object",
+									"required": []string{"name", "type"},
+									"properties": map[string]interface{}{
+										"name":        map[string]interface{}{"type": "string"},
+										"type":        map[string]interface{}{"type": "string", "enum": attributeTypes},
+										"description": map[string]interface{}{"type": "string"},
+									},
+								},
+							},
+							"globalSecondaryIndexes": map[string]interface{}{
+								"type": "array",
+								"items": map[string]interface{}{
+									"type":     "object",
+									"required": []string{"indexName", "partitionKey", "projection"},
+									"properties": map[string]interface{}{
+										"indexName":    map[string]interface{}{"type": "string"},
+										"partitionKey": key,
+										"sortKey":      key,
+										"projection":   map[string]interface{}{"type": "string", "enum": []string{"ALL", "KEYS_ONLY", "INCLUDE"}},
+									},
+								},
+							},
+							"validationRules": map[string]interface{}{
+								"type": "array",
+								"items": map[string]interface{}{
+									"type":     "object",
+									"required": []string{"attribute", "rule"},
+									"properties": map[string]interface{}{
+										"attribute": map[string]interface{}{"type": "string"},
+										"rule":      map[string]interface{}{"type": "string"},
+									},
+								},
+							},
+							"billingMode": map[string]interface{}{"type": "string", "enum": []string{"PAY_PER_REQUEST", "PROVISIONED"}},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+var attributeTypes = []string{"S", "N", "B"}
+
+type designKey struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// validateDesign checks the model output against the design schema where
+// the rest of the system depends on it: every table has a name and a
+// partition key, and keys and attributes have DynamoDB scalar types.
+func validateDesign(raw []byte) error {
+	var design struct {
+		Tables []struct {
+			TableName    string     `json:"tableName"`
+			PartitionKey *designKey `json:"partitionKey"`
+			SortKey      *designKey `json:"sortKey"`
+			Attributes   []struct {
+				Name string `json:"name"`
+				Type string `json:"type"`
+			} `json:"attributes"`
+			GSIs []struct {
+				IndexName    string     `json:"indexName"`
+				PartitionKey *designKey `json:"partitionKey"`
+				SortKey      *designKey `json:"sortKey"`
+			} `json:"globalSecondaryIndexes"`
+		} `json:"tables"`
+	}
+	if err := json.Unmarshal(raw, &design); err != nil {
+		return fmt.Errorf("design is not valid JSON: %v: %w", err, errInvalidOutput)
+	}
+	if len(design.Tables) == 0 {
+		return fmt.Errorf("design has no tables: %w", errInvalidOutput)
+	}
+
+	var problems []string
+	checkKey := func(path string, key *designKey, required bool) {
+		switch {
+		case key == nil:
+			if required {
+				problems = append(problems, path+" is missing")
+			}
+		case key.Name == "":
+			problems = append(problems, path+".name is empty")
+		case !validAttributeType(key.Type):
+			problems = append(problems, fmt.Sprintf("%s.type %q is not S, N or B", path, key.Type))
+		}
+	}
+
+	for i, table := range design.Tables {
+		path := fmt.Sprintf("tables[%d]", i)
+		if table.TableName == "" {
+			problems = append(problems, path+".tableName is empty")
+		}
+		checkKey(path+".partitionKey", table.PartitionKey, true)
+		checkKey(path+".sortKey", table.SortKey, false)
+		for j, attr := range table.Attributes {
+			if attr.Name == "" || !validAttributeType(attr.Type) {
+				problems = append(problems, fmt.Sprintf("%s.attributes[%d] needs a name and an S, N or B type", path, j))
+			}
+		}
+		for j, gsi := range table.GSIs {
+			gsiPath := fmt.Sprintf("%s.globalSecondaryIndexes[%d]", path, j)
+			if gsi.IndexName == "" {
+				problems = append(problems, gsiPath+".indexName is empty")
+			}
+			checkKey(gsiPath+".partitionKey", gsi.PartitionKey, true)
+			checkKey(gsiPath+".sortKey", gsi.SortKey, false)
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("design does not match the schema: %s: %w", strings.Join(problems, "; "), errInvalidOutput)
+	}
+	return nil
+}
+
+// jsonObject extracts the JSON object of a text answer, tolerating text or
+// markdown around it
+func jsonObject(text string) ([]byte, error) {
+	start := strings.Index(text, "{")
+	end := strings.LastIndex(text, "}")
+	if start == -1 || end < start {
+		return nil, fmt.Errorf("response does not contain a JSON object: %w", errInvalidOutput)
+	}
+	return []byte(text[start : end+1]), nil
+}
+
+func validAttributeType(t string) bool {
+	for _, valid := range attributeTypes {
+		if t == valid {
+			return true
+		}
+	}
+	return false
+}
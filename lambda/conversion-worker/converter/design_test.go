@@ -0,0 +1,109 @@
+package converter
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestValidateDesign(t *testing.T) {
+	tests := []struct {
+		name    string
+		design  string
+		wantErr string
+	}{
+		{"diseño mock", MockResponse(), ""},
+		{"sin sort key", `{"tables": [{"tableName": "t", "partitionKey": {"name": "id", "type": "S"}, "sortKey": null}]}`, ""},
+		{"sin tablas", `{"tables": []}`, "no tables"},
+		{"JSON inválido", `{"tables": [`, "not valid JSON"},
+		{"sin partition key", `{"tables": [{"tableName": "t"}]}`, "tables[0].partitionKey is missing"},
+		{"tipo de clave inválido", `{"tables": [{"tableName": "t", "partitionKey": {"name": "id", "type": "STRING"}}]}`, `tables[0].partitionKey.type "STRING"`},
+		{"atributo sin tipo", `{"tables": [{"tableName": "t", "partitionKey": {"name": "id", "type": "S"}, "attributes": [{"name": "x"}]}]}`, "tables[0].attributes[0]"},
+		{"GSI sin nombre", `{"tables": [{"tableName": "t", "partitionKey": {"name": "id", "type": "S"}, "globalSecondaryIndexes": [{"partitionKey": {"name": "x", "type": "S"}}]}]}`, "globalSecondaryIndexes[0].indexName"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateDesign([]byte(tt.design))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error = %v, want %q", err, tt.wantErr)
+			}
+			if ClassifyError(err).Code != ErrCodeInvalidOutput {
+				t.Errorf("code = %s, want %s", ClassifyError(err).Code, ErrCodeInvalidOutput)
+			}
+		})
+	}
+}
+
+func TestDesignTool_SchemaIsJSON(t *testing.T) {
+	tool := DesignTool()
+	b, err := json.Marshal(tool.InputSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tool.Name != DesignToolName || !strings.Contains(string(b), `"partitionKey"`) {
+		t.Errorf("unexpected tool: %s %s", tool.Name, b)
+	}
+}
+
+// stubProvider answers every prompt with the same completion
+type stubProvider struct {
+	completion Completion
+	opts       CompletionOptions
+}
+
+func (s *stubProvider) ModelID() string { return "stub" }
+
+func (s *stubProvider) Complete(ctx context.Context, prompt string, opts CompletionOptions) (Completion, error) {
+	s.opts = opts
+	return s.completion, nil
+}
+
+func TestInvokePrompt(t *testing.T) {
+	valid := `{"tables": [{"tableName": "t", "partitionKey": {"name": "id", "type": "S"}}]}`
+
+	tests := []struct {
+		name       string
+		completion Completion
+		want       string
+		wantCode   string
+	}{
+		{"llamada a la herramienta", Completion{Text: "Este es el diseño.", ToolInput: json.RawMessage(valid), StopReason: "tool_use"}, valid, ""},
+		{"JSON en texto", Completion{Text: "```json\n" + valid + "\n```", StopReason: "end_turn"}, valid, ""},
+		{"respuesta truncada", Completion{ToolInput: json.RawMessage(valid), StopReason: StopMaxTokens}, "", ErrCodeTruncated},
+		{"diseño inválido", Completion{ToolInput: json.RawMessage(`{"tables": [{"tableName": "t"}]}`)}, "", ErrCodeInvalidOutput},
+		{"texto sin JSON", Completion{Text: "No puedo convertir este esquema."}, "", ErrCodeInvalidOutput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubProvider{completion: tt.completion}
+			SetProvider(stub)
+			t.Cleanup(func() { SetProvider(nil) })
+
+			got, err := InvokePrompt(context.Background(), "prompt")
+			if tt.wantCode != "" {
+				if err == nil || ClassifyError(err).Code != tt.wantCode {
+					t.Fatalf("error = %v, want %s", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("design = %s, want %s", got, tt.want)
+			}
+			if stub.opts.Tool == nil || stub.opts.Tool.Name != DesignToolName {
+				t.Errorf("expected the design tool to be forced, got %+v", stub.opts.Tool)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package converter
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime/types"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+// Machine-readable error codes stored on FAILED conversions
+const (
+	ErrCodeThrottled     = "MODEL_THROTTLED"
+	ErrCodeTimeout       = "MODEL_TIMEOUT"
+	ErrCodeUnavailable   = "MODEL_UNAVAILABLE"
+	ErrCodeValidation    = "MODEL_VALIDATION_ERROR"
+	ErrCodeAccessDenied  = "MODEL_ACCESS_DENIED"
+	ErrCodeModelNotFound = "MODEL_NOT_FOUND"
+	ErrCodeNotConfigured = "MODEL_NOT_CONFIGURED"
+	ErrCodeInvalidOutput = "MODEL_INVALID_OUTPUT"
+	ErrCodeTruncated     = "MODEL_OUTPUT_TRUNCATED"
+	ErrCodeUnknown       = "MODEL_ERROR"
+)
+
+// ModelError is an InvokeConversion error classified for the retry policy.
+type ModelError struct {
+	Code      string
+	Retryable bool
+	Err       error
+}
+
+func (e *ModelError) Error() string { return e.Err.Error() }
+
+func (e *ModelError) Unwrap() error { return e.Err }
+
+// errNotConfigured, errInvalidOutput and errTruncated mark failures of this
+// package that no retry can fix.
+var (
+	errNotConfigured = errors.New("model not configured")
+	errInvalidOutput = errors.New("invalid model output")
+	errTruncated     = errors.New("model output truncated")
+)
+
+// ClassifyError maps an InvokeConversion error to a ModelError. Throttling,
+// timeouts and 5xx are retryable; validation, access and configuration
+// errors are permanent. Unknown errors are retried, so SQS and the DLQ decide.
+func ClassifyError(err error) *ModelError {
+	if err == nil {
+		return nil
+	}
+
+	var modelErr *ModelError
+	if errors.As(err, &modelErr) {
+		return modelErr
+	}
+
+	classify := func(code string, retryable bool) *ModelError {
+		return &ModelError{Code: code, Retryable: retryable, Err: err}
+	}
+
+	var (
+		throttling   *types.ThrottlingException
+		quota        *types.ServiceQuotaExceededException
+		modelTimeout *types.ModelTimeoutException
+		internal     *types.InternalServerException
+		unavailable  *types.ServiceUnavailableException
+		notReady     *types.ModelNotReadyException
+		modelFailure *types.ModelErrorException
+		validation   *types.ValidationException
+		accessDenied *types.AccessDeniedException
+		notFound     *types.ResourceNotFoundException
+		netErr       net.Error
+		responseErr  *smithyhttp.ResponseError
+		statusErr    *HTTPStatusError
+	)
+
+	switch {
+	case errors.Is(err, errNotConfigured):
+		return classify(ErrCodeNotConfigured, false)
+	case errors.Is(err, errInvalidOutput):
+		return classify(ErrCodeInvalidOutput, false)
+	case errors.Is(err, errTruncated):
+		return classify(ErrCodeTruncated, false)
+	case errors.As(err, &throttling), errors.As(err, &quota):
+		return classify(ErrCodeThrottled, true)
+	case errors.As(err, &modelTimeout), errors.Is(err, context.DeadlineExceeded):
+		return classify(ErrCodeTimeout, true)
+	case errors.As(err, &internal), errors.As(err, &unavailable), errors.As(err, &notReady), errors.As(err, &modelFailure):
+		return classify(ErrCodeUnavailable, true)
+	case errors.As(err, &validation):
+		return classify(ErrCodeValidation, false)
+	case errors.As(err, &accessDenied):
+		return classify(ErrCodeAccessDenied, false)
+	case errors.As(err, &notFound):
+		return classify(ErrCodeModelNotFound, false)
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return classify(ErrCodeTimeout, true)
+	case errors.As(err, &responseErr):
+		status := responseErr.HTTPStatusCode()
+		if status == 429 || status >= 500 {
+			return classify(ErrCodeUnavailable, true)
+		}
+		return classify(ErrCodeUnknown, false)
+	case errors.As(err, &statusErr):
+		switch status := statusErr.StatusCode; {
+		case status == 429:
+			return classify(ErrCodeThrottled, true)
+		case status >= 500:
+			return classify(ErrCodeUnavailable, true)
+		case status == 401 || status == 403:
+			return classify(ErrCodeAccessDenied, false)
+		case status == 404:
+			return classify(ErrCodeModelNotFound, false)
+		default:
+			return classify(ErrCodeValidation, false)
+		}
+	default:
+		return classify(ErrCodeUnknown, true)
+	}
+}
@@ -0,0 +1,72 @@
+package converter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime/types"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name          string
+		err           error
+		wantCode      string
+		wantRetryable bool
+	}{
+		{"throttling", &types.ThrottlingException{}, ErrCodeThrottled, true},
+		{"cuota excedida", &types.ServiceQuotaExceededException{}, ErrCodeThrottled, true},
+		{"timeout del modelo", &types.ModelTimeoutException{}, ErrCodeTimeout, true},
+		{"deadline del contexto", context.DeadlineExceeded, ErrCodeTimeout, true},
+		{"error interno 5xx", &types.InternalServerException{}, ErrCodeUnavailable, true},
+		{"servicio no disponible", &types.ServiceUnavailableException{}, ErrCodeUnavailable, true},
+		{"validación", &types.ValidationException{}, ErrCodeValidation, false},
+		{"acceso denegado", &types.AccessDeniedException{}, ErrCodeAccessDenied, false},
+		{"modelo inexistente", &types.ResourceNotFoundException{}, ErrCodeModelNotFound, false},
+		{"sin configurar", fmt.Errorf("BEDROCK_MODEL_ID not set: %w", errNotConfigured), ErrCodeNotConfigured, false},
+		{"respuesta vacía", fmt.Errorf("empty response: %w", errInvalidOutput), ErrCodeInvalidOutput, false},
+		{"respuesta truncada", fmt.Errorf("max_tokens: %w", errTruncated), ErrCodeTruncated, false},
+		{"envuelto por InvokeConversion", fmt.Errorf("Bedrock InvokeModel failed: %w", &types.ThrottlingException{}), ErrCodeThrottled, true},
+		{"HTTP 429", &HTTPStatusError{StatusCode: 429}, ErrCodeThrottled, true},
+		{"HTTP 503", &HTTPStatusError{StatusCode: 503}, ErrCodeUnavailable, true},
+		{"HTTP 401", &HTTPStatusError{StatusCode: 401}, ErrCodeAccessDenied, false},
+		{"HTTP 404", &HTTPStatusError{StatusCode: 404}, ErrCodeModelNotFound, false},
+		{"HTTP 400", &HTTPStatusError{StatusCode: 400}, ErrCodeValidation, false},
+		{"desconocido", errors.New("boom"), ErrCodeUnknown, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ClassifyError(tt.err)
+			if got.Code != tt.wantCode || got.Retryable != tt.wantRetryable {
+				t.Errorf("ClassifyError(%v) = %s/%v, want %s/%v", tt.err, got.Code, got.Retryable, tt.wantCode, tt.wantRetryable)
+			}
+			if !errors.Is(got, tt.err) {
+				t.Errorf("ModelError should wrap the original error")
+			}
+		})
+	}
+}
+
+// Una respuesta de Bedrock que no es JSON falla sin reintentos, como en el
+// proveedor compatible con OpenAI
+func TestClassifyError_MalformedBedrockResponse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"content": [{"type": "tool_use"`))
+	}))
+	defer srv.Close()
+
+	p := &BedrockInvokeProvider{client: newTestBedrockClient(srv), modelID: "model"}
+	_, err := p.Complete(context.Background(), "prompt", CompletionOptions{MaxTokens: 100})
+	if err == nil {
+		t.Fatal("expected an error for a malformed response")
+	}
+	if got := ClassifyError(err); got.Code != ErrCodeInvalidOutput || got.Retryable {
+		t.Errorf("ClassifyError(%v) = %s/%v, want %s/false", err, got.Code, got.Retryable, ErrCodeInvalidOutput)
+	}
+}
@@ -0,0 +1,62 @@
+package converter
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// FixtureProvider replays recorded responses: the answer to a prompt is the
+// file <Dir>/<FixtureKey(prompt)>.txt. Prompts without a fixture get Default,
+// or fail when it is empty. Deterministic and offline, for tests.
+type FixtureProvider struct {
+	Dir     string
+	Default string
+}
+
+// NewFixtureProvider replays the fixtures of dir (FIXTURES_DIR).
+func NewFixtureProvider(dir, fallback string) (*FixtureProvider, error) {
+	if dir == "" && fallback == "" {
+		return nil, fmt.Errorf("FIXTURES_DIR not set: %w", errNotConfigured)
+	}
+	return &FixtureProvider{Dir: dir, Default: fallback}, nil
+}
+
+// NewMockProvider answers every prompt with MockResponse.
+func NewMockProvider() *FixtureProvider {
+	return &FixtureProvider{Default: MockResponse()}
+}
+
+// FixtureKey names the fixture of a prompt: its SHA-256 in hex.
+func FixtureKey(prompt string) string {
+	sum := sha256.Sum256([]byte(prompt))
+	return hex.EncodeToString(sum[:])
+}
+
+// ModelID is "fixture", or "mock" for a provider without fixtures
+func (p *FixtureProvider) ModelID() string {
+	if p.Dir == "" {
+		return ProviderMock
+	}
+	return ProviderFixture
+}
+
+func (p *FixtureProvider) Complete(ctx context.Context, prompt string, opts CompletionOptions) (Completion, error) {
+	if p.Dir != "" {
+		path := filepath.Join(p.Dir, FixtureKey(prompt)+".txt")
+		data, err := os.ReadFile(path)
+		switch {
+		case err == nil:
+			return Completion{Text: string(data)}, nil
+		case !errors.Is(err, os.ErrNotExist):
+			return Completion{}, fmt.Errorf("failed to read fixture: %w", err)
+		case p.Default == "":
+			return Completion{}, fmt.Errorf("no fixture %s for this prompt: %w", path, errNotConfigured)
+		}
+	}
+	return Completion{Text: p.Default}, nil
+}
@@ -0,0 +1,36 @@
+package converter
+
+// DesignHint is a modelling recommendation detected by process_handler while
+// validating the SQL (e.g. self-referencing hierarchies,
+// time-series tables).
+type DesignHint struct {
+	Type           string            `json:"type"`
+	Table          string            `json:"table"`
+	Schema         string            `json:"schema,omitempty"`
+	Columns        []string          `json:"columns,omitempty"`
+	Description    string            `json:"description"`
+	KeyPatterns    []string          `json:"keyPatterns,omitempty"`
+	ExampleQueries []ExampleQuery    `json:"exampleQueries,omitempty"`
+	TTLAttribute   string            `json:"ttlAttribute,omitempty"`
+	RetentionDays  int               `json:"retentionDays,omitempty"`
+	AllowedValues  []string          `json:"allowedValues,omitempty"`
+	Rules          []ValidationRule  `json:"validationRules,omitempty"`
+	Descriptions   map[string]string `json:"attributeDescriptions,omitempty"`
+}
+
+// ValidationRule is an application-side rule derived from a SQL CHECK
+// constraint, since DynamoDB cannot enforce it.
+type ValidationRule struct {
+	Column   string   `json:"column"`
+	Kind     string   `json:"kind"`
+	Operator string   `json:"operator,omitempty"`
+	Values   []string `json:"values,omitempty"`
+	Negated  bool     `json:"negated,omitempty"`
+	Source   string   `json:"source"`
+}
+
+// ExampleQuery documents how to serve an access pattern with the suggested design.
+type ExampleQuery struct {
+	AccessPattern string `json:"accessPattern"`
+	Query         string `json:"query"`
+}
@@ -0,0 +1,150 @@
+package converter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// OpenAIProvider calls an OpenAI-compatible chat completions endpoint, so a
+// local model server (Ollama, vLLM, llama.cpp) can stand in for Bedrock.
+type OpenAIProvider struct {
+	BaseURL string
+	APIKey  string
+	Model   string
+	Client  *http.Client
+}
+
+// NewOpenAIProvider reads OPENAI_BASE_URL (default https://api.openai.com/v1),
+// OPENAI_MODEL and the optional OPENAI_API_KEY.
+func NewOpenAIProvider() (*OpenAIProvider, error) {
+	model := os.Getenv("OPENAI_MODEL")
+	if model == "" {
+		return nil, fmt.Errorf("OPENAI_MODEL not set: %w", errNotConfigured)
+	}
+	baseURL := os.Getenv("OPENAI_BASE_URL")
+	if baseURL == "" {
+		baseURL = "https://api.openai.com/v1"
+	}
+	return &OpenAIProvider{
+		BaseURL: baseURL,
+		APIKey:  os.Getenv("OPENAI_API_KEY"),
+		Model:   model,
+		Client:  http.DefaultClient,
+	}, nil
+}
+
+// HTTPStatusError is a non-2xx answer of an HTTP model endpoint.
+type HTTPStatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *HTTPStatusError) Error() string {
+	return fmt.Sprintf("model endpoint returned HTTP %d: %s", e.StatusCode, e.Body)
+}
+
+func (p *OpenAIProvider) ModelID() string { return p.Model }
+
+func (p *OpenAIProvider) Complete(ctx context.Context, prompt string, opts CompletionOptions) (Completion, error) {
+	request := map[string]interface{}{
+		"model":      p.Model,
+		"max_tokens": opts.MaxTokens,
+		"messages": []map[string]string{
+			{"role": "user", "content": prompt},
+		},
+	}
+	if opts.Tool != nil {
+		request["tools"] = []map[string]interface{}{{
+			"type": "function",
+			"function": map[string]interface{}{
+				"name":        opts.Tool.Name,
+				"description": opts.Tool.Description,
+				"parameters":  opts.Tool.InputSchema,
+			},
+		}}
+		request["tool_choice"] = map[string]interface{}{
+			"type":     "function",
+			"function": map[string]string{"name": opts.Tool.Name},
+		}
+	}
+
+	requestBody, err := json.Marshal(request)
+	if err != nil {
+		return Completion{}, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(p.BaseURL, "/")+"/chat/completions", bytes.NewReader(requestBody))
+	if err != nil {
+		return Completion{}, fmt.Errorf("invalid OPENAI_BASE_URL: %v: %w", err, errNotConfigured)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.APIKey)
+	}
+
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return Completion{}, fmt.Errorf("chat completions request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Completion{}, fmt.Errorf("failed to read chat completions response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return Completion{}, &HTTPStatusError{StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(body))}
+	}
+
+	var response struct {
+		Choices []struct {
+			Message struct {
+				Content   string `json:"content"`
+				ToolCalls []struct {
+					Function struct {
+						Name      string `json:"name"`
+						Arguments string `json:"arguments"`
+					} `json:"function"`
+				} `json:"tool_calls"`
+			} `json:"message"`
+			FinishReason string `json:"finish_reason"`
+		} `json:"choices"`
+		Usage struct {
+			PromptTokens     int `json:"prompt_tokens"`
+			CompletionTokens int `json:"completion_tokens"`
+		} `json:"usage"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return Completion{}, fmt.Errorf("failed to parse chat completions response: %v: %w", err, errInvalidOutput)
+	}
+	if len(response.Choices) == 0 {
+		return Completion{}, fmt.Errorf("empty response from chat completions: %w", errInvalidOutput)
+	}
+
+	choice := response.Choices[0]
+	completion := Completion{
+		Text:       choice.Message.Content,
+		StopReason: choice.FinishReason,
+		Usage:      Usage{InputTokens: response.Usage.PromptTokens, OutputTokens: response.Usage.CompletionTokens},
+	}
+	if completion.StopReason == "length" {
+		completion.StopReason = StopMaxTokens
+	}
+	for _, call := range choice.Message.ToolCalls {
+		// Function arguments arrive as a JSON string
+		if opts.Tool != nil && call.Function.Name == opts.Tool.Name && call.Function.Arguments != "" {
+			completion.ToolInput = json.RawMessage(call.Function.Arguments)
+		}
+	}
+
+	if completion.Text == "" && completion.ToolInput == nil {
+		return Completion{}, fmt.Errorf("empty response from chat completions: %w", errInvalidOutput)
+	}
+	return completion, nil
+}
@@ -0,0 +1,120 @@
+package converter
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"text/template"
+)
+
+// Prompt templates, one directory per version and output language. A version
+// is never edited once released: a change to the prompt is a new version, so
+// a stored conversion can be reproduced with the promptVersion it records.
+// A version supports the languages it has a directory for (v1 only es).
+//
+//	prompts/<version>/<language>/conversion.tmpl           the prompt
+//	prompts/<version>/<language>/context.tmpl              defines "hints" and "context"
+//	prompts/<version>/<language>/optimization/<type>.tmpl  defines "optimization"
+//	prompts/<version>/<language>/examples.tmpl             defines "examples" (few-shot)
+//
+//go:embed prompts
+var promptFS embed.FS
+
+// DefaultPromptVersion is used when PROMPT_VERSION is not set
+const DefaultPromptVersion = "v2"
+
+// DefaultLanguage is the output language when none is requested
+const DefaultLanguage = "es"
+
+// promptFuncs are the functions available to the prompt templates
+var promptFuncs = template.FuncMap{"join": strings.Join}
+
+// PromptVersion returns the prompt template version in use: PROMPT_VERSION,
+// or DefaultPromptVersion.
+func PromptVersion() string {
+	if v := os.Getenv("PROMPT_VERSION"); v != "" {
+		return v
+	}
+	return DefaultPromptVersion
+}
+
+// promptData are the values a prompt template renders
+type promptData struct {
+	SQL              string
+	OptimizationType string
+	Hints            []DesignHint
+	Context          schemaContext
+	ToolName         string
+}
+
+// schemaContext describes the rest of the schema to one call of a chunked
+// conversion: the tables earlier chunks designed, summarized, and the
+// referenced tables other chunks design.
+type schemaContext struct {
+	Designed   []string
+	References []TableRef
+}
+
+// buildPrompt renders the conversion prompt of PromptVersion in language.
+// schema is empty except for the calls of a chunked conversion.
+func buildPrompt(sqlContent, optimizationType, language string, hints []DesignHint, schema schemaContext) (string, error) {
+	if language == "" {
+		language = DefaultLanguage
+	}
+
+	tmpl, err := loadPromptTemplate(PromptVersion(), optimizationType, language)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	err = tmpl.ExecuteTemplate(&b, "conversion.tmpl", promptData{
+		SQL:              sqlContent,
+		OptimizationType: optimizationType,
+		Hints:            hints,
+		Context:          schema,
+		ToolName:         DesignToolName,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to render prompt %s: %w", PromptVersion(), err)
+	}
+	return b.String(), nil
+}
+
+// loadPromptTemplate parses the prompt of a version in a language, with the
+// variant for the optimization type and the examples when the version has
+// them. An unknown optimization type uses the balanced variant.
+func loadPromptTemplate(version, optimizationType, language string) (*template.Template, error) {
+	if _, err := fs.Stat(promptFS, path.Join("prompts", version)); err != nil {
+		return nil, fmt.Errorf("unknown PROMPT_VERSION %q: %w", version, errNotConfigured)
+	}
+	dir := path.Join("prompts", version, language)
+	files := []string{path.Join(dir, "conversion.tmpl"), path.Join(dir, "context.tmpl")}
+	if _, err := fs.Stat(promptFS, files[0]); err != nil {
+		return nil, fmt.Errorf("unsupported output language %q for prompt %s: %w", language, version, errNotConfigured)
+	}
+
+	for _, candidates := range [][]string{
+		{path.Join(dir, "optimization", optimizationType+".tmpl"), path.Join(dir, "optimization", "balanced.tmpl")},
+		{path.Join(dir, "examples.tmpl")},
+	} {
+		for _, file := range candidates {
+			if _, err := fs.Stat(promptFS, file); err == nil {
+				files = append(files, file)
+				break
+			} else if !errors.Is(err, fs.ErrNotExist) {
+				return nil, fmt.Errorf("failed to read prompt template: %w", err)
+			}
+		}
+	}
+
+	tmpl, err := template.New("conversion.tmpl").Funcs(promptFuncs).ParseFS(promptFS, files...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse prompt %s: %w", version, err)
+	}
+	return tmpl, nil
+}
@@ -0,0 +1,118 @@
+package converter
+
+import (
+	"io/fs"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestBuildPrompt(t *testing.T) {
+	tests := []struct {
+		name             string
+		version          string
+		optimizationType string
+		language         string
+		want             []string
+		notWant          []string
+		wantCode         string
+	}{
+		{"v1 sin variantes", "v1", "read_heavy", "es", []string{"Tipo de optimización: read_heavy", "SQL Schema:\nCREATE TABLE t"}, []string{"Prioriza", "Ejemplo:"}, ""},
+		{"v1 solo en español", "v1", "balanced", "en", nil, nil, ErrCodeNotConfigured},
+		{"lecturas", "", "read_heavy", "", []string{"Prioriza las lecturas", "en español", "Correo del cliente"}, nil, ""},
+		{"escrituras en inglés", "", "write_heavy", "en", []string{"Prioritize writes", "in English", "Customer email", "Example:"}, []string{"Prioriza", "Correo del cliente", "Ejemplo:"}, ""},
+		{"tipo desconocido usa balanced", "", "otro", "", []string{"Equilibra lecturas y escrituras"}, nil, ""},
+		{"versión inexistente", "v0", "balanced", "", nil, nil, ErrCodeNotConfigured},
+		{"idioma no soportado", "", "balanced", "fr", nil, nil, ErrCodeNotConfigured},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("PROMPT_VERSION", tt.version)
+			got, err := buildPrompt("CREATE TABLE t (id INT);", tt.optimizationType, tt.language, nil, schemaContext{})
+			if tt.wantCode != "" {
+				if err == nil || ClassifyError(err).Code != tt.wantCode {
+					t.Fatalf("error = %v, want %s", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, s := range tt.want {
+				if !strings.Contains(got, s) {
+					t.Errorf("prompt missing %q:\n%s", s, got)
+				}
+			}
+			for _, s := range tt.notWant {
+				if strings.Contains(got, s) {
+					t.Errorf("prompt should not contain %q:\n%s", s, got)
+				}
+			}
+			if !strings.Contains(got, DesignToolName) {
+				t.Errorf("prompt does not name the design tool")
+			}
+		})
+	}
+}
+
+// Los ejemplos few-shot deben cumplir el mismo esquema que la salida del modelo
+func TestPromptExamplesAreValidDesigns(t *testing.T) {
+	files, err := fs.Glob(promptFS, "prompts/*/*/examples.tmpl")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no examples found: %v", err)
+	}
+	for _, file := range files {
+		t.Run(path.Dir(file), func(t *testing.T) {
+			data, _ := fs.ReadFile(promptFS, file)
+			text := string(data)
+			marker := "Diseño:"
+			if !strings.Contains(text, marker) {
+				marker = "Design:"
+			}
+			design := text[strings.Index(text, marker)+len(marker):]
+			design = strings.TrimSpace(design[:strings.Index(design, "{{end}}")])
+			if err := validateDesign([]byte(design)); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+// Los patrones y el contexto de un fragmento se redactan en el idioma del prompt
+func TestBuildPrompt_HintsAndContextFollowLanguage(t *testing.T) {
+	hints := []DesignHint{{Type: "TIME_SERIES", Table: "events", Description: "Time series", TTLAttribute: "expiresAt", RetentionDays: 30,
+		AllowedValues: []string{"a", "b"}, Descriptions: map[string]string{"status": "estado"}}}
+	chunk := Chunk{Tables: []Table{{Name: "orders"}}, References: []TableRef{{Table: "users", PrimaryKey: []string{"id"}}, {Table: "items", PrimaryKey: []string{"a", "b"}}}}
+	designed := []map[string]interface{}{{"tableName": "users", "partitionKey": map[string]interface{}{"name": "userId"}}}
+
+	tests := []struct {
+		name     string
+		language string
+		want     []string
+		notWant  []string
+	}{
+		{"español", "es", []string{"Patrones detectados", "Valores permitidos", "retención de 30 días", "Entidades ya diseñadas", "- users (PK: userId)", "- items (PK: a, b)"}, []string{"Allowed values", "Referenced tables"}},
+		{"inglés", "en", []string{"Patterns detected", "Allowed values", "30-day retention", "Entities already designed", "- users (PK: userId)", "- items (PK: a, b)"}, []string{"Patrones", "Valores permitidos", "Descripción", "retención", "Entidades", "Tablas referenciadas"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("PROMPT_VERSION", "")
+			got, err := buildPrompt("CREATE TABLE orders (id INT);", "balanced", tt.language, hints, chunkContext(chunk, designed))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, s := range tt.want {
+				if !strings.Contains(got, s) {
+					t.Errorf("prompt missing %q:\n%s", s, got)
+				}
+			}
+			for _, s := range tt.notWant {
+				if strings.Contains(got, s) {
+					t.Errorf("prompt should not contain %q:\n%s", s, got)
+				}
+			}
+		})
+	}
+}
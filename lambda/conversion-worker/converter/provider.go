@@ -0,0 +1,120 @@
+package converter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+)
+
+// ModelProvider sends a rendered prompt to a language model and returns the
+// text of its answer. The conversion code only depends on this interface, so
+// the model behind it is chosen by configuration (MODEL_PROVIDER).
+type ModelProvider interface {
+	Complete(ctx context.Context, prompt string, opts CompletionOptions) (Completion, error)
+	// ModelID identifies the model answering, as recorded on conversions
+	ModelID() string
+}
+
+// CompletionOptions are the generation settings of one call.
+type CompletionOptions struct {
+	MaxTokens int
+	// Tool, when set, is the tool the model must call with its answer
+	Tool *Tool
+}
+
+// Completion is the answer of one call.
+type Completion struct {
+	// Text joins the text blocks of the answer
+	Text string
+	// ToolInput is the JSON input of the call to CompletionOptions.Tool, or
+	// nil when the model answered in text
+	ToolInput json.RawMessage
+	// StopReason is why generation stopped; StopMaxTokens means truncated
+	StopReason string
+	Usage      Usage
+}
+
+// StopMaxTokens is the StopReason of an answer cut at MaxTokens, whatever
+// the provider calls it
+const StopMaxTokens = "max_tokens"
+
+// Usage counts the tokens of one call, when the provider reports them.
+type Usage struct {
+	InputTokens  int
+	OutputTokens int
+}
+
+// Provider names accepted in MODEL_PROVIDER
+const (
+	ProviderBedrock         = "bedrock"
+	ProviderBedrockConverse = "bedrock-converse"
+	ProviderOpenAI          = "openai"
+	ProviderFixture         = "fixture"
+	ProviderMock            = "mock"
+)
+
+// defaultMaxTokens bounds every conversion response
+const defaultMaxTokens = 4096
+
+var provider ModelProvider
+
+// InitProvider configures the provider selected by the environment. On a
+// configuration error it logs a warning and leaves no provider, so each
+// conversion fails with MODEL_NOT_CONFIGURED instead of the process.
+func InitProvider() {
+	p, err := NewProviderFromEnv()
+	if err != nil {
+		log.Printf("WARN: Failed to initialize model provider: %v", err)
+		return
+	}
+	provider = p
+}
+
+// SetProvider replaces the provider used by InvokePrompt; nil unsets it.
+func SetProvider(p ModelProvider) {
+	provider = p
+}
+
+// ModelID returns the model of the configured provider, or "" when there is
+// none.
+func ModelID() string {
+	if provider == nil {
+		return ""
+	}
+	return provider.ModelID()
+}
+
+// NewProviderFromEnv builds the provider named by MODEL_PROVIDER (bedrock by
+// default). USE_MOCK_BEDROCK=true still selects the mock response.
+func NewProviderFromEnv() (ModelProvider, error) {
+	name := os.Getenv("MODEL_PROVIDER")
+	if os.Getenv("USE_MOCK_BEDROCK") == "true" {
+		name = ProviderMock
+	}
+
+	var (
+		p   ModelProvider
+		err error
+	)
+	switch name {
+	case "", ProviderBedrock:
+		p, err = NewBedrockInvokeProvider(context.Background())
+	case ProviderBedrockConverse:
+		p, err = NewBedrockConverseProvider(context.Background())
+	case ProviderOpenAI:
+		p, err = NewOpenAIProvider()
+	case ProviderFixture:
+		p, err = NewFixtureProvider(os.Getenv("FIXTURES_DIR"), "")
+	case ProviderMock:
+		log.Println("Mock model provider enabled")
+		p = NewMockProvider()
+	default:
+		err = fmt.Errorf("unknown MODEL_PROVIDER %q (bedrock, bedrock-converse, openai, fixture, mock): %w", name, errNotConfigured)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
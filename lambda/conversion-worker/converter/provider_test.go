@@ -0,0 +1,260 @@
+package converter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+)
+
+func TestFixtureProvider(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, FixtureKey("grabado")+".txt"), []byte(`{"tables":[]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		provider *FixtureProvider
+		prompt   string
+		want     string
+		wantCode string
+	}{
+		{"prompt grabado", &FixtureProvider{Dir: dir}, "grabado", `{"tables":[]}`, ""},
+		{"prompt sin fixture", &FixtureProvider{Dir: dir}, "otro", "", ErrCodeNotConfigured},
+		{"respuesta por defecto", &FixtureProvider{Dir: dir, Default: "mock"}, "otro", "mock", ""},
+		{"mock", NewMockProvider(), "cualquiera", MockResponse(), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.provider.Complete(context.Background(), tt.prompt, CompletionOptions{})
+			if tt.wantCode != "" {
+				if err == nil || ClassifyError(err).Code != tt.wantCode {
+					t.Fatalf("error = %v, want %s", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Text != tt.want {
+				t.Errorf("text = %q, want %q", got.Text, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpenAIProvider(t *testing.T) {
+	var request struct {
+		Model     string `json:"model"`
+		MaxTokens int    `json:"max_tokens"`
+		Messages  []struct {
+			Role    string `json:"role"`
+			Content string `json:"content"`
+		} `json:"messages"`
+		ToolChoice struct {
+			Function struct {
+				Name string `json:"name"`
+			} `json:"function"`
+		} `json:"tool_choice"`
+	}
+	var auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			http.NotFound(w, r)
+			return
+		}
+		auth = r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&request)
+		w.Write([]byte(`{"choices": [{"message": {"role": "assistant", "content": null, "tool_calls": [{"type": "function", "function": {"name": "save_dynamodb_design", "arguments": "{\"tables\":[]}"}}]}, "finish_reason": "tool_calls"}], "usage": {"prompt_tokens": 12, "completion_tokens": 5}}`))
+	}))
+	defer srv.Close()
+
+	tool := DesignTool()
+	p := &OpenAIProvider{BaseURL: srv.URL + "/v1/", APIKey: "secret", Model: "llama3", Client: srv.Client()}
+	got, err := p.Complete(context.Background(), "prompt", CompletionOptions{MaxTokens: 100, Tool: &tool})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got.ToolInput) != `{"tables":[]}` || got.Usage != (Usage{InputTokens: 12, OutputTokens: 5}) {
+		t.Errorf("completion = %+v", got)
+	}
+	if request.Model != "llama3" || request.MaxTokens != 100 || len(request.Messages) != 1 || request.Messages[0].Content != "prompt" {
+		t.Errorf("request = %+v", request)
+	}
+	if request.ToolChoice.Function.Name != DesignToolName {
+		t.Errorf("tool not forced: %+v", request.ToolChoice)
+	}
+	if auth != "Bearer secret" {
+		t.Errorf("Authorization = %q", auth)
+	}
+}
+
+func TestOpenAIProvider_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		wantCode string
+	}{
+		{"limite de peticiones", http.StatusTooManyRequests, `{"error": "rate limit"}`, ErrCodeThrottled},
+		{"modelo inexistente", http.StatusNotFound, `{"error": "model not found"}`, ErrCodeModelNotFound},
+		{"sin respuestas", http.StatusOK, `{"choices": []}`, ErrCodeInvalidOutput},
+		{"JSON invalido", http.StatusOK, `<html>`, ErrCodeInvalidOutput},
+		{"mensaje vacío", http.StatusOK, `{"choices": [{"message": {"content": ""}, "finish_reason": "stop"}]}`, ErrCodeInvalidOutput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			p := &OpenAIProvider{BaseURL: srv.URL, Model: "m", Client: srv.Client()}
+			_, err := p.Complete(context.Background(), "prompt", CompletionOptions{MaxTokens: 10})
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if code := ClassifyError(err).Code; code != tt.wantCode {
+				t.Errorf("code = %s, want %s (%v)", code, tt.wantCode, err)
+			}
+		})
+	}
+}
+
+// newTestBedrockClient points a Bedrock client at a test server
+func newTestBedrockClient(srv *httptest.Server) *bedrockruntime.Client {
+	cfg := aws.Config{Region: "us-east-1", Credentials: credentials.NewStaticCredentialsProvider("test", "test", ""), HTTPClient: srv.Client()}
+	return bedrockruntime.NewFromConfig(cfg, func(o *bedrockruntime.Options) {
+		o.BaseEndpoint = aws.String(srv.URL)
+	})
+}
+
+func TestBedrockInvokeProvider(t *testing.T) {
+	tests := []struct {
+		name           string
+		response       string
+		wantText       string
+		wantToolInput  string
+		wantStopReason string
+	}{
+		{
+			"texto y llamada a la herramienta",
+			`{"content": [{"type": "text", "text": "Diseño "}, {"type": "text", "text": "listo."}, {"type": "tool_use", "id": "t1", "name": "save_dynamodb_design", "input": {"tables": []}}], "stop_reason": "tool_use", "usage": {"input_tokens": 10, "output_tokens": 3}}`,
+			"Diseño listo.", `{"tables": []}`, "tool_use",
+		},
+		{
+			"truncada en max_tokens",
+			`{"content": [{"type": "tool_use", "name": "save_dynamodb_design", "input": {"tables": [{"tableName": "t"}]}}], "stop_reason": "max_tokens"}`,
+			"", `{"tables": [{"tableName": "t"}]}`, StopMaxTokens,
+		},
+		{
+			"otra herramienta",
+			`{"content": [{"type": "text", "text": "{}"}, {"type": "tool_use", "name": "other", "input": {}}], "stop_reason": "tool_use"}`,
+			"{}", "", "tool_use",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var request map[string]interface{}
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				json.NewDecoder(r.Body).Decode(&request)
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(tt.response))
+			}))
+			defer srv.Close()
+
+			tool := DesignTool()
+			p := &BedrockInvokeProvider{client: newTestBedrockClient(srv), modelID: "model"}
+			got, err := p.Complete(context.Background(), "prompt", CompletionOptions{MaxTokens: 100, Tool: &tool})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Text != tt.wantText || string(got.ToolInput) != tt.wantToolInput || got.StopReason != tt.wantStopReason {
+				t.Errorf("completion = %+v (tool input %s)", got, got.ToolInput)
+			}
+			if choice, _ := request["tool_choice"].(map[string]interface{}); choice["name"] != DesignToolName {
+				t.Errorf("tool not forced: %v", request["tool_choice"])
+			}
+		})
+	}
+}
+
+func TestBedrockConverseProvider(t *testing.T) {
+	var request struct {
+		ToolConfig struct {
+			ToolChoice struct {
+				Tool struct {
+					Name string `json:"name"`
+				} `json:"tool"`
+			} `json:"toolChoice"`
+		} `json:"toolConfig"`
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&request)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"output": {"message": {"role": "assistant", "content": [{"text": "Listo."}, {"toolUse": {"toolUseId": "t1", "name": "save_dynamodb_design", "input": {"tables": []}}}]}}, "stopReason": "tool_use", "usage": {"inputTokens": 10, "outputTokens": 3, "totalTokens": 13}}`))
+	}))
+	defer srv.Close()
+
+	tool := DesignTool()
+	p := &BedrockConverseProvider{client: newTestBedrockClient(srv), modelID: "model"}
+	got, err := p.Complete(context.Background(), "prompt", CompletionOptions{MaxTokens: 100, Tool: &tool})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Text != "Listo." || string(got.ToolInput) != `{"tables":[]}` || got.StopReason != "tool_use" {
+		t.Errorf("completion = %+v (tool input %s)", got, got.ToolInput)
+	}
+	if got.Usage != (Usage{InputTokens: 10, OutputTokens: 3}) {
+		t.Errorf("usage = %+v", got.Usage)
+	}
+	if request.ToolConfig.ToolChoice.Tool.Name != DesignToolName {
+		t.Errorf("tool not forced: %+v", request.ToolConfig)
+	}
+}
+
+func TestNewProviderFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		wantErr bool
+	}{
+		{"mock por USE_MOCK_BEDROCK", map[string]string{"USE_MOCK_BEDROCK": "true", "MODEL_PROVIDER": "openai"}, false},
+		{"openai", map[string]string{"MODEL_PROVIDER": "openai", "OPENAI_MODEL": "llama3"}, false},
+		{"openai sin modelo", map[string]string{"MODEL_PROVIDER": "openai"}, true},
+		{"fixture sin directorio", map[string]string{"MODEL_PROVIDER": "fixture"}, true},
+		{"bedrock sin modelo", map[string]string{"MODEL_PROVIDER": "bedrock"}, true},
+		{"proveedor desconocido", map[string]string{"MODEL_PROVIDER": "gpt"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, name := range []string{"USE_MOCK_BEDROCK", "MODEL_PROVIDER", "OPENAI_MODEL", "FIXTURES_DIR", "BEDROCK_MODEL_ID"} {
+				t.Setenv(name, tt.env[name])
+			}
+			p, err := NewProviderFromEnv()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, errNotConfigured) {
+				t.Errorf("configuration errors must be MODEL_NOT_CONFIGURED: %v", err)
+			}
+			if err == nil && p == nil {
+				t.Error("expected a provider")
+			}
+		})
+	}
+}
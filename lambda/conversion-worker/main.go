@@ -2,64 +2,81 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"log"
+	"sync"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
+
+	"conversion-worker/converter"
+	"conversion-worker/worker"
+	"store"
 )
 
-func handler(ctx context.Context, sqsEvent events.SQSEvent) error {
-	log.Printf("Received %d SQS message(s)", len(sqsEvent.Records))
+// conversions is the schemas table, wired to DynamoDB in main
+var conversions store.ConversionStore
 
-	for _, record := range sqsEvent.Records {
-		if err := processMessage(ctx, record); err != nil {
-			log.Printf("ERROR processing message %s: %v", record.MessageId, err)
-			return err
-		}
-	}
+// defaultBatchConcurrency bounds how many messages of a batch are converted
+// in parallel; WORKER_CONCURRENCY overrides it.
+const defaultBatchConcurrency = 4
 
-	return nil
-}
+// handler processes every record of the batch independently and reports the
+// ones that failed, so SQS only redelivers those (ReportBatchItemFailures).
+func handler(ctx context.Context, sqsEvent events.SQSEvent) (events.SQSEventResponse, error) {
+	log.Printf("Received %d SQS message(s)", len(sqsEvent.Records))
 
-func processMessage(ctx context.Context, record events.SQSMessage) error {
-	var msg SQSMessageBody
-	if err := json.Unmarshal([]byte(record.Body), &msg); err != nil {
-		log.Printf("ERROR: Failed to parse SQS message body: %v", err)
-		return err
-	}
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		failures []events.SQSBatchItemFailure
+	)
+	sem := make(chan struct{}, batchConcurrency())
 
-	log.Printf("[%s] Processing conversion (optimization: %s, tables: %d)",
-		msg.ConversionID, msg.OptimizationType, msg.TablesExtracted)
+	for _, record := range sqsEvent.Records {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(record events.SQSMessage) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
 
-	// Update DynamoDB status to PROCESSING
-	if err := UpdateStatusToProcessing(ctx, msg.ConversionID); err != nil {
-		log.Printf("ERROR: Failed to update status: %v", err)
-		return err
+			if err := processMessage(ctx, record); err != nil {
+				log.Printf("ERROR processing message %s: %v", record.MessageId, err)
+				mu.Lock()
+				failures = append(failures, events.SQSBatchItemFailure{ItemIdentifier: record.MessageId})
+				mu.Unlock()
+			}
+		}(record)
 	}
+	wg.Wait()
 
-	// Invoke Bedrock for conversion
-	result, err := InvokeConversion(ctx, msg.SQLContent, msg.OptimizationType)
-	if err != nil {
-		log.Printf("[%s] Bedrock conversion failed: %v", msg.ConversionID, err)
-		if updateErr := UpdateStatusToFailed(ctx, msg.ConversionID, err.Error()); updateErr != nil {
-			log.Printf("[%s] Failed to update status to FAILED: %v", msg.ConversionID, updateErr)
-		}
-		return nil // Don't retry — already marked as FAILED
+	if len(failures) > 0 {
+		log.Printf("%d of %d message(s) failed and will be retried", len(failures), len(sqsEvent.Records))
 	}
+	return events.SQSEventResponse{BatchItemFailures: failures}, nil
+}
 
-	// Store result in DynamoDB
-	if err := UpdateStatusToCompleted(ctx, msg.ConversionID, result); err != nil {
-		log.Printf("[%s] Failed to update status to COMPLETED: %v", msg.ConversionID, err)
-		return err
-	}
+func batchConcurrency() int {
+	return worker.EnvInt("WORKER_CONCURRENCY", defaultBatchConcurrency)
+}
 
-	log.Printf("[%s] Conversion completed successfully", msg.ConversionID)
-	return nil
+// processMessage converts one record; retryable model errors extend its
+// visibility timeout so SQS redelivers it after a growing backoff.
+func processMessage(ctx context.Context, record events.SQSMessage) error {
+	return worker.Process(ctx, conversions, record.Body, func(ctx context.Context) error {
+		return delayRedelivery(ctx, record.ReceiptHandle, receiveCount(record.Attributes))
+	})
 }
 
 func main() {
-	initDynamoClient()
-	initBedrockClient()
+	dynamoStore, err := store.NewDynamoStoreFromEnv(context.Background())
+	if err != nil {
+		log.Printf("WARN: Failed to initialize DynamoDB store: %v", err)
+	} else {
+		conversions = dynamoStore
+	}
+	initSQSClient()
+	converter.InitProvider()
 	lambda.Start(handler)
 }
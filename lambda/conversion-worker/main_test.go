@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+
+	"conversion-worker/converter"
+	"store"
+)
+
+func sqsRecord(id, body string) events.SQSMessage {
+	return events.SQSMessage{MessageId: id, Body: body}
+}
+
+func messageFor(conversionID string) string {
+	return fmt.Sprintf(`{"conversionId": %q, "sqlContent": "CREATE TABLE t (id INT);", "optimizationType": "balanced", "tablesExtracted": 1}`, conversionID)
+}
+
+// useProvider sets the model provider for one test; nil leaves none
+func useProvider(t *testing.T, p converter.ModelProvider) {
+	t.Helper()
+	converter.SetProvider(p)
+	t.Cleanup(func() { converter.SetProvider(nil) })
+}
+
+func TestHandler_PartialBatchFailures(t *testing.T) {
+	useProvider(t, converter.NewMockProvider())
+	ctx := context.Background()
+
+	memStore := store.NewMemoryStore()
+	conversions = memStore
+	t.Cleanup(func() { conversions = nil })
+
+	pending := store.NewConversion("CREATE TABLE t (id INT);", "balanced", 1, nil)
+	completed := store.NewConversion("CREATE TABLE t (id INT);", "balanced", 1, nil)
+	memStore.Create(ctx, pending)
+	memStore.Create(ctx, completed)
+	memStore.Transition(ctx, completed.ConversionID, store.StatusProcessing)
+	memStore.SaveResult(ctx, completed.ConversionID, `{"tables":[]}`)
+
+	resp, err := handler(ctx, events.SQSEvent{Records: []events.SQSMessage{
+		sqsRecord("msg-ok", messageFor(pending.ConversionID)),
+		sqsRecord("msg-redelivered", messageFor(completed.ConversionID)),
+		sqsRecord("msg-unknown", messageFor("missing")),
+		sqsRecord("msg-invalid", `{not json`),
+	}})
+	if err != nil {
+		t.Fatalf("handler returned error: %v", err)
+	}
+
+	var failed []string
+	for _, f := range resp.BatchItemFailures {
+		failed = append(failed, f.ItemIdentifier)
+	}
+	sort.Strings(failed)
+	if fmt.Sprint(failed) != "[msg-invalid msg-unknown]" {
+		t.Errorf("unexpected batch item failures: %v", failed)
+	}
+
+	got, _ := memStore.Get(ctx, pending.ConversionID)
+	if got.Status != store.StatusCompleted {
+		t.Errorf("expected the valid message to complete, got %s", got.Status)
+	}
+	if got.PromptVersion != converter.DefaultPromptVersion || got.ModelID != "mock" {
+		t.Errorf("expected the prompt version and model to be recorded, got %q %q", got.PromptVersion, got.ModelID)
+	}
+	got, _ = memStore.Get(ctx, completed.ConversionID)
+	if got.Version != 3 {
+		t.Errorf("redelivered message modified a completed conversion: %+v", got)
+	}
+}
+
+func TestHandler_ReusesCachedModelResponse(t *testing.T) {
+	// Sin proveedor: invocar el modelo fallaría
+	useProvider(t, nil)
+	ctx := context.Background()
+
+	memStore := store.NewMemoryStore()
+	conversions = memStore
+	t.Cleanup(func() { conversions = nil })
+
+	// Intento anterior: obtuvo la respuesta pero no llegó a completar
+	c := store.NewConversion("CREATE TABLE t (id INT);", "balanced", 1, nil)
+	memStore.Create(ctx, c)
+	memStore.Transition(ctx, c.ConversionID, store.StatusProcessing)
+	memStore.SaveModelResponse(ctx, c.ConversionID, `{"tables":[]}`, store.ModelInfo{})
+
+	resp, err := handler(ctx, events.SQSEvent{Records: []events.SQSMessage{
+		sqsRecord("msg-retry", messageFor(c.ConversionID)),
+	}})
+	if err != nil || len(resp.BatchItemFailures) != 0 {
+		t.Fatalf("unexpected result: %v %+v", err, resp)
+	}
+
+	got, _ := memStore.Get(ctx, c.ConversionID)
+	if got.Status != store.StatusCompleted || got.NoSQLSchema != `{"tables":[]}` {
+		t.Errorf("expected the cached response to complete the conversion, got %+v", got)
+	}
+	if got.Attempts != 2 {
+		t.Errorf("attempts = %d, want 2", got.Attempts)
+	}
+}
+
+func TestHandler_PermanentModelErrorFailsConversion(t *testing.T) {
+	// Sin proveedor de modelo: error de configuración, no se reintenta
+	useProvider(t, nil)
+	ctx := context.Background()
+
+	memStore := store.NewMemoryStore()
+	conversions = memStore
+	t.Cleanup(func() { conversions = nil })
+
+	c := store.NewConversion("CREATE TABLE t (id INT);", "balanced", 1, nil)
+	memStore.Create(ctx, c)
+
+	resp, err := handler(ctx, events.SQSEvent{Records: []events.SQSMessage{
+		sqsRecord("msg-1", messageFor(c.ConversionID)),
+	}})
+	if err != nil || len(resp.BatchItemFailures) != 0 {
+		t.Fatalf("permanent errors must not be redelivered: %v %+v", err, resp)
+	}
+
+	got, _ := memStore.Get(ctx, c.ConversionID)
+	if got.Status != store.StatusFailed || got.ErrorCode != converter.ErrCodeNotConfigured {
+		t.Errorf("expected FAILED with %s, got %s %s", converter.ErrCodeNotConfigured, got.Status, got.ErrorCode)
+	}
+}
+
+func TestHandler_ResolvesClaimCheck(t *testing.T) {
+	useProvider(t, converter.NewMockProvider())
+	ctx := context.Background()
+
+	memStore := store.NewMemoryStore()
+	conversions = memStore
+	t.Cleanup(func() { conversions = nil })
+
+	resolved := store.NewConversion("CREATE TABLE t (id INT);", "balanced", 1, nil)
+	unresolved := store.NewConversion("", "balanced", 1, nil)
+	memStore.Create(ctx, resolved)
+	memStore.Create(ctx, unresolved)
+
+	// El SQL grande viaja como referencia; el store ya lo resolvió al leer
+	refMessage := func(id string) string {
+		return fmt.Sprintf(`{"conversionId": %q, "sqlContentRef": "s3://bucket/conversions/%s/sqlContent", "optimizationType": "balanced"}`, id, id)
+	}
+
+	resp, err := handler(ctx, events.SQSEvent{Records: []events.SQSMessage{
+		sqsRecord("msg-resolved", refMessage(resolved.ConversionID)),
+		sqsRecord("msg-unresolved", refMessage(unresolved.ConversionID)),
+	}})
+	if err != nil {
+		t.Fatalf("handler returned error: %v", err)
+	}
+	if len(resp.BatchItemFailures) != 1 || resp.BatchItemFailures[0].ItemIdentifier != "msg-unresolved" {
+		t.Errorf("unexpected batch item failures: %+v", resp.BatchItemFailures)
+	}
+
+	if got, _ := memStore.Get(ctx, resolved.ConversionID); got.Status != store.StatusCompleted {
+		t.Errorf("expected the resolved message to complete, got %s", got.Status)
+	}
+}
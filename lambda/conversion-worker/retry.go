@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+
+	"conversion-worker/worker"
+)
+
+// Once the in-process retries of the worker package are exhausted, the
+// message goes back to SQS with a growing visibility timeout.
+const (
+	baseVisibilityDelay  = 30 * time.Second
+	maxVisibilityTimeout = 15 * time.Minute
+)
+
+var sqsClient *sqs.Client
+
+func initSQSClient() {
+	cfg, err := config.LoadDefaultConfig(context.Background())
+	if err != nil {
+		log.Printf("WARN: Failed to load AWS config for SQS: %v", err)
+		return
+	}
+
+	endpoint := os.Getenv("SQS_ENDPOINT")
+	if endpoint != "" {
+		sqsClient = sqs.NewFromConfig(cfg, func(o *sqs.Options) {
+			o.BaseEndpoint = aws.String(endpoint)
+		})
+	} else {
+		sqsClient = sqs.NewFromConfig(cfg)
+	}
+}
+
+// delayRedelivery extends the visibility timeout of a message that failed
+// with a retryable error, so SQS redelivers it after a backoff that grows
+// with the receive count instead of the queue's fixed timeout.
+func delayRedelivery(ctx context.Context, receiptHandle string, receiveCount int) error {
+	queueURL := os.Getenv("SQS_QUEUE_URL")
+	if queueURL == "" {
+		return fmt.Errorf("SQS_QUEUE_URL not set")
+	}
+	if sqsClient == nil {
+		return fmt.Errorf("SQS client not initialized")
+	}
+
+	delay := baseVisibilityDelay/2 + worker.JitteredBackoff(baseVisibilityDelay, maxVisibilityTimeout, receiveCount)
+	if delay > maxVisibilityTimeout {
+		delay = maxVisibilityTimeout
+	}
+
+	_, err := sqsClient.ChangeMessageVisibility(ctx, &sqs.ChangeMessageVisibilityInput{
+		QueueUrl:          aws.String(queueURL),
+		ReceiptHandle:     aws.String(receiptHandle),
+		VisibilityTimeout: int32(delay.Seconds()),
+	})
+	if err != nil {
+		return fmt.Errorf("SQS ChangeMessageVisibility failed: %w", err)
+	}
+	return nil
+}
+
+// receiveCount returns how many times SQS delivered the message
+func receiveCount(record map[string]string) int {
+	n, err := strconv.Atoi(record["ApproximateReceiveCount"])
+	if err != nil || n < 1 {
+		return 1
+	}
+	return n
+}
@@ -0,0 +1,115 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"conversion-worker/converter"
+	"diagrams/sqlschema"
+	"store"
+)
+
+// Chunked conversion: schemas with more tables than CHUNK_MAX_TABLES are
+// converted in groups of related tables, since one response (max_tokens
+// 4096) cannot hold the design of a large schema. CHUNK_CONCURRENCY above 1
+// converts the groups in parallel, at the cost of the shared designs.
+const (
+	defaultChunkMaxTables   = 12
+	defaultChunkConcurrency = 1
+)
+
+// convert invokes the model for the whole schema, or chunk by chunk for a
+// large one. The returned error is always a *converter.ModelError.
+func convert(ctx context.Context, msg store.QueueMessage, hints []converter.DesignHint) (string, error) {
+	chunks := schemaChunks(msg.SQLContent, EnvInt("CHUNK_MAX_TABLES", defaultChunkMaxTables))
+	if len(chunks) <= 1 {
+		return invokeWithRetry(ctx, msg.ConversionID, func() (string, error) {
+			return converter.InvokeConversion(ctx, msg.SQLContent, msg.OptimizationType, msg.OutputLanguage, hints)
+		})
+	}
+
+	log.Printf("[%s] Large schema: converting in %d chunks", msg.ConversionID, len(chunks))
+	result, err := converter.InvokeChunkedConversion(ctx, chunks, converter.ChunkOptions{
+		OptimizationType: msg.OptimizationType,
+		Language:         msg.OutputLanguage,
+		Hints:            hints,
+		Concurrency:      EnvInt("CHUNK_CONCURRENCY", defaultChunkConcurrency),
+		Invoke: func(ctx context.Context, prompt string) (string, error) {
+			return invokeWithRetry(ctx, msg.ConversionID, func() (string, error) {
+				return converter.InvokePrompt(ctx, prompt)
+			})
+		},
+	})
+	if err != nil {
+		return "", converter.ClassifyError(err)
+	}
+	return result, nil
+}
+
+// schemaChunks splits the schema into groups of at most maxTables tables
+// related by foreign keys. It returns nil when the schema fits one call or
+// cannot be parsed, and the whole SQL is converted at once.
+func schemaChunks(sqlContent string, maxTables int) []converter.Chunk {
+	result := sqlschema.ValidateSQL(sqlContent)
+	if !result.IsValid || len(result.Tables) <= maxTables {
+		return nil
+	}
+
+	clusters := sqlschema.ClusterTables(result.Tables, maxTables)
+	chunkOf := make(map[string]int)
+	for c, cluster := range clusters {
+		for _, table := range cluster {
+			chunkOf[table.QualifiedName()] = c
+		}
+	}
+
+	chunks := make([]converter.Chunk, len(clusters))
+	for c, cluster := range clusters {
+		var statements []string
+		referenced := make(map[string]bool)
+
+		for _, table := range cluster {
+			chunks[c].Tables = append(chunks[c].Tables, converter.Table{Schema: table.Schema, Name: table.Name})
+			statements = append(statements, table.Statement)
+
+			for _, fk := range table.ForeignKeys {
+				ref, ok := findTable(result.Tables, fk.RefSchema, table.Schema, fk.RefTable)
+				if !ok || chunkOf[ref.QualifiedName()] == c || referenced[ref.QualifiedName()] {
+					continue
+				}
+				referenced[ref.QualifiedName()] = true
+				chunks[c].References = append(chunks[c].References, converter.TableRef{
+					Schema:     ref.Schema,
+					Table:      ref.Name,
+					PrimaryKey: ref.PrimaryKey,
+				})
+			}
+		}
+		chunks[c].SQL = strings.Join(statements, "\n\n")
+	}
+	return chunks
+}
+
+// findTable resolves a foreign key target: its schema, the referencing
+// table's schema, or any schema
+func findTable(tables []sqlschema.TableInfo, refSchema, schema, name string) (sqlschema.TableInfo, bool) {
+	for _, candidate := range []string{refSchema, schema, ""} {
+		for _, table := range tables {
+			if table.Name == name && (candidate == "" || table.Schema == candidate) {
+				return table, true
+			}
+		}
+	}
+	return sqlschema.TableInfo{}, false
+}
+
+// EnvInt reads a positive integer setting, or fallback when unset or invalid
+func EnvInt(name string, fallback int) int {
+	if n, err := strconv.Atoi(os.Getenv(name)); err == nil && n > 0 {
+		return n
+	}
+	return fallback
+}
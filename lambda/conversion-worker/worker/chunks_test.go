@@ -0,0 +1,69 @@
+package worker
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSchemaChunks(t *testing.T) {
+	var large strings.Builder
+	large.WriteString("CREATE TABLE users (id INT PRIMARY KEY);\n")
+	for i := 0; i < 4; i++ {
+		fmt.Fprintf(&large, "CREATE TABLE orders_%d (id INT PRIMARY KEY, user_id INT REFERENCES users(id));\n", i)
+	}
+
+	tests := []struct {
+		name       string
+		sql        string
+		maxTables  int
+		wantChunks int
+	}{
+		{"esquema chico en una llamada", "CREATE TABLE t (id INT);", 3, 0},
+		{"SQL inválido en una llamada", "CREATE TABLE (", 3, 0},
+		{"esquema grande por partes", large.String(), 3, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chunks := schemaChunks(tt.sql, tt.maxTables)
+			if len(chunks) != tt.wantChunks {
+				t.Fatalf("got %d chunks, want %d: %+v", len(chunks), tt.wantChunks, chunks)
+			}
+			if len(chunks) == 0 {
+				return
+			}
+
+			// La segunda parte referencia users, diseñada en la primera
+			second := chunks[1]
+			if len(second.References) != 1 || second.References[0].Table != "users" || fmt.Sprint(second.References[0].PrimaryKey) != "[id]" {
+				t.Errorf("unexpected references: %+v", second.References)
+			}
+			if !strings.Contains(second.SQL, "CREATE TABLE orders_2") || strings.Contains(second.SQL, "CREATE TABLE users") {
+				t.Errorf("unexpected chunk SQL:\n%s", second.SQL)
+			}
+		})
+	}
+}
+
+// Las tablas de cada parte conservan su schema, para repartir los hints de
+// tablas homónimas
+func TestSchemaChunks_MultiSchema(t *testing.T) {
+	chunks := schemaChunks(`
+		CREATE TABLE billing.invoices (id INT PRIMARY KEY);
+		CREATE TABLE sales.invoices (id INT PRIMARY KEY);
+	`, 1)
+	if len(chunks) != 2 {
+		t.Fatalf("got %d chunks, want 2: %+v", len(chunks), chunks)
+	}
+
+	var keys []string
+	for _, chunk := range chunks {
+		for _, table := range chunk.Tables {
+			keys = append(keys, table.Key())
+		}
+	}
+	if fmt.Sprint(keys) != "[billing.invoices sales.invoices]" {
+		t.Errorf("chunk tables = %v", keys)
+	}
+}
@@ -0,0 +1,125 @@
+// Package worker processes one conversion queue message: the record-level
+// logic shared by the conversion worker Lambda and the devserver.
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+
+	"conversion-worker/converter"
+	"store"
+)
+
+// Process converts the conversion a queue message refers to. A nil error
+// acknowledges the message; an error leaves it for the queue to redeliver,
+// after redeliver (when set) has scheduled the retry of a retryable model
+// error.
+func Process(ctx context.Context, conversions store.ConversionStore, body string, redeliver func(ctx context.Context) error) error {
+	var msg store.QueueMessage
+	if err := json.Unmarshal([]byte(body), &msg); err != nil {
+		log.Printf("ERROR: Failed to parse SQS message body: %v", err)
+		return err
+	}
+	var hints []converter.DesignHint
+	if len(msg.DesignHints) > 0 {
+		if err := json.Unmarshal(msg.DesignHints, &hints); err != nil {
+			log.Printf("[%s] ERROR: Failed to parse design hints: %v", msg.ConversionID, err)
+			return err
+		}
+	}
+
+	log.Printf("[%s] Processing conversion (optimization: %s, tables: %d)",
+		msg.ConversionID, msg.OptimizationType, msg.TablesExtracted)
+
+	if conversions == nil {
+		return fmt.Errorf("conversion store not initialized")
+	}
+
+	// Idempotency: a redelivered message for a conversion that already
+	// finished (or was cancelled) is acknowledged without work.
+	current, err := conversions.Get(ctx, msg.ConversionID)
+	if err != nil {
+		log.Printf("ERROR: Failed to read conversion: %v", err)
+		return err
+	}
+	if store.IsTerminal(current.Status) {
+		log.Printf("[%s] Skipping message: conversion is already %s", msg.ConversionID, current.Status)
+		return nil
+	}
+
+	// Claim check: large SQL travels as a reference, resolved by the store
+	if msg.SQLContentRef != "" {
+		if current.SQLContent == "" {
+			return fmt.Errorf("SQL content %s not resolved (is PAYLOAD_BUCKET set?)", msg.SQLContentRef)
+		}
+		msg.SQLContent = current.SQLContent
+	}
+
+	// Update DynamoDB status to PROCESSING (counts the attempt)
+	err = conversions.Transition(ctx, msg.ConversionID, store.StatusProcessing)
+	if errors.Is(err, store.ErrInvalidTransition) {
+		log.Printf("[%s] Skipping message: %v", msg.ConversionID, err)
+		return nil
+	}
+	if err != nil {
+		log.Printf("ERROR: Failed to update status: %v", err)
+		return err
+	}
+
+	// Reuse the response a previous attempt got before failing to complete
+	result := current.ModelResponse
+	if result != "" {
+		log.Printf("[%s] Reusing model response cached by a previous attempt", msg.ConversionID)
+	} else {
+		// Invoke Bedrock for conversion (by chunks for large schemas),
+		// retrying transient errors
+		result, err = convert(ctx, msg, hints)
+		if err != nil {
+			modelErr := converter.ClassifyError(err)
+			if modelErr.Retryable {
+				// Leave it PROCESSING: the queue redelivers it after the
+				// backoff, and the DLQ handler fails it after maxReceiveCount.
+				log.Printf("[%s] Bedrock conversion failed with retryable error (%s): %v", msg.ConversionID, modelErr.Code, err)
+				if recErr := conversions.RecordError(ctx, msg.ConversionID, modelErr.Code, err.Error()); recErr != nil {
+					log.Printf("[%s] WARN: Failed to record last error: %v", msg.ConversionID, recErr)
+				}
+				if redeliver != nil {
+					if visErr := redeliver(ctx); visErr != nil {
+						log.Printf("[%s] WARN: Failed to delay redelivery: %v", msg.ConversionID, visErr)
+					}
+				}
+				return err
+			}
+
+			log.Printf("[%s] Bedrock conversion failed permanently (%s): %v", msg.ConversionID, modelErr.Code, err)
+			if updateErr := conversions.Fail(ctx, msg.ConversionID, modelErr.Code, err.Error(), nil); updateErr != nil {
+				log.Printf("[%s] Failed to update status to FAILED: %v", msg.ConversionID, updateErr)
+			}
+			return nil // Don't retry — already marked as FAILED
+		}
+
+		model := store.ModelInfo{PromptVersion: converter.PromptVersion(), ModelID: converter.ModelID()}
+		if err := conversions.SaveModelResponse(ctx, msg.ConversionID, result, model); err != nil {
+			// Non-blocking: only a retry would need the cache
+			log.Printf("[%s] WARN: Failed to cache model response: %v", msg.ConversionID, err)
+		}
+	}
+
+	// Store result in DynamoDB
+	err = conversions.SaveResult(ctx, msg.ConversionID, result)
+	if errors.Is(err, store.ErrInvalidTransition) {
+		// Cancelled or finished by another delivery while converting
+		log.Printf("[%s] Discarding result: %v", msg.ConversionID, err)
+		return nil
+	}
+	if err != nil {
+		log.Printf("[%s] Failed to update status to COMPLETED: %v", msg.ConversionID, err)
+		return err
+	}
+
+	log.Printf("[%s] Conversion completed successfully", msg.ConversionID)
+	return nil
+}
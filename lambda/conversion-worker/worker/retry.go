@@ -0,0 +1,60 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"math/rand"
+	"time"
+
+	"conversion-worker/converter"
+)
+
+// Retry policy for retryable model errors: a few in-process attempts with
+// jittered exponential backoff while the deadline allows; after that the
+// caller's queue redelivers the message.
+const (
+	maxInvokeAttempts = 3
+	baseInvokeBackoff = 1 * time.Second
+	maxInvokeBackoff  = 8 * time.Second
+	deadlineReserve   = 15 * time.Second // kept to persist the result
+)
+
+// invokeWithRetry calls the model and retries retryable errors in process.
+// The returned error is always a *converter.ModelError.
+func invokeWithRetry(ctx context.Context, conversionID string, invoke func() (string, error)) (string, error) {
+	for attempt := 1; ; attempt++ {
+		result, err := invoke()
+		if err == nil {
+			return result, nil
+		}
+
+		modelErr := converter.ClassifyError(err)
+		if !modelErr.Retryable || attempt >= maxInvokeAttempts {
+			return "", modelErr
+		}
+
+		wait := JitteredBackoff(baseInvokeBackoff, maxInvokeBackoff, attempt)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait+deadlineReserve {
+			return "", modelErr
+		}
+
+		log.Printf("[%s] Retryable model error (%s), attempt %d/%d, retrying in %s: %v",
+			conversionID, modelErr.Code, attempt, maxInvokeAttempts, wait, err)
+
+		select {
+		case <-ctx.Done():
+			return "", converter.ClassifyError(ctx.Err())
+		case <-time.After(wait):
+		}
+	}
+}
+
+// JitteredBackoff returns a random delay in [0, min(max, base*2^(attempt-1))]
+// ("full jitter"), so throttled workers do not retry in lockstep.
+func JitteredBackoff(base, max time.Duration, attempt int) time.Duration {
+	ceiling := base << (attempt - 1)
+	if ceiling > max || ceiling <= 0 {
+		ceiling = max
+	}
+	return time.Duration(rand.Int63n(int64(ceiling) + 1))
+}
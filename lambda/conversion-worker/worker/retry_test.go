@@ -0,0 +1,11 @@
+package worker
+
+import "testing"
+
+func TestJitteredBackoff(t *testing.T) {
+	for attempt := 1; attempt <= 10; attempt++ {
+		if d := JitteredBackoff(baseInvokeBackoff, maxInvokeBackoff, attempt); d < 0 || d > maxInvokeBackoff {
+			t.Errorf("attempt %d: backoff %s out of [0, %s]", attempt, d, maxInvokeBackoff)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+
+	"redrive"
+)
+
+// redriver moves dead-lettered conversions back to the conversion queue;
+// main wires it when the SQS client and the store are available.
+var redriver *redrive.Redriver
+
+// RedriveRequest es el body esperado en POST /api/v1/admin/dlq/redrive
+type RedriveRequest struct {
+	ConversionIDs []string `json:"conversionIds,omitempty"`
+	All           bool     `json:"all,omitempty"`
+	DryRun        bool     `json:"dryRun,omitempty"`
+	MaxMessages   int      `json:"maxMessages,omitempty"`
+}
+
+// handleAdmin serves the DLQ admin routes. They are disabled unless
+// ADMIN_TOKEN is set, and require it as a bearer token.
+func handleAdmin(ctx context.Context, req events.APIGatewayV2HTTPRequest) (V2Response, error) {
+	token := os.Getenv("ADMIN_TOKEN")
+	if token == "" {
+		return jsonResponse(404, ErrorResponse{Error: "NOT_FOUND", Message: "Route not found"})
+	}
+	if !authorized(req.Headers, token) {
+		return jsonResponse(401, ErrorResponse{Error: ErrUnauthorized, Message: "Missing or invalid admin token"})
+	}
+	if redriver == nil {
+		log.Printf("ERROR: DLQ admin route called without the SQS client or the store")
+		return jsonResponse(500, ErrorResponse{Error: ErrInternalServerError, Message: "DLQ redrive is not configured"})
+	}
+
+	method := req.RequestContext.HTTP.Method
+	path := req.RequestContext.HTTP.Path
+
+	// GET /api/v1/admin/dlq -> listar conversiones que agotaron sus reintentos
+	if method == "GET" && strings.HasSuffix(path, "/admin/dlq") {
+		maxMessages, _ := strconv.Atoi(req.QueryStringParameters["max"])
+		messages, err := redriver.List(ctx, maxMessages)
+		if err != nil {
+			log.Printf("ERROR: Failed to list dead-lettered conversions: %v", err)
+			return jsonResponse(500, ErrorResponse{Error: ErrInternalServerError, Message: "Failed to list dead-lettered conversions"})
+		}
+		if messages == nil {
+			messages = []redrive.Message{}
+		}
+		return jsonResponse(200, map[string]interface{}{
+			"messages": messages,
+			"count":    len(messages),
+		})
+	}
+
+	// POST /api/v1/admin/dlq/redrive -> reencolar conversiones seleccionadas o todas
+	if method == "POST" && strings.HasSuffix(path, "/admin/dlq/redrive") {
+		var body RedriveRequest
+		if err := json.Unmarshal([]byte(req.Body), &body); err != nil {
+			return jsonResponse(400, ErrorResponse{Error: ErrInvalidJSON, Message: "Request body is not valid JSON"})
+		}
+		if !body.All && len(body.ConversionIDs) == 0 {
+			return jsonResponse(400, ErrorResponse{
+				Error:   ErrInvalidRedriveRequest,
+				Message: "Set conversionIds or all: true",
+			})
+		}
+
+		results, err := redriver.Redrive(ctx, redrive.Options{
+			ConversionIDs: body.ConversionIDs,
+			All:           body.All,
+			DryRun:        body.DryRun,
+			MaxMessages:   body.MaxMessages,
+		})
+		if err != nil {
+			log.Printf("ERROR: DLQ redrive failed: %v", err)
+			return jsonResponse(500, ErrorResponse{Error: ErrInternalServerError, Message: "Failed to redrive conversions"})
+		}
+		if results == nil {
+			results = []redrive.Result{}
+		}
+		return jsonResponse(200, map[string]interface{}{
+			"dryRun":  body.DryRun,
+			"results": results,
+		})
+	}
+
+	return jsonResponse(404, ErrorResponse{Error: "NOT_FOUND", Message: "Route not found"})
+}
+
+// authorized checks the bearer token in constant time. API Gateway HTTP APIs
+// lowercase header names.
+func authorized(headers map[string]string, token string) bool {
+	auth := headers["authorization"]
+	if auth == "" {
+		auth = headers["Authorization"]
+	}
+	given, ok := strings.CutPrefix(auth, "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(given), []byte(token)) == 1
+}
+
+func initRedriver() {
+	if sqsClient == nil || conversions == nil {
+		return
+	}
+	redriver = redrive.New(sqsClient, conversions, os.Getenv("SQS_QUEUE_URL"))
+}
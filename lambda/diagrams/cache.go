@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log"
+	"os"
+	"strconv"
+
+	"diagrams/sqlschema"
+	"store"
+)
+
+// contentHash identifies equivalent conversion requests: the normalized SQL
+// plus every setting that changes the model input (prompt version included)
+// or the model itself.
+// Design hints derive from the SQL, retentionDays and schemaMapping.
+func contentHash(body ConvertRequest) string {
+	h := sha256.New()
+	for _, part := range []string{
+		sqlschema.NormalizeSQL(body.SQLContent),
+		body.OptimizationType,
+		strconv.Itoa(body.RetentionDays),
+		body.SchemaMapping,
+		body.OutputLanguage,
+		os.Getenv("PROMPT_VERSION"),
+		os.Getenv("MODEL_PROVIDER"),
+		os.Getenv("BEDROCK_MODEL_ID"),
+	} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// findCachedResult returns a live COMPLETED conversion of the same content,
+// or nil. Lookup errors only cost the cache hit.
+func findCachedResult(ctx context.Context, hash string) *store.Conversion {
+	if conversions == nil {
+		return nil
+	}
+
+	cached, err := conversions.FindCompleted(ctx, hash)
+	if err != nil {
+		if !errors.Is(err, store.ErrNotFound) {
+			log.Printf("WARN: Result cache lookup failed: %v", err)
+		}
+		return nil
+	}
+	return cached
+}
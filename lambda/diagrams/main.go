@@ -3,13 +3,20 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log"
 	"strings"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
+
+	"diagrams/sqlschema"
+	"store"
 )
 
+// conversions is the schemas table; main wires DynamoDB, tests a MemoryStore
+var conversions store.ConversionStore
+
 func handler(ctx context.Context, req events.APIGatewayV2HTTPRequest) (V2Response, error) {
 	method := req.RequestContext.HTTP.Method
 	path := req.RequestContext.HTTP.Path
@@ -20,6 +27,11 @@ func handler(ctx context.Context, req events.APIGatewayV2HTTPRequest) (V2Respons
 		return handleValidateSQL(ctx, req)
 	}
 
+	// /api/v1/admin/dlq... -> inspeccionar y reencolar las conversiones agotadas
+	if strings.Contains(path, "/api/v1/admin/") {
+		return handleAdmin(ctx, req)
+	}
+
 	// GET /api/v1/schemas -> health / info
 	if method == "GET" && strings.HasSuffix(path, "/api/v1/schemas") {
 		return jsonResponse(200, map[string]string{
@@ -47,13 +59,13 @@ func handleValidateSQL(ctx context.Context, req events.APIGatewayV2HTTPRequest)
 	// 2. Validar sqlContent no vacio
 	if strings.TrimSpace(body.SQLContent) == "" {
 		return jsonResponse(400, ErrorResponse{
-			Error:   ErrEmptySQLContent,
+			Error:   sqlschema.ErrEmptySQLContent,
 			Message: "Field sqlContent is required",
 		})
 	}
 
 	// 3. Validar optimizationType si se envia
-	if body.OptimizationType != "" && !validOptimizationTypes[body.OptimizationType] {
+	if body.OptimizationType != "" && !sqlschema.ValidOptimizationTypes[body.OptimizationType] {
 		return jsonResponse(400, ErrorResponse{
 			Error:   ErrInvalidOptimizationType,
 			Message: "Invalid optimization type. Valid values: read_heavy, write_heavy, balanced",
@@ -64,20 +76,65 @@ func handleValidateSQL(ctx context.Context, req events.APIGatewayV2HTTPRequest)
 		body.OptimizationType = "balanced"
 	}
 
+	// 3b. Validar retentionDays si se envia (aplica a tablas time-series)
+	if body.RetentionDays < 0 || body.RetentionDays > sqlschema.MaxRetentionDays {
+		return jsonResponse(400, ErrorResponse{
+			Error:   ErrInvalidRetentionPeriod,
+			Message: fmt.Sprintf("Invalid retentionDays. Must be between 1 and %d, or 0 to omit it", sqlschema.MaxRetentionDays),
+		})
+	}
+
+	// 3c. Validar schemaMapping si se envia (scripts con varios schemas)
+	if body.SchemaMapping != "" && !sqlschema.ValidSchemaMappings[body.SchemaMapping] {
+		return jsonResponse(400, ErrorResponse{
+			Error:   ErrInvalidSchemaMapping,
+			Message: "Invalid schema mapping. Valid values: prefix, entity",
+		})
+	}
+
+	// 3d. Validar outputLanguage si se envia (idioma de descripciones y reglas)
+	if body.OutputLanguage != "" && !sqlschema.ValidOutputLanguages[body.OutputLanguage] {
+		return jsonResponse(400, ErrorResponse{
+			Error:   ErrInvalidOutputLanguage,
+			Message: "Invalid output language. Valid values: es, en",
+		})
+	}
+
+	if body.OutputLanguage == "" {
+		body.OutputLanguage = sqlschema.DefaultOutputLanguage
+	}
+
 	// 4. Ejecutar validacion SQL
-	result := ValidateSQL(body.SQLContent)
+	result := sqlschema.ValidateSQL(body.SQLContent)
 
 	if !result.IsValid {
 		return jsonResponse(400, ErrorResponse{
-			Error:   ErrInvalidSQLSyntax,
+			Error:   sqlschema.ErrInvalidSQLSyntax,
 			Message: result.Errors[0].Message,
 			Details: result.Errors,
 		})
 	}
 
+	sqlschema.ApplyRetentionPeriod(result.Hints, body.RetentionDays)
+	sqlschema.ApplySchemaMapping(&result, body.SchemaMapping)
+
 	// 5. Schema valido -> crear registro PENDING en DynamoDB
-	record, err := CreateConversionRecord(ctx, body.SQLContent, body.OptimizationType, len(result.Tables))
-	if err != nil {
+	var hintsJSON []byte
+	if len(result.Hints) > 0 {
+		hintsJSON, _ = json.Marshal(result.Hints)
+	}
+	record := store.NewConversion(body.SQLContent, body.OptimizationType, len(result.Tables), hintsJSON)
+	record.OutputLanguage = body.OutputLanguage
+	record.ContentHash = contentHash(body)
+
+	// 5b. Mismo contenido ya convertido -> el registro nace COMPLETED
+	cached := findCachedResult(ctx, record.ContentHash)
+	if cached != nil {
+		record.ReuseResult(cached)
+		log.Printf("[%s] Result cache hit (cachedFrom: %s)", record.ConversionID, cached.ConversionID)
+	}
+
+	if err := createConversion(ctx, record); err != nil {
 		log.Printf("ERROR: Failed to create DynamoDB record: %v", err)
 		return jsonResponse(500, ErrorResponse{
 			Error:   ErrInternalServerError,
@@ -86,17 +143,30 @@ func handleValidateSQL(ctx context.Context, req events.APIGatewayV2HTTPRequest)
 	}
 
 	// 6. Send to SQS for async processing (non-blocking)
-	if err := SendToQueue(ctx, record); err != nil {
-		log.Printf("WARN: Failed to send to SQS (non-blocking): %v", err)
+	if cached == nil {
+		if err := SendToQueue(ctx, record); err != nil {
+			log.Printf("WARN: Failed to send to SQS (non-blocking): %v", err)
+		}
 	}
 
 	// 7. Retornar 202 Accepted
-	return jsonResponse(202, map[string]interface{}{
+	response := map[string]interface{}{
 		"conversionId": record.ConversionID,
 		"status":       record.Status,
 		"createdAt":    record.CreatedAt,
 		"expiresAt":    record.ExpiresAt,
-	})
+		"cacheHit":     cached != nil,
+	}
+	if cached != nil {
+		response["cachedFrom"] = cached.ConversionID
+	}
+	if len(result.Warnings) > 0 {
+		response["warnings"] = result.Warnings
+	}
+	if len(result.Hints) > 0 {
+		response["designHints"] = result.Hints
+	}
+	return jsonResponse(202, response)
 }
 
 func jsonResponse(statusCode int, body interface{}) (V2Response, error) {
@@ -110,8 +180,22 @@ func jsonResponse(statusCode int, body interface{}) (V2Response, error) {
 	}, nil
 }
 
+// createConversion stores the PENDING record
+func createConversion(ctx context.Context, record *store.Conversion) error {
+	if conversions == nil {
+		return fmt.Errorf("conversion store not initialized")
+	}
+	return conversions.Create(ctx, record)
+}
+
 func main() {
-	initDynamoClient()
+	dynamoStore, err := store.NewDynamoStoreFromEnv(context.Background())
+	if err != nil {
+		log.Printf("WARN: Failed to initialize DynamoDB store: %v", err)
+	} else {
+		conversions = dynamoStore
+	}
 	initSQSClient()
+	initRedriver()
 	lambda.Start(handler)
 }
@@ -6,6 +6,10 @@ import (
 	"testing"
 
 	"github.com/aws/aws-lambda-go/events"
+
+	"diagrams/sqlschema"
+	"redrive"
+	"store"
 )
 
 func v2Request(method, path, body string) events.APIGatewayV2HTTPRequest {
@@ -41,6 +45,10 @@ func TestHandler_GET_Schemas(t *testing.T) {
 }
 
 func TestHandler_POST_ValidSQL(t *testing.T) {
+	memStore := store.NewMemoryStore()
+	conversions = memStore
+	t.Cleanup(func() { conversions = nil })
+
 	body, _ := json.Marshal(ConvertRequest{
 		SQLContent: `CREATE TABLE users (
 			id SERIAL PRIMARY KEY,
@@ -54,14 +62,91 @@ func TestHandler_POST_ValidSQL(t *testing.T) {
 	if err != nil {
 		t.Fatalf("handler returned error: %v", err)
 	}
-	if resp.StatusCode != 200 {
-		t.Fatalf("expected 200, got %d: %s", resp.StatusCode, resp.Body)
+	if resp.StatusCode != 202 {
+		t.Fatalf("expected 202, got %d: %s", resp.StatusCode, resp.Body)
 	}
 
 	var result map[string]interface{}
 	json.Unmarshal([]byte(resp.Body), &result)
-	if result["isValid"] != true {
-		t.Fatalf("expected isValid=true, got %v", result["isValid"])
+	if result["status"] != store.StatusPending {
+		t.Fatalf("expected status PENDING, got %v", result["status"])
+	}
+
+	record, err := memStore.Get(context.Background(), result["conversionId"].(string))
+	if err != nil {
+		t.Fatalf("conversion was not stored: %v", err)
+	}
+	if record.TablesExtracted != 1 || record.OptimizationType != "balanced" {
+		t.Fatalf("unexpected stored conversion: %+v", record)
+	}
+}
+
+func TestHandler_POST_CacheHit(t *testing.T) {
+	ctx := context.Background()
+	memStore := store.NewMemoryStore()
+	conversions = memStore
+	t.Cleanup(func() { conversions = nil })
+
+	submit := func(sql, optimizationType string) map[string]interface{} {
+		body, _ := json.Marshal(ConvertRequest{SQLContent: sql, OptimizationType: optimizationType})
+		resp, _ := handler(ctx, v2Request("POST", "/api/v1/schemas", string(body)))
+		if resp.StatusCode != 202 {
+			t.Fatalf("expected 202, got %d: %s", resp.StatusCode, resp.Body)
+		}
+		var result map[string]interface{}
+		json.Unmarshal([]byte(resp.Body), &result)
+		return result
+	}
+
+	first := submit("CREATE TABLE users (id INT PRIMARY KEY);", "balanced")
+	if first["cacheHit"] != false {
+		t.Fatalf("first submission reported a cache hit: %v", first)
+	}
+	firstID := first["conversionId"].(string)
+	memStore.Transition(ctx, firstID, store.StatusProcessing)
+	memStore.SaveResult(ctx, firstID, `{"tables":[]}`)
+
+	tests := []struct {
+		name             string
+		sql              string
+		optimizationType string
+		wantHit          bool
+	}{
+		{"mismo SQL con otro formato", "-- usuarios\ncreate table users(\n  id int primary key\n);", "balanced", true},
+		{"otro tipo de optimización", "CREATE TABLE users (id INT PRIMARY KEY);", "read_heavy", false},
+		{"otro SQL", "CREATE TABLE users (id BIGINT PRIMARY KEY);", "balanced", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := submit(tt.sql, tt.optimizationType)
+			if result["cacheHit"] != tt.wantHit {
+				t.Fatalf("cacheHit = %v, want %v", result["cacheHit"], tt.wantHit)
+			}
+			if !tt.wantHit {
+				return
+			}
+
+			record, _ := memStore.Get(ctx, result["conversionId"].(string))
+			if record.Status != store.StatusCompleted || record.CachedFrom != firstID || record.NoSQLSchema != `{"tables":[]}` {
+				t.Errorf("unexpected cached conversion: %+v", record)
+			}
+			if result["status"] != store.StatusCompleted || result["cachedFrom"] != firstID {
+				t.Errorf("unexpected response: %v", result)
+			}
+		})
+	}
+}
+
+func TestHandler_POST_StoreUnavailable(t *testing.T) {
+	body, _ := json.Marshal(ConvertRequest{SQLContent: "CREATE TABLE t (id INT);"})
+
+	resp, err := handler(context.Background(), v2Request("POST", "/api/v1/schemas", string(body)))
+	if err != nil {
+		t.Fatalf("handler returned error: %v", err)
+	}
+	if resp.StatusCode != 500 {
+		t.Fatalf("expected 500 without a store, got %d", resp.StatusCode)
 	}
 }
 
@@ -80,8 +165,8 @@ func TestHandler_POST_InvalidSQL(t *testing.T) {
 
 	var errResp ErrorResponse
 	json.Unmarshal([]byte(resp.Body), &errResp)
-	if errResp.Error != ErrInvalidSQLSyntax {
-		t.Fatalf("expected error %s, got %s", ErrInvalidSQLSyntax, errResp.Error)
+	if errResp.Error != sqlschema.ErrInvalidSQLSyntax {
+		t.Fatalf("expected error %s, got %s", sqlschema.ErrInvalidSQLSyntax, errResp.Error)
 	}
 }
 
@@ -98,8 +183,8 @@ func TestHandler_POST_EmptyBody(t *testing.T) {
 
 	var errResp ErrorResponse
 	json.Unmarshal([]byte(resp.Body), &errResp)
-	if errResp.Error != ErrEmptySQLContent {
-		t.Fatalf("expected error %s, got %s", ErrEmptySQLContent, errResp.Error)
+	if errResp.Error != sqlschema.ErrEmptySQLContent {
+		t.Fatalf("expected error %s, got %s", sqlschema.ErrEmptySQLContent, errResp.Error)
 	}
 }
 
@@ -124,6 +209,69 @@ func TestHandler_POST_InvalidOptimizationType(t *testing.T) {
 	}
 }
 
+func TestHandler_POST_InvalidRetentionDays(t *testing.T) {
+	body, _ := json.Marshal(ConvertRequest{
+		SQLContent:    "CREATE TABLE t (id INT);",
+		RetentionDays: -1,
+	})
+
+	resp, err := handler(context.Background(), v2Request("POST", "/api/v1/schemas", string(body)))
+	if err != nil {
+		t.Fatalf("handler returned error: %v", err)
+	}
+	if resp.StatusCode != 400 {
+		t.Fatalf("expected 400, got %d", resp.StatusCode)
+	}
+
+	var errResp ErrorResponse
+	json.Unmarshal([]byte(resp.Body), &errResp)
+	if errResp.Error != ErrInvalidRetentionPeriod {
+		t.Fatalf("expected error %s, got %s", ErrInvalidRetentionPeriod, errResp.Error)
+	}
+}
+
+func TestHandler_POST_InvalidSchemaMapping(t *testing.T) {
+	body, _ := json.Marshal(ConvertRequest{
+		SQLContent:    "CREATE TABLE t (id INT);",
+		SchemaMapping: "suffix",
+	})
+
+	resp, err := handler(context.Background(), v2Request("POST", "/api/v1/schemas", string(body)))
+	if err != nil {
+		t.Fatalf("handler returned error: %v", err)
+	}
+	if resp.StatusCode != 400 {
+		t.Fatalf("expected 400, got %d", resp.StatusCode)
+	}
+
+	var errResp ErrorResponse
+	json.Unmarshal([]byte(resp.Body), &errResp)
+	if errResp.Error != ErrInvalidSchemaMapping {
+		t.Fatalf("expected error %s, got %s", ErrInvalidSchemaMapping, errResp.Error)
+	}
+}
+
+func TestHandler_POST_InvalidOutputLanguage(t *testing.T) {
+	body, _ := json.Marshal(ConvertRequest{
+		SQLContent:     "CREATE TABLE t (id INT);",
+		OutputLanguage: "fr",
+	})
+
+	resp, err := handler(context.Background(), v2Request("POST", "/api/v1/schemas", string(body)))
+	if err != nil {
+		t.Fatalf("handler returned error: %v", err)
+	}
+	if resp.StatusCode != 400 {
+		t.Fatalf("expected 400, got %d", resp.StatusCode)
+	}
+
+	var errResp ErrorResponse
+	json.Unmarshal([]byte(resp.Body), &errResp)
+	if errResp.Error != ErrInvalidOutputLanguage {
+		t.Fatalf("expected error %s, got %s", ErrInvalidOutputLanguage, errResp.Error)
+	}
+}
+
 func TestHandler_POST_InvalidJSON(t *testing.T) {
 	resp, err := handler(context.Background(), v2Request("POST", "/api/v1/schemas", "not json"))
 	if err != nil {
@@ -133,3 +281,49 @@ func TestHandler_POST_InvalidJSON(t *testing.T) {
 		t.Fatalf("expected 400, got %d", resp.StatusCode)
 	}
 }
+
+func TestHandler_AdminDLQ(t *testing.T) {
+	redriver = redrive.New(nil, store.NewMemoryStore(), "queue-url")
+	t.Cleanup(func() { redriver = nil })
+
+	tests := []struct {
+		name       string
+		token      string
+		header     string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		wantError  string
+	}{
+		{"deshabilitado sin ADMIN_TOKEN", "", "Bearer secret", "GET", "/api/v1/admin/dlq", "", 404, "NOT_FOUND"},
+		{"sin token", "secret", "", "GET", "/api/v1/admin/dlq", "", 401, ErrUnauthorized},
+		{"token incorrecto", "secret", "Bearer other", "GET", "/api/v1/admin/dlq", "", 401, ErrUnauthorized},
+		{"redrive sin selección", "secret", "Bearer secret", "POST", "/api/v1/admin/dlq/redrive", `{"dryRun":true}`, 400, ErrInvalidRedriveRequest},
+		{"redrive con JSON inválido", "secret", "Bearer secret", "POST", "/api/v1/admin/dlq/redrive", "not json", 400, ErrInvalidJSON},
+		{"ruta inexistente", "secret", "Bearer secret", "DELETE", "/api/v1/admin/dlq", "", 404, "NOT_FOUND"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("ADMIN_TOKEN", tt.token)
+
+			req := v2Request(tt.method, tt.path, tt.body)
+			req.Headers = map[string]string{"authorization": tt.header}
+
+			resp, err := handler(context.Background(), req)
+			if err != nil {
+				t.Fatalf("handler returned error: %v", err)
+			}
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("expected %d, got %d: %s", tt.wantStatus, resp.StatusCode, resp.Body)
+			}
+
+			var errResp ErrorResponse
+			json.Unmarshal([]byte(resp.Body), &errResp)
+			if errResp.Error != tt.wantError {
+				t.Errorf("expected error %s, got %s", tt.wantError, errResp.Error)
+			}
+		})
+	}
+}
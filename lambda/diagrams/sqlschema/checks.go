@@ -0,0 +1,278 @@
+package sqlschema
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ============================================================================
+// PARSING DE CHECK CONSTRAINTS
+// ============================================================================
+
+// Tipos de regla de validacion derivadas de un CHECK
+const (
+	RuleComparison = "COMPARISON" // price >= 0
+	RuleIn         = "IN"         // status IN ('a', 'b')
+	RuleBetween    = "BETWEEN"    // rating BETWEEN 1 AND 5
+	RuleLike       = "LIKE"       // code LIKE 'AB%'
+	RuleRegex      = "REGEX"      // email ~ '^.+@.+$'
+	RuleLength     = "LENGTH"     // length(code) = 3
+	RuleNotNull    = "NOT_NULL"   // col IS NOT NULL
+)
+
+const checkOperand = `(-?\d+(?:\.\d+)?|'(?:[^']|'')*'|TRUE|FALSE)(?:::[\w ]+)?`
+const checkColumn = `("(?:[^"]|"")+"|[A-Za-z_][\w$]*)`
+
+var (
+	comparisonRuleRegex        = regexp.MustCompile(`(?is)^` + checkColumn + `\s*(>=|<=|<>|!=|=|>|<)\s*` + checkOperand + `$`)
+	reversedComparisonRegex    = regexp.MustCompile(`(?is)^` + checkOperand + `\s*(>=|<=|<>|!=|=|>|<)\s*` + checkColumn + `$`)
+	inRuleRegex                = regexp.MustCompile(`(?is)^` + checkColumn + `\s+(NOT\s+)?IN\s*\((.*)\)$`)
+	betweenRuleRegex           = regexp.MustCompile(`(?is)^` + checkColumn + `\s+(NOT\s+)?BETWEEN\s+` + checkOperand + `\s+AND\s+` + checkOperand + `$`)
+	likeRuleRegex              = regexp.MustCompile(`(?is)^` + checkColumn + `\s+(NOT\s+)?(I?LIKE)\s+` + checkOperand + `$`)
+	regexRuleRegex             = regexp.MustCompile(`(?is)^` + checkColumn + `\s*(!?~\*?)\s*` + checkOperand + `$`)
+	lengthRuleRegex            = regexp.MustCompile(`(?is)^(?:length|char_length|character_length)\s*\(\s*` + checkColumn + `\s*\)\s*(>=|<=|<>|!=|=|>|<)\s*(\d+)$`)
+	notNullRuleRegex           = regexp.MustCompile(`(?is)^` + checkColumn + `\s+IS\s+NOT\s+NULL$`)
+	checkListValueRegex        = regexp.MustCompile(checkOperand)
+	checkKeywordRegex          = regexp.MustCompile(`(?i)\bCHECK\s*\(`)
+	reversedComparisonOperator = map[string]string{">": "<", "<": ">", ">=": "<=", "<=": ">=", "=": "=", "<>": "<>", "!=": "!="}
+)
+
+// extractCheckExpressions obtiene las expresiones de cada CHECK (...) de un
+// elemento de tabla, respetando parentesis anidados y strings.
+func extractCheckExpressions(elem string) []string {
+	var exprs []string
+
+	for _, loc := range checkKeywordRegex.FindAllStringIndex(elem, -1) {
+		start := loc[1] // posicion despues de "("
+		depth := 1
+		inQuotes := false
+
+		for i := start; i < len(elem); i++ {
+			switch elem[i] {
+			case '\'':
+				inQuotes = !inQuotes
+			case '(':
+				if !inQuotes {
+					depth++
+				}
+			case ')':
+				if !inQuotes {
+					depth--
+				}
+			}
+			if depth == 0 {
+				exprs = append(exprs, strings.TrimSpace(elem[start:i]))
+				break
+			}
+		}
+	}
+
+	return exprs
+}
+
+// parseCheckExpression convierte una expresion CHECK en reglas de validacion.
+// Solo soporta conjunciones (AND) de comparaciones simples, IN, BETWEEN, LIKE,
+// ~ y length(); cualquier otra cosa (OR, funciones, varias columnas) retorna ok=false.
+func parseCheckExpression(expr string) ([]ValidationRule, bool) {
+	var rules []ValidationRule
+
+	for _, part := range splitCheckConjunction(stripOuterParens(expr)) {
+		rule, ok := parseCheckCondition(stripOuterParens(part))
+		if !ok {
+			return nil, false
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, len(rules) > 0
+}
+
+func parseCheckCondition(cond string) (ValidationRule, bool) {
+	rule := ValidationRule{Source: cond}
+
+	switch {
+	case betweenRuleRegex.MatchString(cond):
+		m := betweenRuleRegex.FindStringSubmatch(cond)
+		rule.Column, rule.Kind = foldIdentifier(m[1]), RuleBetween
+		rule.Negated = m[2] != ""
+		rule.Values = []string{cleanCheckLiteral(m[3]), cleanCheckLiteral(m[4])}
+	case inRuleRegex.MatchString(cond):
+		m := inRuleRegex.FindStringSubmatch(cond)
+		rule.Column, rule.Kind = foldIdentifier(m[1]), RuleIn
+		rule.Negated = m[2] != ""
+		for _, v := range checkListValueRegex.FindAllString(m[3], -1) {
+			rule.Values = append(rule.Values, cleanCheckLiteral(v))
+		}
+		if len(rule.Values) == 0 {
+			return rule, false
+		}
+	case likeRuleRegex.MatchString(cond):
+		m := likeRuleRegex.FindStringSubmatch(cond)
+		rule.Column, rule.Kind = foldIdentifier(m[1]), RuleLike
+		rule.Negated = m[2] != ""
+		rule.Operator = strings.ToUpper(m[3])
+		rule.Values = []string{cleanCheckLiteral(m[4])}
+	case lengthRuleRegex.MatchString(cond):
+		m := lengthRuleRegex.FindStringSubmatch(cond)
+		rule.Column, rule.Kind = foldIdentifier(m[1]), RuleLength
+		rule.Operator = m[2]
+		rule.Values = []string{m[3]}
+	case notNullRuleRegex.MatchString(cond):
+		m := notNullRuleRegex.FindStringSubmatch(cond)
+		rule.Column, rule.Kind = foldIdentifier(m[1]), RuleNotNull
+	case comparisonRuleRegex.MatchString(cond):
+		m := comparisonRuleRegex.FindStringSubmatch(cond)
+		rule.Column, rule.Kind = foldIdentifier(m[1]), RuleComparison
+		rule.Operator = m[2]
+		rule.Values = []string{cleanCheckLiteral(m[3])}
+	case reversedComparisonRegex.MatchString(cond):
+		// 0 <= price -> price >= 0
+		m := reversedComparisonRegex.FindStringSubmatch(cond)
+		rule.Column, rule.Kind = foldIdentifier(m[3]), RuleComparison
+		rule.Operator = reversedComparisonOperator[m[2]]
+		rule.Values = []string{cleanCheckLiteral(m[1])}
+	case regexRuleRegex.MatchString(cond):
+		m := regexRuleRegex.FindStringSubmatch(cond)
+		rule.Column, rule.Kind = foldIdentifier(m[1]), RuleRegex
+		rule.Operator = m[2]
+		rule.Values = []string{cleanCheckLiteral(m[3])}
+	default:
+		return rule, false
+	}
+
+	// VALUE solo existe en CHECK de dominios, no es una columna
+	if strings.EqualFold(rule.Column, "value") {
+		return rule, false
+	}
+
+	return rule, true
+}
+
+// splitCheckConjunction separa por AND a nivel superior. El AND de un BETWEEN
+// no separa, y un OR a nivel superior deja la expresion como una sola parte
+// (no soportada).
+func splitCheckConjunction(expr string) []string {
+	var parts []string
+	tokens := tokenizeCheck(expr)
+	var current []string
+	pendingBetween := 0
+
+	for _, tok := range tokens {
+		upper := strings.ToUpper(tok)
+		switch {
+		case upper == "OR":
+			return []string{expr}
+		case upper == "BETWEEN":
+			pendingBetween++
+		case upper == "AND" && pendingBetween > 0:
+			pendingBetween--
+		case upper == "AND":
+			parts = append(parts, strings.Join(current, " "))
+			current = nil
+			continue
+		}
+		current = append(current, tok)
+	}
+
+	if len(current) > 0 {
+		parts = append(parts, strings.Join(current, " "))
+	}
+	return parts
+}
+
+// tokenizeCheck separa por espacios sin romper strings ni parentesis
+func tokenizeCheck(expr string) []string {
+	var tokens []string
+	var current strings.Builder
+	depth := 0
+	inQuotes := false
+
+	for i := 0; i < len(expr); i++ {
+		ch := expr[i]
+		switch {
+		case ch == '\'':
+			inQuotes = !inQuotes
+		case ch == '(' && !inQuotes:
+			depth++
+		case ch == ')' && !inQuotes:
+			depth--
+		case (ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r') && !inQuotes && depth == 0:
+			if current.Len() > 0 {
+				tokens = append(tokens, current.String())
+				current.Reset()
+			}
+			continue
+		}
+		current.WriteByte(ch)
+	}
+
+	if current.Len() > 0 {
+		tokens = append(tokens, current.String())
+	}
+	return tokens
+}
+
+// stripOuterParens quita parentesis que envuelven toda la expresion: ((a > 0)) -> a > 0
+func stripOuterParens(expr string) string {
+	expr = strings.TrimSpace(expr)
+	for len(expr) >= 2 && expr[0] == '(' && expr[len(expr)-1] == ')' {
+		depth := 0
+		wrapsAll := true
+		for i := 0; i < len(expr)-1; i++ {
+			switch expr[i] {
+			case '(':
+				depth++
+			case ')':
+				depth--
+			}
+			if depth == 0 {
+				wrapsAll = false
+				break
+			}
+		}
+		if !wrapsAll {
+			break
+		}
+		expr = strings.TrimSpace(expr[1 : len(expr)-1])
+	}
+	return expr
+}
+
+// cleanCheckLiteral quita comillas y casts de un literal ('a'::text -> a)
+func cleanCheckLiteral(lit string) string {
+	lit = strings.TrimSpace(lit)
+	if strings.HasPrefix(lit, "'") {
+		lit = lit[:strings.LastIndex(lit, "'")+1]
+	} else if idx := strings.Index(lit, "::"); idx != -1 {
+		lit = lit[:idx]
+	}
+	if len(lit) >= 2 && lit[0] == '\'' && lit[len(lit)-1] == '\'' {
+		return strings.ReplaceAll(lit[1:len(lit)-1], "''", "'")
+	}
+	return lit
+}
+
+// String renderiza la regla de forma legible: price >= 0, status IN (a, b)
+func (r ValidationRule) String() string {
+	not := ""
+	if r.Negated {
+		not = "NOT "
+	}
+
+	switch r.Kind {
+	case RuleIn:
+		return r.Column + " " + not + "IN (" + strings.Join(r.Values, ", ") + ")"
+	case RuleBetween:
+		return r.Column + " " + not + "BETWEEN " + r.Values[0] + " AND " + r.Values[1]
+	case RuleLike:
+		return r.Column + " " + not + r.Operator + " '" + r.Values[0] + "'"
+	case RuleLength:
+		return "length(" + r.Column + ") " + r.Operator + " " + r.Values[0]
+	case RuleNotNull:
+		return r.Column + " IS NOT NULL"
+	case RuleRegex:
+		return r.Column + " " + r.Operator + " '" + r.Values[0] + "'"
+	default:
+		return r.Column + " " + r.Operator + " " + r.Values[0]
+	}
+}
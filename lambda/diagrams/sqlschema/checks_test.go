@@ -0,0 +1,112 @@
+package sqlschema
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractCheckExpressions(t *testing.T) {
+	tests := []struct {
+		name string
+		elem string
+		want []string
+	}{
+		{"CHECK de columna", "price NUMERIC CHECK (price > 0)", []string{"price > 0"}},
+		{"Parentesis anidados", "CHECK ((a > 0) AND (b < 10))", []string{"(a > 0) AND (b < 10)"}},
+		{"Parentesis dentro de string", "CHECK (code <> ')')", []string{"code <> ')'"}},
+		{"Varios CHECK", "qty INT CHECK (qty > 0) CHECK (qty < 100)", []string{"qty > 0", "qty < 100"}},
+		{"Sin CHECK", "name TEXT NOT NULL", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := extractCheckExpressions(tt.elem)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("extractCheckExpressions(%q) = %v, want %v", tt.elem, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCheckExpression(t *testing.T) {
+	tests := []struct {
+		name   string
+		expr   string
+		wantOK bool
+		want   []string // reglas formateadas
+	}{
+		{"Comparacion simple", "price > 0", true, []string{"price > 0"}},
+		{"Comparacion invertida", "0 <= price", true, []string{"price >= 0"}},
+		{"IN con casts", "status IN ('active'::text, 'inactive'::text)", true, []string{"status IN (active, inactive)"}},
+		{"NOT IN", "role NOT IN ('root')", true, []string{"role NOT IN (root)"}},
+		{"BETWEEN", "rating BETWEEN 1 AND 5", true, []string{"rating BETWEEN 1 AND 5"}},
+		{"BETWEEN y AND", "rating BETWEEN 1 AND 5 AND votes >= 0", true, []string{"rating BETWEEN 1 AND 5", "votes >= 0"}},
+		{"Conjuncion con parentesis", "((qty > 0) AND (qty < 100))", true, []string{"qty > 0", "qty < 100"}},
+		{"LIKE", "code LIKE 'AB%'", true, []string{"code LIKE 'AB%'"}},
+		{"Regex", "email ~* '^.+@.+$'", true, []string{"email ~* '^.+@.+$'"}},
+		{"Longitud", "char_length(code) = 3", true, []string{"length(code) = 3"}},
+		{"IS NOT NULL", "email IS NOT NULL", true, []string{"email IS NOT NULL"}},
+		{"Columna con comillas", `"Price" > 0`, true, []string{"Price > 0"}},
+		{"OR no soportado", "price > 0 OR price IS NULL", false, nil},
+		{"Varias columnas", "end_date > start_date", false, nil},
+		{"Funcion no soportada", "lower(email) = email", false, nil},
+		{"VALUE de dominio", "VALUE > 0", false, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, ok := parseCheckExpression(tt.expr)
+			if ok != tt.wantOK {
+				t.Fatalf("parseCheckExpression(%q) ok = %v, want %v", tt.expr, ok, tt.wantOK)
+			}
+			var got []string
+			for _, r := range rules {
+				got = append(got, r.String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseCheckExpression(%q) = %v, want %v", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateSQL_CheckConstraints(t *testing.T) {
+	result := ValidateSQL(`CREATE TABLE products (
+		id SERIAL PRIMARY KEY,
+		price NUMERIC(10,2) CHECK (price >= 0),
+		status TEXT CHECK (status IN ('draft', 'published')),
+		starts_at DATE,
+		ends_at DATE,
+		CONSTRAINT valid_period CHECK (ends_at > starts_at)
+	);`)
+
+	if !result.IsValid {
+		t.Fatalf("expected valid schema, got errors: %v", result.Errors)
+	}
+
+	rules := result.Tables[0].ValidationRules
+	if len(rules) != 2 {
+		t.Fatalf("expected 2 validation rules, got %d: %v", len(rules), rules)
+	}
+	if rules[0].Column != "price" || rules[1].Kind != RuleIn {
+		t.Errorf("unexpected rules: %v", rules)
+	}
+
+	unsupported := 0
+	for _, w := range result.Warnings {
+		if w.Code == WarnUnsupportedCheck {
+			unsupported++
+		}
+	}
+	if unsupported != 1 {
+		t.Errorf("expected 1 %s warning, got %d", WarnUnsupportedCheck, unsupported)
+	}
+
+	hints := hintsOfType(result.Hints, HintValidation)
+	if len(hints) != 1 {
+		t.Fatalf("expected 1 %s hint, got %d", HintValidation, len(hints))
+	}
+	if len(hints[0].Rules) != 2 || hints[0].KeyPatterns[0] != "price >= 0" {
+		t.Errorf("unexpected hint: %+v", hints[0])
+	}
+}
@@ -0,0 +1,159 @@
+package sqlschema
+
+import "sort"
+
+// ============================================================================
+// AGRUPACION DE TABLAS POR RELACIONES (conversion por partes)
+// ============================================================================
+
+// ClusterTables agrupa las tablas en grupos de a lo sumo maxTables, juntando
+// las que se relacionan por FK o INHERITS: cada grupo se convierte en una
+// llamada al modelo, y las relaciones entre grupos quedan como referencias.
+//
+// Las componentes conexas mas grandes que maxTables se cortan en orden BFS
+// desde su tabla con mas relaciones, para que cada parte quede conectada.
+// Las componentes pequeñas se empaquetan juntas, en el orden del script.
+func ClusterTables(tables []TableInfo, maxTables int) [][]TableInfo {
+	if len(tables) == 0 {
+		return nil
+	}
+	if maxTables < 1 || len(tables) <= maxTables {
+		return [][]TableInfo{tables}
+	}
+
+	neighbors := relationGraph(tables)
+
+	// 1. Componentes conexas, en el orden del script
+	var pieces [][]int
+	visited := make([]bool, len(tables))
+	for i := range tables {
+		if visited[i] {
+			continue
+		}
+		component := bfsOrder(neighbors, mostConnected(neighbors, componentOf(neighbors, i)), visited)
+
+		// 2. Cortar las componentes grandes en partes conectadas
+		for len(component) > maxTables {
+			pieces = append(pieces, component[:maxTables])
+			component = component[maxTables:]
+		}
+		pieces = append(pieces, component)
+	}
+
+	// 3. Empaquetar las partes (first fit) sin superar maxTables
+	var clusters [][]int
+	for _, piece := range pieces {
+		placed := false
+		for c := range clusters {
+			if len(clusters[c])+len(piece) <= maxTables {
+				clusters[c] = append(clusters[c], piece...)
+				placed = true
+				break
+			}
+		}
+		if !placed {
+			clusters = append(clusters, append([]int(nil), piece...))
+		}
+	}
+
+	result := make([][]TableInfo, len(clusters))
+	for c, cluster := range clusters {
+		sort.Ints(cluster)
+		for _, i := range cluster {
+			result[c] = append(result[c], tables[i])
+		}
+	}
+	return result
+}
+
+// relationGraph retorna, por tabla, los indices de las tablas relacionadas
+// por FK o INHERITS en cualquier direccion (sin repetir ni incluirse a si misma)
+func relationGraph(tables []TableInfo) [][]int {
+	index := make(map[string]int, len(tables))
+	for i, table := range tables {
+		index[tableKey(table.Schema, table.Name)] = i
+	}
+	find := func(schema, name string) (int, bool) {
+		if i, ok := index[tableKey(schema, name)]; ok {
+			return i, true
+		}
+		// Sin schema explicito: cualquier tabla con ese nombre
+		if table, ok := findTable(tables, "", name); ok {
+			return index[tableKey(table.Schema, table.Name)], true
+		}
+		return 0, false
+	}
+
+	neighbors := make([][]int, len(tables))
+	linked := make(map[[2]int]bool)
+	link := func(a, b int) {
+		if a == b || linked[[2]int{a, b}] {
+			return
+		}
+		linked[[2]int{a, b}], linked[[2]int{b, a}] = true, true
+		neighbors[a] = append(neighbors[a], b)
+		neighbors[b] = append(neighbors[b], a)
+	}
+
+	for i, table := range tables {
+		for _, fk := range table.ForeignKeys {
+			schema := fk.RefSchema
+			if schema == "" {
+				schema = table.Schema
+			}
+			if j, ok := find(schema, fk.RefTable); ok {
+				link(i, j)
+			}
+		}
+		for _, parent := range table.Inherits {
+			schema, name := splitSchemaAndName(parent)
+			if j, ok := find(schema, name); ok {
+				link(i, j)
+			}
+		}
+	}
+	return neighbors
+}
+
+// componentOf retorna los indices de la componente conexa de start
+func componentOf(neighbors [][]int, start int) []int {
+	seen := map[int]bool{start: true}
+	component := []int{start}
+	for k := 0; k < len(component); k++ {
+		for _, next := range neighbors[component[k]] {
+			if !seen[next] {
+				seen[next] = true
+				component = append(component, next)
+			}
+		}
+	}
+	return component
+}
+
+// mostConnected retorna la tabla con mas relaciones; ante empate, la primera
+// del script
+func mostConnected(neighbors [][]int, component []int) int {
+	sort.Ints(component)
+	best := component[0]
+	for _, i := range component[1:] {
+		if len(neighbors[i]) > len(neighbors[best]) {
+			best = i
+		}
+	}
+	return best
+}
+
+// bfsOrder recorre la componente en anchura desde start, marcando visited
+func bfsOrder(neighbors [][]int, start int, visited []bool) []int {
+	visited[start] = true
+	order := []int{start}
+	for k := 0; k < len(order); k++ {
+		for _, next := range neighbors[order[k]] {
+			if !visited[next] {
+				visited[next] = true
+				order = append(order, next)
+			}
+		}
+	}
+	return order
+}
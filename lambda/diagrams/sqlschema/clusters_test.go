@@ -0,0 +1,63 @@
+package sqlschema
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestClusterTables(t *testing.T) {
+	sql := `
+CREATE TABLE users (id INT PRIMARY KEY);
+CREATE TABLE tags (id INT PRIMARY KEY);
+CREATE TABLE orders (id INT PRIMARY KEY, user_id INT REFERENCES users(id));
+CREATE TABLE order_items (id INT PRIMARY KEY, order_id INT REFERENCES orders(id));
+CREATE TABLE payments (id INT PRIMARY KEY, order_id INT REFERENCES orders(id));
+CREATE TABLE audit_log (id INT PRIMARY KEY);
+CREATE TABLE countries (code CHAR(2) PRIMARY KEY);
+CREATE TABLE regions (id INT PRIMARY KEY, country CHAR(2) REFERENCES countries(code));`
+
+	result := ValidateSQL(sql)
+	if !result.IsValid {
+		t.Fatalf("invalid test schema: %+v", result.Errors)
+	}
+
+	tests := []struct {
+		name      string
+		maxTables int
+		want      [][]string
+	}{
+		{"sin límite", 0, [][]string{{"users", "tags", "orders", "order_items", "payments", "audit_log", "countries", "regions"}}},
+		{"cabe entero", 8, [][]string{{"users", "tags", "orders", "order_items", "payments", "audit_log", "countries", "regions"}}},
+		{"componentes juntas", 4, [][]string{
+			{"users", "orders", "order_items", "payments"},
+			{"tags", "audit_log", "countries", "regions"},
+		}},
+		// orders tiene mas relaciones: el corte BFS parte de ella
+		{"componente cortada", 3, [][]string{
+			{"users", "orders", "order_items"},
+			{"tags", "payments", "audit_log"},
+			{"countries", "regions"},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got [][]string
+			for _, cluster := range ClusterTables(result.Tables, tt.maxTables) {
+				var names []string
+				for _, table := range cluster {
+					names = append(names, table.Name)
+				}
+				got = append(got, names)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ClusterTables() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if stmt := result.Tables[2].Statement; !strings.HasPrefix(stmt, "CREATE TABLE orders") {
+		t.Errorf("Statement = %q", stmt)
+	}
+}
@@ -0,0 +1,729 @@
+package sqlschema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ============================================================================
+// DETECCIÓN DE PATRONES DE MODELADO
+// ============================================================================
+
+// detectSelfReferences busca tablas con FK hacia si mismas (categories.parent_id ->
+// categories.id, organigramas, hilos de comentarios) y sugiere como modelar la
+// jerarquia en DynamoDB: sort key con materialized path o lista de adyacencia.
+func detectSelfReferences(tables []TableInfo) []DesignHint {
+	var hints []DesignHint
+
+	for _, table := range tables {
+		for _, fk := range table.ForeignKeys {
+			if !isSelfReference(table, fk) {
+				continue
+			}
+			hints = append(hints, selfReferenceHint(table, fk))
+		}
+	}
+
+	return hints
+}
+
+// isSelfReference compara tablas calificadas: sales.orders -> archive.orders
+// no es una jerarquia. Una referencia sin schema se resuelve en el schema de
+// la propia tabla (el explicito o el activo por search_path al declararla).
+// Los nombres ya vienen con case folding: "Nodes" -> nodes tampoco lo es.
+func isSelfReference(table TableInfo, fk ForeignKeyInfo) bool {
+	refSchema := fk.RefSchema
+	if refSchema == "" {
+		refSchema = table.Schema
+	}
+	return tableKey(refSchema, fk.RefTable) == tableKey(table.Schema, table.Name)
+}
+
+func selfReferenceHint(table TableInfo, fk ForeignKeyInfo) DesignHint {
+	entity := strings.ToUpper(table.Name)
+	parentCol := strings.Join(fk.Columns, "#")
+
+	idCol := "id"
+	switch {
+	case len(fk.RefColumns) > 0:
+		idCol = strings.Join(fk.RefColumns, "#")
+	case len(table.PrimaryKey) > 0:
+		idCol = strings.Join(table.PrimaryKey, "#")
+	}
+
+	indexName := fmt.Sprintf("%s-index", strings.Join(fk.Columns, "_"))
+
+	return DesignHint{
+		Type:    HintSelfReference,
+		Table:   table.Name,
+		Schema:  table.Schema,
+		Columns: fk.Columns,
+		Description: fmt.Sprintf(
+			"Table %q references itself through %s -> %s, forming a hierarchy. "+
+				"Store each node with a materialized path sort key for subtree reads, "+
+				"and keep %s as an adjacency GSI for direct children (use ROOT for nodes without parent).",
+			table.Name, parentCol, idCol, parentCol),
+		KeyPatterns: []string{
+			fmt.Sprintf("Materialized path: PK = %s, SK = PATH#<rootId>#...#<parentId>#<%s>", entity, idCol),
+			fmt.Sprintf("Adjacency list: GSI %s with PK = PARENT#<%s>, SK = <%s>", indexName, parentCol, idCol),
+		},
+		ExampleQueries: []ExampleQuery{
+			{
+				AccessPattern: "Children of a node",
+				Query:         fmt.Sprintf("Query %s WHERE PK = \"PARENT#<%s>\"", indexName, idCol),
+			},
+			{
+				AccessPattern: "All descendants of a node",
+				Query:         fmt.Sprintf("Query WHERE PK = \"%s\" AND begins_with(SK, \"<node path>#\")", entity),
+			},
+			{
+				AccessPattern: "Root nodes",
+				Query:         fmt.Sprintf("Query %s WHERE PK = \"PARENT#ROOT\"", indexName),
+			},
+			{
+				AccessPattern: "Ancestors of a node",
+				Query:         "GetItem the node, split its PATH sort key and BatchGetItem each ancestor id",
+			},
+		},
+	}
+}
+
+// Palabras en el nombre de la tabla que indican eventos append-only
+var timeSeriesTableKeywords = map[string]bool{
+	"log": true, "logs": true, "event": true, "events": true,
+	"audit": true, "audits": true, "history": true, "histories": true,
+	"activity": true, "activities": true, "trail": true, "trails": true,
+	"metric": true, "metrics": true, "reading": true, "readings": true,
+	"telemetry": true, "measurement": true, "measurements": true,
+}
+
+// Columnas que indican que las filas se modifican (no append-only)
+var updateTrackingColumns = map[string]bool{
+	"updated_at": true, "modified_at": true, "updated_on": true,
+	"last_modified": true, "last_updated": true, "updatedat": true,
+}
+
+// Columnas timestamp preferidas como sort key, en orden de prioridad
+var eventTimestampColumns = []string{
+	"occurred_at", "event_time", "timestamp", "logged_at", "recorded_at", "created_at",
+}
+
+// detectTimeSeries busca tablas de eventos/logs/auditoria: append-only (sin
+// columnas de actualizacion), con una columna timestamp y una FK al dueño.
+// Sugiere partition key por dueño + bucket de fecha, timestamp como sort key
+// y atributo TTL, igual que la tabla de schemas con expiresAt y conversionDate.
+func detectTimeSeries(tables []TableInfo) []DesignHint {
+	var hints []DesignHint
+
+	for _, table := range tables {
+		if !isTimeSeriesTableName(table.Name) {
+			continue
+		}
+
+		appendOnly := true
+		for _, col := range table.Columns {
+			if updateTrackingColumns[strings.ToLower(col.Name)] {
+				appendOnly = false
+				break
+			}
+		}
+		if !appendOnly {
+			continue
+		}
+
+		tsCol := findEventTimestampColumn(table.Columns)
+		if tsCol == "" {
+			continue
+		}
+
+		var owner *ForeignKeyInfo
+		for i, fk := range table.ForeignKeys {
+			if !isSelfReference(table, fk) {
+				owner = &table.ForeignKeys[i]
+				break
+			}
+		}
+		if owner == nil {
+			continue
+		}
+
+		hints = append(hints, timeSeriesHint(table, tsCol, *owner))
+	}
+
+	return hints
+}
+
+func isTimeSeriesTableName(name string) bool {
+	for _, part := range strings.Split(strings.ToLower(name), "_") {
+		if timeSeriesTableKeywords[part] {
+			return true
+		}
+	}
+	return false
+}
+
+func findEventTimestampColumn(columns []ColumnInfo) string {
+	var timestampCols []string
+	for _, col := range columns {
+		if strings.HasPrefix(strings.ToLower(col.DataType), "timestamp") {
+			timestampCols = append(timestampCols, col.Name)
+		}
+	}
+
+	for _, preferred := range eventTimestampColumns {
+		for _, name := range timestampCols {
+			if strings.EqualFold(name, preferred) {
+				return name
+			}
+		}
+	}
+
+	if len(timestampCols) > 0 {
+		return timestampCols[0]
+	}
+	return ""
+}
+
+func timeSeriesHint(table TableInfo, tsCol string, owner ForeignKeyInfo) DesignHint {
+	ownerEntity := strings.ToUpper(owner.RefTable)
+	ownerCol := strings.Join(owner.Columns, "#")
+
+	idCol := "id"
+	if len(table.PrimaryKey) > 0 {
+		idCol = strings.Join(table.PrimaryKey, "#")
+	}
+
+	return DesignHint{
+		Type:    HintTimeSeries,
+		Table:   table.Name,
+		Schema:  table.Schema,
+		Columns: []string{ownerCol, tsCol},
+		Description: fmt.Sprintf(
+			"Table %q looks like an append-only time series (timestamp %s, owner %s -> %s). "+
+				"Partition by owner and day bucket to spread writes, sort by %s, "+
+				"and expire old items with a TTL attribute.",
+			table.Name, tsCol, ownerCol, owner.RefTable, tsCol),
+		KeyPatterns: []string{
+			fmt.Sprintf("PK = %s#<%s>#<YYYY-MM-DD>", ownerEntity, ownerCol),
+			fmt.Sprintf("SK = <%s ISO 8601>#<%s>", tsCol, idCol),
+			fmt.Sprintf("GSI eventDate-%s-index with PK = eventDate (YYYY-MM-DD), SK = %s", tsCol, tsCol),
+			fmt.Sprintf("TTL attribute expiresAt (Unix seconds) = %s + retention period", tsCol),
+		},
+		ExampleQueries: []ExampleQuery{
+			{
+				AccessPattern: fmt.Sprintf("Events of a %s in a time range", strings.ToLower(owner.RefTable)),
+				Query:         fmt.Sprintf("Query WHERE PK = \"%s#<%s>#<day>\" AND SK BETWEEN \"<from>\" AND \"<to>\" (one query per day bucket)", ownerEntity, ownerCol),
+			},
+			{
+				AccessPattern: "Latest events of an owner",
+				Query:         fmt.Sprintf("Query WHERE PK = \"%s#<%s>#<today>\" with ScanIndexForward = false and Limit, then walk back day buckets", ownerEntity, ownerCol),
+			},
+			{
+				AccessPattern: "All events of a day",
+				Query:         fmt.Sprintf("Query eventDate-%s-index WHERE eventDate = \"<YYYY-MM-DD>\"", tsCol),
+			},
+		},
+		TTLAttribute: "expiresAt",
+	}
+}
+
+// ApplyRetentionPeriod completa los hints time-series (y de tablas particionadas
+// por fecha) con la retencion pedida por el usuario (retentionDays en el
+// request). 0 significa no especificada.
+func ApplyRetentionPeriod(hints []DesignHint, retentionDays int) {
+	if retentionDays <= 0 {
+		return
+	}
+	for i := range hints {
+		if hints[i].Type != HintTimeSeries && hints[i].Type != HintPartitioned {
+			continue
+		}
+		hints[i].RetentionDays = retentionDays
+		for j, kp := range hints[i].KeyPatterns {
+			if strings.HasPrefix(kp, "TTL attribute") {
+				hints[i].KeyPatterns[j] = strings.Replace(kp, "retention period", fmt.Sprintf("%d days", retentionDays), 1)
+			}
+		}
+	}
+}
+
+// detectEnumAttributes documenta las columnas que usan un ENUM (directo o via
+// DOMAIN): en DynamoDB se guardan como atributo string y los valores
+// permitidos se validan en la aplicacion.
+func detectEnumAttributes(tables []TableInfo) []DesignHint {
+	var hints []DesignHint
+
+	for _, table := range tables {
+		for _, col := range table.Columns {
+			if len(col.EnumValues) == 0 {
+				continue
+			}
+			hints = append(hints, DesignHint{
+				Type:    HintEnumAttribute,
+				Table:   table.Name,
+				Schema:  table.Schema,
+				Columns: []string{col.Name},
+				Description: fmt.Sprintf(
+					"Column %q uses enum type %q. Store it as a string attribute (S); "+
+						"DynamoDB does not enforce the allowed values, validate them in the application.",
+					col.Name, col.DataType),
+				KeyPatterns:   []string{fmt.Sprintf("%s: S, one of [%s]", col.Name, strings.Join(col.EnumValues, ", "))},
+				AllowedValues: col.EnumValues,
+			})
+		}
+	}
+
+	return hints
+}
+
+// detectMultiSchema documenta como se mapean los schemas de PostgreSQL cuando
+// el script declara tablas en mas de uno (billing.invoices y sales.invoices):
+// como prefijo del nombre de tabla o como entity type en la clave.
+func detectMultiSchema(tables []TableInfo, mapping string) []DesignHint {
+	var schemas []string
+	seenSchemas := make(map[string]bool)
+	nameCount := make(map[string]int)
+
+	for _, table := range tables {
+		schema := effectiveSchema(table)
+		if !seenSchemas[schema] {
+			seenSchemas[schema] = true
+			schemas = append(schemas, schema)
+		}
+		nameCount[strings.ToLower(table.Name)]++
+	}
+
+	if len(schemas) < 2 {
+		return nil
+	}
+
+	var collisions []string
+	for _, table := range tables {
+		name := strings.ToLower(table.Name)
+		if nameCount[name] > 1 {
+			collisions = append(collisions, name)
+			nameCount[name] = 0
+		}
+	}
+
+	hint := DesignHint{
+		Type: HintMultiSchema,
+		Description: fmt.Sprintf("Script declares tables in %d schemas (%s). Using schemaMapping=%s.",
+			len(schemas), strings.Join(schemas, ", "), mapping),
+	}
+	if len(collisions) > 0 {
+		hint.Description += fmt.Sprintf(" Tables with the same name in several schemas: %s.", strings.Join(collisions, ", "))
+	}
+
+	for _, table := range tables {
+		schema := effectiveSchema(table)
+		if mapping == SchemaMappingEntity {
+			entity := strings.ToUpper(schema + "#" + table.Name)
+			hint.KeyPatterns = append(hint.KeyPatterns,
+				fmt.Sprintf("%s.%s -> entity type %s (PK = %s#<id>)", schema, table.Name, entity, entity))
+		} else {
+			hint.KeyPatterns = append(hint.KeyPatterns,
+				fmt.Sprintf("%s.%s -> table %s_%s", schema, table.Name, schema, table.Name))
+		}
+	}
+
+	return []DesignHint{hint}
+}
+
+// ApplySchemaMapping regenera el hint MULTI_SCHEMA con el mapeo elegido por el
+// usuario (schemaMapping en el request). Vacio mantiene el default (prefix).
+func ApplySchemaMapping(result *ValidationResult, mapping string) {
+	if mapping == "" || mapping == SchemaMappingPrefix {
+		return
+	}
+	for i, h := range result.Hints {
+		if h.Type != HintMultiSchema {
+			continue
+		}
+		if regenerated := detectMultiSchema(result.Tables, mapping); len(regenerated) > 0 {
+			result.Hints[i] = regenerated[0]
+		}
+	}
+}
+
+func effectiveSchema(table TableInfo) string {
+	if table.Schema == "" {
+		return defaultSchema
+	}
+	return table.Schema
+}
+
+// detectGeneratedColumns traduce columnas cuyo valor genera PostgreSQL:
+// los autoincrementales (serial, identity, nextval) pasan a ULID/UUID o a un
+// item contador atomico, y las columnas STORED a atributos calculados al escribir.
+func detectGeneratedColumns(tables []TableInfo) []DesignHint {
+	var hints []DesignHint
+
+	for _, table := range tables {
+		for _, col := range table.Columns {
+			switch col.Generation {
+			case GenerationSerial, GenerationIdentity, GenerationSequence:
+				hints = append(hints, autoIncrementHint(table, col))
+			case GenerationStored:
+				hints = append(hints, computedAttributeHint(table, col))
+			}
+		}
+	}
+
+	return hints
+}
+
+func autoIncrementHint(table TableInfo, col ColumnInfo) DesignHint {
+	source := strings.ToLower(col.DataType)
+	switch col.Generation {
+	case GenerationIdentity:
+		source = "identity column"
+	case GenerationSequence:
+		source = fmt.Sprintf("sequence %s", col.SequenceName)
+	}
+
+	counterKey := fmt.Sprintf("COUNTER#%s", strings.ToUpper(table.Name))
+	if col.SequenceName != "" {
+		counterKey = fmt.Sprintf("COUNTER#%s", strings.ToUpper(col.SequenceName))
+	}
+
+	return DesignHint{
+		Type:    HintAutoIncrement,
+		Table:   table.Name,
+		Schema:  table.Schema,
+		Columns: []string{col.Name},
+		Description: fmt.Sprintf(
+			"Column %q in table %q is auto-incremented by PostgreSQL (%s). DynamoDB has no sequences: "+
+				"generate a ULID (time-sortable) or UUID in the application, or keep numeric ids with an atomic counter item.",
+			col.Name, table.Name, source),
+		KeyPatterns: []string{
+			fmt.Sprintf("%s: S = ULID generated on write (preferred, sortable by creation time)", col.Name),
+			fmt.Sprintf("Atomic counter item: PK = %s, attribute currentValue (N)", counterKey),
+		},
+		ExampleQueries: []ExampleQuery{
+			{
+				AccessPattern: fmt.Sprintf("Next %s value (counter option)", col.Name),
+				Query:         fmt.Sprintf("UpdateItem PK = \"%s\" SET currentValue = if_not_exists(currentValue, :zero) + :one, ReturnValues = UPDATED_NEW", counterKey),
+			},
+		},
+	}
+}
+
+func computedAttributeHint(table TableInfo, col ColumnInfo) DesignHint {
+	return DesignHint{
+		Type:    HintComputedAttr,
+		Table:   table.Name,
+		Schema:  table.Schema,
+		Columns: []string{col.Name},
+		Description: fmt.Sprintf(
+			"Column %q in table %q is a stored generated column. DynamoDB does not compute attributes: "+
+				"calculate it in the application on every PutItem/UpdateItem that changes its inputs.",
+			col.Name, table.Name),
+		KeyPatterns: []string{
+			fmt.Sprintf("%s = %s (computed at write time)", col.Name, col.GenerationExpr),
+		},
+	}
+}
+
+// detectValidationRules agrupa por tabla las reglas derivadas de CHECK. DynamoDB
+// no tiene un equivalente, asi que se documentan como validadores de aplicacion.
+func detectValidationRules(tables []TableInfo) []DesignHint {
+	var hints []DesignHint
+
+	for _, table := range tables {
+		if len(table.ValidationRules) == 0 {
+			continue
+		}
+
+		hint := DesignHint{
+			Type:   HintValidation,
+			Table:  table.Name,
+			Schema: table.Schema,
+			Description: fmt.Sprintf(
+				"Table %q has CHECK constraints. DynamoDB cannot enforce them: validate these rules in the application "+
+					"before every PutItem/UpdateItem (simple comparisons can also go in a ConditionExpression).",
+				table.Name),
+			Rules: table.ValidationRules,
+		}
+
+		seen := make(map[string]bool)
+		for _, rule := range table.ValidationRules {
+			if !seen[rule.Column] {
+				seen[rule.Column] = true
+				hint.Columns = append(hint.Columns, rule.Column)
+			}
+			hint.KeyPatterns = append(hint.KeyPatterns, rule.String())
+		}
+
+		hints = append(hints, hint)
+	}
+
+	return hints
+}
+
+// detectViewAccessPatterns convierte cada vista en un patron de acceso: las
+// columnas filtradas por igualdad son candidatas a partition key de un GSI y
+// las de rango u ORDER BY a sort key. Los JOIN sugieren item collections.
+func detectViewAccessPatterns(tables []TableInfo, views []ViewInfo) []DesignHint {
+	var hints []DesignHint
+
+	for _, view := range views {
+		table, ok := findTable(tables, view.BaseSchema, view.BaseTable)
+		if !ok {
+			continue
+		}
+		hints = append(hints, viewAccessHint(table, view))
+	}
+
+	return hints
+}
+
+func viewAccessHint(table TableInfo, view ViewInfo) DesignHint {
+	entity := strings.ToUpper(table.Name)
+	kind := "View"
+	if view.Materialized {
+		kind = "Materialized view"
+	}
+
+	partitionCol := ""
+	if len(view.FilterColumns) > 0 {
+		partitionCol = view.FilterColumns[0]
+	}
+	sortCol := ""
+	for _, col := range append(append([]string{}, view.RangeColumns...), view.OrderColumns...) {
+		if col != partitionCol {
+			sortCol = col
+			break
+		}
+	}
+
+	hint := DesignHint{
+		Type:   HintViewAccess,
+		Table:  table.Name,
+		Schema: table.Schema,
+		Description: fmt.Sprintf(
+			"%s %q reads table %q. Every view is an access pattern the DynamoDB design must serve without a Scan.",
+			kind, view.Name, table.Name),
+	}
+
+	var query string
+	switch {
+	case partitionCol != "" && len(table.PrimaryKey) == 1 && table.PrimaryKey[0] == partitionCol:
+		hint.KeyPatterns = append(hint.KeyPatterns, fmt.Sprintf("Served by the table key %s", partitionCol))
+		query = fmt.Sprintf("GetItem PK = \"%s#<%s>\"", entity, partitionCol)
+	case partitionCol != "":
+		indexName := fmt.Sprintf("%s-index", partitionCol)
+		pattern := fmt.Sprintf("GSI %s with PK = <%s>", indexName, partitionCol)
+		query = fmt.Sprintf("Query %s WHERE PK = :%s", indexName, partitionCol)
+		if sortCol != "" {
+			pattern += fmt.Sprintf(", SK = <%s>", sortCol)
+			query += fmt.Sprintf(" AND SK > :%s", sortCol)
+		}
+		hint.KeyPatterns = append(hint.KeyPatterns, pattern)
+	case sortCol != "":
+		indexName := fmt.Sprintf("%s-index", sortCol)
+		hint.KeyPatterns = append(hint.KeyPatterns, fmt.Sprintf(
+			"GSI %s with a constant PK = TYPE#%s and SK = <%s> (shard the PK if the table is large)", indexName, entity, sortCol))
+		query = fmt.Sprintf("Query %s WHERE PK = \"TYPE#%s\" ORDER BY SK", indexName, entity)
+	default:
+		hint.KeyPatterns = append(hint.KeyPatterns, "No filter on the base table: precompute the view result if it is read often")
+		query = fmt.Sprintf("Scan %s (avoid on hot paths)", table.Name)
+	}
+
+	if len(view.JoinedTables) > 0 {
+		hint.KeyPatterns = append(hint.KeyPatterns, fmt.Sprintf(
+			"Joins %s: store those items in the same item collection (PK = %s#<id>) or denormalize the attributes the view reads",
+			strings.Join(view.JoinedTables, ", "), entity))
+	}
+	if view.Materialized {
+		hint.KeyPatterns = append(hint.KeyPatterns, "Keep the precomputed result as its own items, refreshed from DynamoDB Streams")
+	}
+
+	for _, col := range []string{partitionCol, sortCol} {
+		if col != "" {
+			hint.Columns = append(hint.Columns, col)
+		}
+	}
+	hint.ExampleQueries = []ExampleQuery{{AccessPattern: fmt.Sprintf("View %s", view.Name), Query: query}}
+
+	return hint
+}
+
+// findTable busca una tabla por nombre; schema vacio acepta cualquier schema
+func findTable(tables []TableInfo, schema, name string) (TableInfo, bool) {
+	for _, table := range tables {
+		if table.Name != name {
+			continue
+		}
+		if schema == "" || tableKey(table.Schema, table.Name) == tableKey(schema, name) {
+			return table, true
+		}
+	}
+	return TableInfo{}, false
+}
+
+// detectDescriptions propaga los COMMENT ON COLUMN para que el modelo los
+// copie como descripcion de cada atributo en el diseño generado.
+func detectDescriptions(tables []TableInfo) []DesignHint {
+	var hints []DesignHint
+
+	for _, table := range tables {
+		descriptions := make(map[string]string)
+		for _, col := range table.Columns {
+			if col.Description != "" {
+				descriptions[col.Name] = col.Description
+			}
+		}
+		if len(descriptions) == 0 && table.Description == "" {
+			continue
+		}
+
+		description := fmt.Sprintf("Table %q has documented columns; keep them as attribute descriptions.", table.Name)
+		if table.Description != "" {
+			description = fmt.Sprintf("Table %q: %s", table.Name, table.Description)
+		}
+
+		hint := DesignHint{
+			Type:         HintDescriptions,
+			Table:        table.Name,
+			Schema:       table.Schema,
+			Description:  description,
+			Descriptions: descriptions,
+		}
+		for _, col := range table.Columns {
+			if col.Description != "" {
+				hint.Columns = append(hint.Columns, col.Name)
+			}
+		}
+		hints = append(hints, hint)
+	}
+
+	return hints
+}
+
+// detectPartitionedTables usa la clave de PARTITION BY como señal para las
+// llaves de DynamoDB: RANGE sobre fechas es un sort key con buckets de tiempo,
+// LIST agrupa por valor (prefijo del partition key) y HASH ya indica una
+// distribucion uniforme apta como partition key.
+func detectPartitionedTables(tables []TableInfo) []DesignHint {
+	var hints []DesignHint
+
+	for _, table := range tables {
+		if table.PartitionStrategy == "" || len(table.PartitionKey) == 0 {
+			continue
+		}
+		hints = append(hints, partitionedTableHint(table))
+	}
+
+	return hints
+}
+
+func partitionedTableHint(table TableInfo) DesignHint {
+	entity := strings.ToUpper(table.Name)
+	keyCol := table.PartitionKey[0]
+
+	var partitions []string
+	for _, p := range table.Partitions {
+		partitions = append(partitions, p.Name)
+	}
+	merged := "no partitions declared"
+	if len(partitions) > 0 {
+		merged = fmt.Sprintf("%d partitions merged into one entity (%s)", len(partitions), strings.Join(partitions, ", "))
+	}
+
+	hint := DesignHint{
+		Type:    HintPartitioned,
+		Table:   table.Name,
+		Schema:  table.Schema,
+		Columns: table.PartitionKey,
+	}
+
+	switch {
+	case table.PartitionStrategy == PartitionRange && isTemporalColumn(table, keyCol):
+		hint.Description = fmt.Sprintf(
+			"Table %q is partitioned by RANGE on %s, %s. Time ranges map to a sort key: "+
+				"bucket the partition key by period and replace dropping old partitions with a TTL attribute.",
+			table.Name, keyCol, merged)
+		hint.KeyPatterns = []string{
+			fmt.Sprintf("PK = %s#<YYYY-MM>, SK = <%s ISO 8601>#<id>", entity, keyCol),
+			fmt.Sprintf("TTL attribute expiresAt (Unix seconds) = %s + retention period", keyCol),
+		}
+		hint.ExampleQueries = []ExampleQuery{{
+			AccessPattern: fmt.Sprintf("%s in a time range", table.Name),
+			Query:         fmt.Sprintf("Query WHERE PK = \"%s#<YYYY-MM>\" AND SK BETWEEN \"<from>\" AND \"<to>\" (one query per bucket)", entity),
+		}}
+		hint.TTLAttribute = "expiresAt"
+	case table.PartitionStrategy == PartitionRange:
+		hint.Description = fmt.Sprintf(
+			"Table %q is partitioned by RANGE on %s, %s. Use %s as sort key so range reads stay a single Query.",
+			table.Name, keyCol, merged, keyCol)
+		hint.KeyPatterns = []string{fmt.Sprintf("PK = %s, SK = <%s> (zero-padded if numeric)", entity, keyCol)}
+		hint.ExampleQueries = []ExampleQuery{{
+			AccessPattern: fmt.Sprintf("%s by %s range", table.Name, keyCol),
+			Query:         fmt.Sprintf("Query WHERE PK = \"%s\" AND SK BETWEEN :from AND :to", entity),
+		}}
+	case table.PartitionStrategy == PartitionList:
+		hint.Description = fmt.Sprintf(
+			"Table %q is partitioned by LIST on %s, %s. Each list value is a natural partition key prefix; "+
+				"add a write-sharding suffix if one value concentrates the traffic.",
+			table.Name, keyCol, merged)
+		hint.KeyPatterns = []string{fmt.Sprintf("PK = %s#<%s>, SK = <id>", strings.ToUpper(keyCol), keyCol)}
+		hint.ExampleQueries = []ExampleQuery{{
+			AccessPattern: fmt.Sprintf("%s of one %s", table.Name, keyCol),
+			Query:         fmt.Sprintf("Query WHERE PK = \"%s#<value>\"", strings.ToUpper(keyCol)),
+		}}
+	default:
+		hint.Description = fmt.Sprintf(
+			"Table %q is partitioned by HASH on %s, %s. The hash key already spreads rows evenly: use it directly as partition key.",
+			table.Name, strings.Join(table.PartitionKey, ", "), merged)
+		hint.KeyPatterns = []string{fmt.Sprintf("PK = <%s>", strings.Join(table.PartitionKey, "#"))}
+		hint.ExampleQueries = []ExampleQuery{{
+			AccessPattern: fmt.Sprintf("%s by %s", table.Name, keyCol),
+			Query:         fmt.Sprintf("Query WHERE PK = :%s", keyCol),
+		}}
+	}
+
+	return hint
+}
+
+func isTemporalColumn(table TableInfo, name string) bool {
+	for _, col := range table.Columns {
+		if col.Name != name {
+			continue
+		}
+		dt := strings.ToLower(col.DataType)
+		return strings.HasPrefix(dt, "timestamp") || strings.HasPrefix(dt, "date")
+	}
+	return false
+}
+
+// detectInheritance traduce INHERITS a entidades que comparten tabla: los
+// items de la hija y del padre conviven con un atributo de tipo que los distingue.
+func detectInheritance(tables []TableInfo) []DesignHint {
+	var hints []DesignHint
+
+	for _, table := range tables {
+		for _, parent := range table.Inherits {
+			parentEntity := strings.ToUpper(parent)
+			hints = append(hints, DesignHint{
+				Type:   HintInheritance,
+				Table:  table.Name,
+				Schema: table.Schema,
+				Description: fmt.Sprintf(
+					"Table %q inherits from %q. Store both entities in the same DynamoDB table with the parent's key pattern "+
+						"and an entityType attribute; reading the parent includes child items, as in PostgreSQL.",
+					table.Name, parent),
+				KeyPatterns: []string{
+					fmt.Sprintf("PK = %s#<id> for both entities, entityType = %s | %s", parentEntity, parentEntity, strings.ToUpper(table.Name)),
+					"GSI entityType-index with PK = entityType to list only one entity",
+				},
+				ExampleQueries: []ExampleQuery{{
+					AccessPattern: fmt.Sprintf("Only %s items", table.Name),
+					Query:         fmt.Sprintf("Query entityType-index WHERE entityType = \"%s\"", strings.ToUpper(table.Name)),
+				}},
+			})
+		}
+	}
+
+	return hints
+}
@@ -0,0 +1,364 @@
+package sqlschema
+
+import (
+	"strings"
+	"testing"
+)
+
+func hintsOfType(hints []DesignHint, hintType string) []DesignHint {
+	var filtered []DesignHint
+	for _, h := range hints {
+		if h.Type == hintType {
+			filtered = append(filtered, h)
+		}
+	}
+	return filtered
+}
+
+func TestDetectSelfReferences(t *testing.T) {
+	tests := []struct {
+		name      string
+		sql       string
+		wantTable string
+		wantCol   string
+	}{
+		{
+			name: "FK inline",
+			sql: `CREATE TABLE categories (
+				id SERIAL PRIMARY KEY,
+				parent_id INT REFERENCES categories(id),
+				name TEXT
+			);`,
+			wantTable: "categories",
+			wantCol:   "parent_id",
+		},
+		{
+			name: "FK a nivel de tabla",
+			sql: `CREATE TABLE employees (
+				id BIGINT,
+				manager_id BIGINT,
+				PRIMARY KEY (id),
+				FOREIGN KEY (manager_id) REFERENCES employees(id)
+			);`,
+			wantTable: "employees",
+			wantCol:   "manager_id",
+		},
+		{
+			name: "FK sin schema resuelta en el schema de la tabla",
+			sql: `CREATE TABLE hr.employees (
+				id BIGINT PRIMARY KEY,
+				manager_id BIGINT REFERENCES employees(id)
+			);`,
+			wantTable: "employees",
+			wantCol:   "manager_id",
+		},
+		{
+			name: "Hilo de comentarios sin columna referenciada",
+			sql: `CREATE TABLE comments (
+				id UUID PRIMARY KEY,
+				reply_to UUID REFERENCES Comments,
+				body TEXT
+			);`,
+			wantTable: "comments",
+			wantCol:   "reply_to",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ValidateSQL(tt.sql)
+			if !result.IsValid {
+				t.Fatalf("expected valid SQL, got errors: %+v", result.Errors)
+			}
+			hints := hintsOfType(result.Hints, HintSelfReference)
+			if len(hints) != 1 {
+				t.Fatalf("expected 1 hint, got %d: %+v", len(hints), result.Hints)
+			}
+
+			hint := hints[0]
+			if hint.Type != HintSelfReference || hint.Table != tt.wantTable {
+				t.Errorf("hint = %s/%s, want %s/%s", hint.Type, hint.Table, HintSelfReference, tt.wantTable)
+			}
+			if len(hint.Columns) != 1 || hint.Columns[0] != tt.wantCol {
+				t.Errorf("hint columns = %v, want [%s]", hint.Columns, tt.wantCol)
+			}
+			if len(hint.KeyPatterns) == 0 || !strings.Contains(hint.KeyPatterns[0], "PATH#") {
+				t.Errorf("expected materialized path key pattern, got %v", hint.KeyPatterns)
+			}
+			if len(hint.ExampleQueries) < 2 {
+				t.Errorf("expected children/descendants example queries, got %v", hint.ExampleQueries)
+			}
+		})
+	}
+}
+
+func TestDetectSelfReferences_NoHierarchy(t *testing.T) {
+	result := ValidateSQL(`
+		CREATE TABLE users (id SERIAL PRIMARY KEY);
+		CREATE TABLE orders (id SERIAL PRIMARY KEY, user_id INT REFERENCES users(id));
+	`)
+	if hints := hintsOfType(result.Hints, HintSelfReference); len(hints) != 0 {
+		t.Errorf("expected no hints, got %+v", hints)
+	}
+}
+
+// Misma tabla en otro schema: archivar pedidos no es una jerarquia
+func TestDetectSelfReferences_CrossSchemaSameName(t *testing.T) {
+	result := ValidateSQL(`
+		CREATE TABLE archive.orders (id BIGINT PRIMARY KEY);
+		CREATE TABLE sales.orders (
+			id BIGINT PRIMARY KEY,
+			archived_id BIGINT REFERENCES archive.orders(id)
+		);
+	`)
+	if !result.IsValid {
+		t.Fatalf("expected valid SQL, got errors: %+v", result.Errors)
+	}
+	if hints := hintsOfType(result.Hints, HintSelfReference); len(hints) != 0 {
+		t.Errorf("expected no hints, got %+v", hints)
+	}
+}
+
+// Un nombre entre comillas conserva las mayusculas: "Nodes" y nodes son tablas
+// distintas
+func TestDetectSelfReferences_QuotedMixedCase(t *testing.T) {
+	result := ValidateSQL(`
+		CREATE TABLE nodes (id BIGINT PRIMARY KEY);
+		CREATE TABLE "Nodes" (
+			id BIGINT PRIMARY KEY,
+			node_id BIGINT REFERENCES nodes(id)
+		);
+	`)
+	if !result.IsValid {
+		t.Fatalf("expected valid SQL, got errors: %+v", result.Errors)
+	}
+	if hints := hintsOfType(result.Hints, HintSelfReference); len(hints) != 0 {
+		t.Errorf("expected no hints, got %+v", hints)
+	}
+}
+
+func TestDetectTimeSeries(t *testing.T) {
+	tests := []struct {
+		name    string
+		sql     string
+		want    bool
+		wantTS  string
+		wantOwn string
+	}{
+		{
+			name: "Audit log append-only",
+			sql: `CREATE TABLE audit_log (
+				id BIGSERIAL PRIMARY KEY,
+				user_id INT NOT NULL REFERENCES users(id),
+				action TEXT NOT NULL,
+				created_at TIMESTAMPTZ DEFAULT NOW()
+			);`,
+			want:    true,
+			wantTS:  "created_at",
+			wantOwn: "user_id",
+		},
+		{
+			name: "Prefiere occurred_at sobre created_at",
+			sql: `CREATE TABLE device_events (
+				id UUID PRIMARY KEY,
+				device_id UUID,
+				created_at TIMESTAMP,
+				occurred_at TIMESTAMPTZ,
+				FOREIGN KEY (device_id) REFERENCES devices(id)
+			);`,
+			want:    true,
+			wantTS:  "occurred_at",
+			wantOwn: "device_id",
+		},
+		{
+			name: "Con updated_at no es append-only",
+			sql: `CREATE TABLE activity_log (
+				id SERIAL PRIMARY KEY,
+				user_id INT REFERENCES users(id),
+				created_at TIMESTAMPTZ,
+				updated_at TIMESTAMPTZ
+			);`,
+			want: false,
+		},
+		{
+			name: "Sin FK al dueño",
+			sql:  `CREATE TABLE app_logs (id SERIAL PRIMARY KEY, message TEXT, logged_at TIMESTAMPTZ);`,
+			want: false,
+		},
+		{
+			name: "Dueño con el mismo nombre en otro schema",
+			sql: `CREATE TABLE audit.events (
+				id BIGSERIAL PRIMARY KEY,
+				event_id BIGINT REFERENCES public.events(id),
+				created_at TIMESTAMPTZ
+			);`,
+			want:    true,
+			wantTS:  "created_at",
+			wantOwn: "event_id",
+		},
+		{
+			name: "Nombre sin señal de eventos",
+			sql:  `CREATE TABLE orders (id SERIAL PRIMARY KEY, user_id INT REFERENCES users(id), created_at TIMESTAMPTZ);`,
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ValidateSQL(tt.sql)
+			if !result.IsValid {
+				t.Fatalf("expected valid SQL, got errors: %+v", result.Errors)
+			}
+
+			var hint *DesignHint
+			if hints := hintsOfType(result.Hints, HintTimeSeries); len(hints) > 0 {
+				hint = &hints[0]
+			}
+			if (hint != nil) != tt.want {
+				t.Fatalf("time-series detected = %v, want %v (%+v)", hint != nil, tt.want, result.Hints)
+			}
+			if hint == nil {
+				return
+			}
+			if hint.Columns[0] != tt.wantOwn || hint.Columns[1] != tt.wantTS {
+				t.Errorf("hint columns = %v, want [%s %s]", hint.Columns, tt.wantOwn, tt.wantTS)
+			}
+			if hint.TTLAttribute != "expiresAt" {
+				t.Errorf("TTLAttribute = %q, want expiresAt", hint.TTLAttribute)
+			}
+		})
+	}
+}
+
+func TestApplyRetentionPeriod(t *testing.T) {
+	result := ValidateSQL(`CREATE TABLE login_events (
+		id BIGSERIAL PRIMARY KEY,
+		user_id INT REFERENCES users(id),
+		created_at TIMESTAMPTZ
+	);`)
+	ApplyRetentionPeriod(result.Hints, 30)
+
+	hints := hintsOfType(result.Hints, HintTimeSeries)
+	if len(hints) != 1 {
+		t.Fatalf("expected 1 hint, got %+v", result.Hints)
+	}
+	hint := hints[0]
+	if hint.RetentionDays != 30 {
+		t.Errorf("RetentionDays = %d, want 30", hint.RetentionDays)
+	}
+	last := hint.KeyPatterns[len(hint.KeyPatterns)-1]
+	if !strings.Contains(last, "30 days") {
+		t.Errorf("TTL key pattern = %q, want retention in days", last)
+	}
+}
+
+func TestDetectEnumAttributes(t *testing.T) {
+	result := ValidateSQL(`
+		CREATE TYPE order_status AS ENUM ('pending', 'paid', 'shipped');
+		CREATE TABLE orders (
+			id SERIAL PRIMARY KEY,
+			status order_status NOT NULL,
+			total NUMERIC(10,2)
+		);`)
+	if !result.IsValid {
+		t.Fatalf("expected valid SQL, got errors: %+v", result.Errors)
+	}
+
+	col := result.Tables[0].Columns[1]
+	if col.BaseType != "text" || len(col.EnumValues) != 3 {
+		t.Errorf("column = %+v, want enum values carried into ColumnInfo", col)
+	}
+
+	hints := hintsOfType(result.Hints, HintEnumAttribute)
+	if len(hints) != 1 {
+		t.Fatalf("expected 1 ENUM_ATTRIBUTE hint, got %+v", result.Hints)
+	}
+	if got := strings.Join(hints[0].AllowedValues, ","); got != "pending,paid,shipped" {
+		t.Errorf("AllowedValues = %s", got)
+	}
+}
+
+func TestValidateSQL_InvalidDomainBaseType(t *testing.T) {
+	result := ValidateSQL(`
+		CREATE DOMAIN code AS nope;
+		CREATE TABLE t (id INT PRIMARY KEY, c code);`)
+	if result.IsValid {
+		t.Fatal("expected invalid result for domain with unknown base type")
+	}
+	if result.Errors[0].Code != ErrInvalidDataType {
+		t.Errorf("error code = %s, want %s", result.Errors[0].Code, ErrInvalidDataType)
+	}
+}
+
+func TestDetectMultiSchema(t *testing.T) {
+	sql := `
+		CREATE TABLE billing.invoices (id INT PRIMARY KEY);
+		SET search_path TO sales;
+		CREATE TABLE invoices (id INT PRIMARY KEY);`
+
+	result := ValidateSQL(sql)
+	if !result.IsValid {
+		t.Fatalf("expected valid SQL, got errors: %+v", result.Errors)
+	}
+	if result.Tables[1].Schema != "sales" {
+		t.Errorf("schema from search_path = %q, want sales", result.Tables[1].Schema)
+	}
+	if len(result.Hints) != 1 || result.Hints[0].Type != HintMultiSchema {
+		t.Fatalf("expected 1 MULTI_SCHEMA hint, got %+v", result.Hints)
+	}
+	if got := result.Hints[0].KeyPatterns[0]; got != "billing.invoices -> table billing_invoices" {
+		t.Errorf("prefix mapping = %q", got)
+	}
+
+	ApplySchemaMapping(&result, SchemaMappingEntity)
+	if got := result.Hints[0].KeyPatterns[1]; !strings.Contains(got, "SALES#INVOICES") {
+		t.Errorf("entity mapping = %q, want SALES#INVOICES entity type", got)
+	}
+
+	single := ValidateSQL(`CREATE TABLE public.a (id INT PRIMARY KEY); CREATE TABLE b (id INT PRIMARY KEY);`)
+	if len(single.Hints) != 0 {
+		t.Errorf("expected no hint for a single schema, got %+v", single.Hints)
+	}
+}
+
+func TestDetectGeneratedColumns(t *testing.T) {
+	result := ValidateSQL(`
+		CREATE TABLE order_lines (
+			line_no BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+			legacy_id INT DEFAULT nextval('legacy_seq'::regclass),
+			price NUMERIC(10,2) NOT NULL,
+			qty INT NOT NULL,
+			total NUMERIC(12,2) GENERATED ALWAYS AS (price * qty) STORED,
+			note TEXT
+		);`)
+	if !result.IsValid {
+		t.Fatalf("expected valid SQL, got errors: %+v", result.Errors)
+	}
+
+	cols := result.Tables[0].Columns
+	wantGen := []string{GenerationIdentity, GenerationSequence, "", "", GenerationStored, ""}
+	for i, want := range wantGen {
+		if cols[i].Generation != want {
+			t.Errorf("column %s generation = %q, want %q", cols[i].Name, cols[i].Generation, want)
+		}
+	}
+	if cols[1].SequenceName != "legacy_seq" {
+		t.Errorf("SequenceName = %q, want legacy_seq", cols[1].SequenceName)
+	}
+	if cols[4].GenerationExpr != "price * qty" {
+		t.Errorf("GenerationExpr = %q, want price * qty", cols[4].GenerationExpr)
+	}
+
+	auto := hintsOfType(result.Hints, HintAutoIncrement)
+	if len(auto) != 2 {
+		t.Fatalf("expected 2 AUTO_INCREMENT hints, got %+v", auto)
+	}
+	if !strings.Contains(auto[1].KeyPatterns[1], "COUNTER#LEGACY_SEQ") {
+		t.Errorf("sequence counter pattern = %q", auto[1].KeyPatterns[1])
+	}
+
+	computed := hintsOfType(result.Hints, HintComputedAttr)
+	if len(computed) != 1 || !strings.Contains(computed[0].KeyPatterns[0], "total = price * qty") {
+		t.Errorf("expected computed attribute hint for total, got %+v", computed)
+	}
+}
@@ -0,0 +1,249 @@
+// Package sqlschema valida scripts DDL de PostgreSQL y detecta los patrones de
+// modelado (hints) que guian la conversion a DynamoDB. Lo usan el process
+// handler y el CLI sql2ddb.
+package sqlschema
+
+// ============================================================================
+// Error Codes (spec/fase1_construccion_validacion.md)
+// ============================================================================
+
+const (
+	ErrEmptySQLContent         = "EMPTY_SQL_CONTENT"
+	ErrInvalidSQLSyntax        = "INVALID_SQL_SYNTAX"
+	ErrNoCreateTablesFound     = "NO_CREATE_TABLES_FOUND"
+	ErrInvalidTableName        = "INVALID_TABLE_NAME"
+	ErrInvalidColumnName       = "INVALID_COLUMN_NAME"
+	ErrInvalidDataType         = "INVALID_DATA_TYPE"
+	ErrInvalidConstraintSyntax = "INVALID_CONSTRAINT_SYNTAX"
+	ErrDuplicateColumn         = "DUPLICATE_COLUMN"
+	ErrFKInvalidReference      = "FK_INVALID_REFERENCE"
+	ErrIncompleteStatement     = "INCOMPLETE_STATEMENT"
+	ErrDuplicateTable          = "DUPLICATE_TABLE"
+	ErrUnknownParentTable      = "UNKNOWN_PARENT_TABLE"
+
+	WarnNoPrimaryKey         = "NO_PRIMARY_KEY"
+	WarnReservedWord         = "RESERVED_WORD"
+	WarnDynamoDBReservedWord = "DYNAMODB_RESERVED_WORD"
+	WarnUnsupportedCheck     = "UNSUPPORTED_CHECK"
+)
+
+// Tipos de hints de diseño detectados durante la validación
+const (
+	HintSelfReference = "SELF_REFERENCE"
+	HintTimeSeries    = "TIME_SERIES"
+	HintEnumAttribute = "ENUM_ATTRIBUTE"
+	HintMultiSchema   = "MULTI_SCHEMA"
+	HintAutoIncrement = "AUTO_INCREMENT"
+	HintComputedAttr  = "COMPUTED_ATTRIBUTE"
+	HintValidation    = "VALIDATION_RULES"
+	HintViewAccess    = "VIEW_ACCESS_PATTERN"
+	HintDescriptions  = "ATTRIBUTE_DESCRIPTIONS"
+	HintPartitioned   = "PARTITIONED_TABLE"
+	HintInheritance   = "TABLE_INHERITANCE"
+)
+
+// Origen del valor de columnas generadas por la base de datos
+const (
+	GenerationSerial   = "SERIAL"   // serial / bigserial / smallserial
+	GenerationIdentity = "IDENTITY" // GENERATED {ALWAYS | BY DEFAULT} AS IDENTITY
+	GenerationSequence = "SEQUENCE" // DEFAULT nextval('seq')
+	GenerationStored   = "STORED"   // GENERATED ALWAYS AS (expr) STORED
+)
+
+// Como se mapean los schemas de PostgreSQL (billing.invoices) a DynamoDB
+const (
+	SchemaMappingPrefix = "prefix" // billing.invoices -> tabla billing_invoices
+	SchemaMappingEntity = "entity" // billing.invoices -> entity type BILLING#INVOICES
+)
+
+// Schema por defecto cuando la tabla no esta calificada ni hay search_path
+const defaultSchema = "public"
+
+// ValidSchemaMappings son los valores aceptados para schemaMapping
+var ValidSchemaMappings = map[string]bool{
+	SchemaMappingPrefix: true,
+	SchemaMappingEntity: true,
+}
+
+// ValidOutputLanguages son los idiomas aceptados para las descripciones y
+// reglas del diseño (outputLanguage); DefaultOutputLanguage si no se envia
+var ValidOutputLanguages = map[string]bool{
+	"es": true,
+	"en": true,
+}
+
+// DefaultOutputLanguage es el idioma del diseño si no se envia outputLanguage
+const DefaultOutputLanguage = "es"
+
+// ValidOptimizationTypes son los tipos de optimizacion aceptados por la conversion
+var ValidOptimizationTypes = map[string]bool{
+	"read_heavy":  true,
+	"write_heavy": true,
+	"balanced":    true,
+}
+
+// Tipos personalizados declarados en el mismo script
+const (
+	CustomTypeEnum   = "ENUM"
+	CustomTypeDomain = "DOMAIN"
+)
+
+// Retencion maxima aceptada para tablas time-series (10 años)
+const MaxRetentionDays = 3650
+
+// Severidad de errores de validacion
+const (
+	SeverityError   = "ERROR"
+	SeverityWarning = "WARNING"
+)
+
+// ============================================================================
+// Validation Result
+// ============================================================================
+
+// ValidationResult contiene el resultado completo de la validacion SQL
+type ValidationResult struct {
+	IsValid     bool               `json:"isValid"`
+	Tables      []TableInfo        `json:"tables,omitempty"`
+	Errors      []ValidationDetail `json:"errors,omitempty"`
+	Warnings    []ValidationDetail `json:"warnings,omitempty"`
+	Hints       []DesignHint       `json:"designHints,omitempty"`
+	CustomTypes []CustomType       `json:"customTypes,omitempty"`
+	Views       []ViewInfo         `json:"views,omitempty"`
+}
+
+// ValidationDetail describe un error o warning especifico
+type ValidationDetail struct {
+	Code     string `json:"code"`
+	Message  string `json:"message"`
+	Severity string `json:"severity"`
+	Table    string `json:"table,omitempty"`
+	Column   string `json:"column,omitempty"`
+}
+
+// TableInfo contiene metadata extraida de un CREATE TABLE
+type TableInfo struct {
+	Name            string           `json:"name"`
+	Schema          string           `json:"schema,omitempty"`
+	Columns         []ColumnInfo     `json:"columns"`
+	Constraints     []string         `json:"constraints,omitempty"`
+	HasPrimaryKey   bool             `json:"hasPrimaryKey"`
+	PrimaryKey      []string         `json:"primaryKey,omitempty"`
+	ForeignKeys     []ForeignKeyInfo `json:"foreignKeys,omitempty"`
+	ValidationRules []ValidationRule `json:"validationRules,omitempty"`
+	Description     string           `json:"description,omitempty"`
+
+	// PARTITION BY / PARTITION OF / INHERITS
+	PartitionStrategy string          `json:"partitionStrategy,omitempty"`
+	PartitionKey      []string        `json:"partitionKey,omitempty"`
+	Partitions        []PartitionInfo `json:"partitions,omitempty"`
+	Inherits          []string        `json:"inherits,omitempty"`
+
+	// Sentencia CREATE TABLE original, para convertir el esquema por partes
+	Statement string `json:"-"`
+}
+
+// PartitionInfo es una particion hija (PARTITION OF) fusionada en su tabla padre
+type PartitionInfo struct {
+	Name   string `json:"name"`
+	Schema string `json:"schema,omitempty"`
+	Bound  string `json:"bound"`
+}
+
+// QualifiedName retorna schema.tabla si la tabla tiene schema, o solo el nombre
+func (t TableInfo) QualifiedName() string {
+	if t.Schema == "" {
+		return t.Name
+	}
+	return t.Schema + "." + t.Name
+}
+
+// ForeignKeyInfo describe una relacion FK (inline o a nivel de tabla)
+type ForeignKeyInfo struct {
+	Columns    []string `json:"columns"`
+	RefSchema  string   `json:"refSchema,omitempty"`
+	RefTable   string   `json:"refTable"`
+	RefColumns []string `json:"refColumns,omitempty"`
+}
+
+// ColumnInfo contiene metadata de una columna
+type ColumnInfo struct {
+	Name           string   `json:"name"`
+	DataType       string   `json:"dataType"`
+	Raw            string   `json:"raw"`
+	BaseType       string   `json:"baseType,omitempty"`
+	EnumValues     []string `json:"enumValues,omitempty"`
+	Generation     string   `json:"generation,omitempty"`
+	GenerationExpr string   `json:"generationExpr,omitempty"`
+	SequenceName   string   `json:"sequenceName,omitempty"`
+	Description    string   `json:"description,omitempty"`
+}
+
+// ValidationRule es una regla derivada de un CHECK simple. DynamoDB no tiene
+// CHECK, asi que se documenta para validarla en la aplicacion.
+type ValidationRule struct {
+	Column   string   `json:"column"`
+	Kind     string   `json:"kind"`
+	Operator string   `json:"operator,omitempty"`
+	Values   []string `json:"values,omitempty"`
+	Negated  bool     `json:"negated,omitempty"`
+	Source   string   `json:"source"`
+}
+
+// CustomType describe un CREATE TYPE ... AS ENUM o CREATE DOMAIN del script
+type CustomType struct {
+	Name     string   `json:"name"`
+	Kind     string   `json:"kind"`
+	BaseType string   `json:"baseType,omitempty"`
+	Values   []string `json:"values,omitempty"`
+	Raw      string   `json:"raw"`
+}
+
+// ============================================================================
+// Design Hints
+// ============================================================================
+
+// DesignHint es una recomendacion de modelado detectada en el schema SQL.
+// Se envia al worker junto con el SQL y se guarda en el registro de conversion.
+type DesignHint struct {
+	Type           string            `json:"type"`
+	Table          string            `json:"table"`
+	Schema         string            `json:"schema,omitempty"`
+	Columns        []string          `json:"columns,omitempty"`
+	Description    string            `json:"description"`
+	KeyPatterns    []string          `json:"keyPatterns,omitempty"`
+	ExampleQueries []ExampleQuery    `json:"exampleQueries,omitempty"`
+	TTLAttribute   string            `json:"ttlAttribute,omitempty"`
+	RetentionDays  int               `json:"retentionDays,omitempty"`
+	AllowedValues  []string          `json:"allowedValues,omitempty"`
+	Rules          []ValidationRule  `json:"validationRules,omitempty"`
+	Descriptions   map[string]string `json:"attributeDescriptions,omitempty"`
+}
+
+// QualifiedTable retorna schema.tabla del hint, igual que TableInfo.QualifiedName
+func (h DesignHint) QualifiedTable() string {
+	if h.Schema == "" {
+		return h.Table
+	}
+	return h.Schema + "." + h.Table
+}
+
+// ViewInfo resume una vista (CREATE VIEW): que tabla lee y por que columnas
+// filtra u ordena. Cada vista es un patron de acceso que el diseño debe cubrir.
+type ViewInfo struct {
+	Name          string   `json:"name"`
+	Schema        string   `json:"schema,omitempty"`
+	Materialized  bool     `json:"materialized,omitempty"`
+	BaseTable     string   `json:"baseTable"`
+	BaseSchema    string   `json:"baseSchema,omitempty"`
+	JoinedTables  []string `json:"joinedTables,omitempty"`
+	FilterColumns []string `json:"filterColumns,omitempty"`
+	RangeColumns  []string `json:"rangeColumns,omitempty"`
+	OrderColumns  []string `json:"orderColumns,omitempty"`
+}
+
+// ExampleQuery documenta como resolver un patron de acceso con el diseño sugerido
+type ExampleQuery struct {
+	AccessPattern string `json:"accessPattern"`
+	Query         string `json:"query"`
+}
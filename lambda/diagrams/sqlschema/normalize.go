@@ -0,0 +1,63 @@
+package sqlschema
+
+import "strings"
+
+// ============================================================================
+// NORMALIZACION DEL SCRIPT (clave de cache)
+// ============================================================================
+
+// NormalizeSQL retorna el script sin comentarios, con los espacios colapsados
+// y en minusculas, para que dos scripts equivalentes tengan el mismo hash.
+// Los strings, identificadores con comillas y cuerpos con dollar-quoting se
+// conservan tal cual: su contenido si distingue mayusculas.
+func NormalizeSQL(sql string) string {
+	var b strings.Builder
+	pendingSpace := false
+
+	write := func(s string) {
+		if pendingSpace && b.Len() > 0 {
+			last := b.String()[b.Len()-1]
+			if !isSpaceNeutral(last) && !isSpaceNeutral(s[0]) {
+				b.WriteByte(' ')
+			}
+		}
+		pendingSpace = false
+		b.WriteString(s)
+	}
+
+	for i := 0; i < len(sql); i++ {
+		switch {
+		case sql[i] == '\'' || sql[i] == '"':
+			end := skipUntil(sql, i+1, sql[i:i+1])
+			write(sql[i:min(end+1, len(sql))])
+			i = end
+		case strings.HasPrefix(sql[i:], "--"):
+			i = skipUntil(sql, i, "\n")
+			pendingSpace = true
+		case strings.HasPrefix(sql[i:], "/*"):
+			i = skipUntil(sql, i+2, "*/")
+			pendingSpace = true
+		case sql[i] == '$' && (i == 0 || !isIdentifierByte(sql[i-1])) && dollarQuoteTagRegex.MatchString(sql[i:]):
+			tag := dollarQuoteTagRegex.FindString(sql[i:])
+			end := skipUntil(sql, i+len(tag), tag)
+			write(sql[i:min(end+1, len(sql))])
+			i = end
+		case sql[i] == ' ' || sql[i] == '\t' || sql[i] == '\r' || sql[i] == '\n':
+			pendingSpace = true
+		default:
+			ch := sql[i]
+			if ch >= 'A' && ch <= 'Z' {
+				ch += 'a' - 'A'
+			}
+			write(string(ch))
+		}
+	}
+
+	return b.String()
+}
+
+// isSpaceNeutral indica si los espacios junto al byte no cambian el script:
+// "t (id)" y "t(id)" son equivalentes
+func isSpaceNeutral(ch byte) bool {
+	return strings.IndexByte("(),;=", ch) >= 0
+}
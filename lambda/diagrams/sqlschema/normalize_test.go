@@ -0,0 +1,29 @@
+package sqlschema
+
+import "testing"
+
+func TestNormalizeSQL(t *testing.T) {
+	base := "create table users(id int primary key,note text default 'A;b');"
+
+	tests := []struct {
+		name string
+		sql  string
+		want string
+	}{
+		{"ya normalizado", base, base},
+		{"espacios y saltos de línea", "CREATE  TABLE users (\n    id INT PRIMARY KEY,\n    note TEXT DEFAULT 'A;b'\n);", base},
+		{"comentarios", "-- tabla de usuarios\nCREATE TABLE users ( /* pk */ id INT PRIMARY KEY, note TEXT DEFAULT 'A;b');", base},
+		{"el string conserva mayúsculas", "create table users(id int primary key,note text default 'a;b');", "create table users(id int primary key,note text default 'a;b');"},
+		{"identificador con comillas", `CREATE TABLE "Users" (id INT);`, `create table "Users"(id int);`},
+		{"dollar-quoting", "DO $$ BEGIN  PERFORM 1; END $$;", "do $$ BEGIN  PERFORM 1; END $$;"},
+		{"comentario dentro de un string", "SELECT '-- no';", "select '-- no';"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeSQL(tt.sql); got != tt.want {
+				t.Errorf("NormalizeSQL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
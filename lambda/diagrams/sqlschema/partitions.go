@@ -0,0 +1,151 @@
+package sqlschema
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ============================================================================
+// TABLAS PARTICIONADAS Y HERENCIA
+// ============================================================================
+
+// Estrategias de PARTITION BY
+const (
+	PartitionRange = "RANGE"
+	PartitionList  = "LIST"
+	PartitionHash  = "HASH"
+)
+
+var (
+	// CREATE TABLE hija PARTITION OF padre [(...)] { FOR VALUES ... | DEFAULT } [PARTITION BY ...]
+	partitionOfRegex    = regexp.MustCompile(`(?is)^CREATE\s+TABLE\s+(?:IF\s+NOT\s+EXISTS\s+)?(` + qualifiedIdentPattern + `)\s+PARTITION\s+OF\s+(` + qualifiedIdentPattern + `)\s*(.*?)\s*;?\s*$`)
+	partitionBoundRegex = regexp.MustCompile(`(?is)^(?:\(.*?\)\s*)?(FOR\s+VALUES\s+.+?|DEFAULT)(?:\s+PARTITION\s+BY\s+(?:RANGE|LIST|HASH)\s*\(.+\))?$`)
+
+	// Clausulas validas despues del cierre del cuerpo: INHERITS (...) PARTITION BY ...
+	tableOptionsRegex  = regexp.MustCompile(`(?is)^(?:INHERITS\s*\(([^)]*)\)\s*)?(?:PARTITION\s+BY\s+(RANGE|LIST|HASH)\s*\((.+)\))?\s*;?$`)
+	partitionTermRegex = regexp.MustCompile(`("(?:[^"]|"")+"|[A-Za-z_][\w$]*)(\s*\()?`)
+)
+
+// tableBodyEnd retorna la posicion del ')' que cierra el '(' en start,
+// ignorando parentesis dentro de strings e identificadores con comillas.
+func tableBodyEnd(stmt string, start int) int {
+	depth := 0
+	var quote byte
+
+	for i := start; i < len(stmt); i++ {
+		ch := stmt[i]
+		switch {
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			}
+		case ch == '\'' || ch == '"':
+			quote = ch
+		case ch == '(':
+			depth++
+		case ch == ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// tableOptions retorna lo que sigue al cuerpo de la tabla, sin el ';' final
+func tableOptions(stmt string) string {
+	start := strings.Index(stmt, "(")
+	if start == -1 {
+		return ""
+	}
+	end := tableBodyEnd(stmt, start)
+	if end == -1 {
+		return ""
+	}
+	return strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(stmt[end+1:]), ";"))
+}
+
+// extractTableOptions obtiene INHERITS y PARTITION BY de un CREATE TABLE.
+// ok es false si hay algo mas despues del cuerpo.
+func extractTableOptions(stmt string) (inherits []string, strategy string, key []string, ok bool) {
+	options := tableOptions(stmt)
+	if options == "" {
+		return nil, "", nil, true
+	}
+
+	m := tableOptionsRegex.FindStringSubmatch(options)
+	if m == nil {
+		return nil, "", nil, false
+	}
+
+	for _, parent := range strings.Split(m[1], ",") {
+		if parent = strings.TrimSpace(parent); parent != "" {
+			inherits = append(inherits, parent)
+		}
+	}
+	if m[2] != "" {
+		strategy = strings.ToUpper(m[2])
+		key = parsePartitionKey(m[3])
+		if len(key) == 0 {
+			return nil, "", nil, false
+		}
+	}
+
+	return inherits, strategy, key, true
+}
+
+// parsePartitionKey obtiene las columnas de la clave de particion. Para
+// expresiones (date_trunc('day', created_at)) usa la primera columna del
+// argumento; COLLATE y operator classes se ignoran.
+func parsePartitionKey(expr string) []string {
+	var cols []string
+
+	for _, part := range splitTableElements(expr) {
+		part = quotedValueRegex.ReplaceAllString(part, "")
+		for _, m := range partitionTermRegex.FindAllStringSubmatch(part, -1) {
+			if m[2] != "" {
+				continue // nombre de funcion
+			}
+			if col := foldIdentifier(m[1]); !containsString(cols, col) {
+				cols = append(cols, col)
+			}
+			break
+		}
+	}
+
+	return cols
+}
+
+// isPartitionOfStatement indica si la sentencia crea una particion hija
+func isPartitionOfStatement(stmt string) bool {
+	return partitionOfRegex.MatchString(stmt)
+}
+
+// parsePartitionOf extrae la particion y su tabla padre (schema, nombre) de un
+// CREATE TABLE ... PARTITION OF. ok es false si falta FOR VALUES / DEFAULT o
+// algun nombre es invalido.
+func parsePartitionOf(stmt string) (PartitionInfo, string, string, bool) {
+	m := partitionOfRegex.FindStringSubmatch(stmt)
+	if m == nil {
+		return PartitionInfo{}, "", "", false
+	}
+
+	bound := partitionBoundRegex.FindStringSubmatch(m[3])
+	if bound == nil {
+		return PartitionInfo{}, "", "", false
+	}
+
+	childParts := splitQualifiedName(m[1])
+	parentParts := splitQualifiedName(m[2])
+	if len(childParts) > 2 || len(parentParts) > 2 ||
+		!isValidIdentifier(childParts[len(childParts)-1]) || !isValidIdentifier(parentParts[len(parentParts)-1]) {
+		return PartitionInfo{}, "", "", false
+	}
+
+	partition := PartitionInfo{Bound: strings.Join(strings.Fields(bound[1]), " ")}
+	partition.Schema, partition.Name = splitSchemaAndName(m[1])
+	parentSchema, parentName := splitSchemaAndName(m[2])
+
+	return partition, parentSchema, parentName, true
+}
@@ -0,0 +1,169 @@
+package sqlschema
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractTableOptions(t *testing.T) {
+	tests := []struct {
+		name         string
+		stmt         string
+		wantInherits []string
+		wantStrategy string
+		wantKey      []string
+		wantOK       bool
+	}{
+		{"Sin opciones", "CREATE TABLE t (id INT);", nil, "", nil, true},
+		{"RANGE", "CREATE TABLE m (id INT, logdate DATE) PARTITION BY RANGE (logdate);", nil, PartitionRange, []string{"logdate"}, true},
+		{"LIST en minusculas", "CREATE TABLE m (id INT, region TEXT) partition by list (region);", nil, PartitionList, []string{"region"}, true},
+		{"HASH multiple", "CREATE TABLE m (a INT, b INT) PARTITION BY HASH (a, b);", nil, PartitionHash, []string{"a", "b"}, true},
+		{"Expresion", "CREATE TABLE m (ts TIMESTAMPTZ) PARTITION BY RANGE (date_trunc('day', ts));", nil, PartitionRange, []string{"ts"}, true},
+		{"INHERITS", "CREATE TABLE capitals (state CHAR(2)) INHERITS (cities);", []string{"cities"}, "", nil, true},
+		{"Basura", "CREATE TABLE t (id INT) WITHOUT OIDS;", nil, "", nil, false},
+		{"Estrategia invalida", "CREATE TABLE t (id INT) PARTITION BY TREE (id);", nil, "", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inherits, strategy, key, ok := extractTableOptions(tt.stmt)
+			if ok != tt.wantOK {
+				t.Fatalf("extractTableOptions() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !reflect.DeepEqual(inherits, tt.wantInherits) || strategy != tt.wantStrategy || !reflect.DeepEqual(key, tt.wantKey) {
+				t.Errorf("extractTableOptions() = %v, %q, %v; want %v, %q, %v",
+					inherits, strategy, key, tt.wantInherits, tt.wantStrategy, tt.wantKey)
+			}
+		})
+	}
+}
+
+func TestParsePartitionOf(t *testing.T) {
+	tests := []struct {
+		name       string
+		stmt       string
+		wantOK     bool
+		wantName   string
+		wantParent string
+		wantBound  string
+	}{
+		{
+			name:       "FOR VALUES FROM TO",
+			stmt:       "CREATE TABLE m_2024 PARTITION OF m FOR VALUES FROM ('2024-01-01') TO ('2025-01-01');",
+			wantOK:     true,
+			wantName:   "m_2024",
+			wantParent: "m",
+			wantBound:  "FOR VALUES FROM ('2024-01-01') TO ('2025-01-01')",
+		},
+		{
+			name:       "DEFAULT calificado",
+			stmt:       "CREATE TABLE sales.orders_other PARTITION OF sales.orders DEFAULT;",
+			wantOK:     true,
+			wantName:   "orders_other",
+			wantParent: "orders",
+			wantBound:  "DEFAULT",
+		},
+		{
+			name:       "Subparticion",
+			stmt:       "CREATE TABLE m_2024 PARTITION OF m FOR VALUES FROM (1) TO (10) PARTITION BY LIST (region);",
+			wantOK:     true,
+			wantName:   "m_2024",
+			wantParent: "m",
+			wantBound:  "FOR VALUES FROM (1) TO (10)",
+		},
+		{
+			name:   "Sin limites",
+			stmt:   "CREATE TABLE m_2024 PARTITION OF m;",
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			partition, _, parent, ok := parsePartitionOf(tt.stmt)
+			if ok != tt.wantOK {
+				t.Fatalf("parsePartitionOf() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if partition.Name != tt.wantName || parent != tt.wantParent || partition.Bound != tt.wantBound {
+				t.Errorf("parsePartitionOf() = %+v, parent %q", partition, parent)
+			}
+		})
+	}
+}
+
+func TestValidateSQL_PartitionedTable(t *testing.T) {
+	result := ValidateSQL(`
+		CREATE TABLE measurements (
+			sensor_id INT NOT NULL,
+			logdate DATE NOT NULL,
+			peak NUMERIC
+		) PARTITION BY RANGE (logdate);
+
+		CREATE TABLE measurements_2024 PARTITION OF measurements
+			FOR VALUES FROM ('2024-01-01') TO ('2025-01-01') PARTITION BY HASH (sensor_id);
+		CREATE TABLE measurements_2024_a PARTITION OF measurements_2024
+			FOR VALUES WITH (MODULUS 2, REMAINDER 0);
+		CREATE TABLE measurements_default PARTITION OF measurements DEFAULT;
+	`)
+
+	if !result.IsValid {
+		t.Fatalf("expected valid schema, got errors: %v", result.Errors)
+	}
+	if len(result.Tables) != 1 {
+		t.Fatalf("expected partitions merged into 1 table, got %d", len(result.Tables))
+	}
+
+	table := result.Tables[0]
+	if table.PartitionStrategy != PartitionRange || len(table.Partitions) != 3 {
+		t.Errorf("unexpected partitioning: %s %+v", table.PartitionStrategy, table.Partitions)
+	}
+
+	hints := hintsOfType(result.Hints, HintPartitioned)
+	if len(hints) != 1 || hints[0].TTLAttribute != "expiresAt" {
+		t.Fatalf("expected 1 time-range %s hint, got %+v", HintPartitioned, hints)
+	}
+}
+
+func TestValidateSQL_PartitionOfUnknownParent(t *testing.T) {
+	result := ValidateSQL(`
+		CREATE TABLE t (id INT PRIMARY KEY);
+		CREATE TABLE p1 PARTITION OF missing FOR VALUES IN (1);
+	`)
+
+	if result.IsValid {
+		t.Fatal("expected invalid schema")
+	}
+	if result.Errors[0].Code != ErrUnknownParentTable {
+		t.Errorf("expected %s, got %s", ErrUnknownParentTable, result.Errors[0].Code)
+	}
+}
+
+func TestValidateSQL_Inherits(t *testing.T) {
+	result := ValidateSQL(`
+		CREATE TABLE cities (id INT PRIMARY KEY, name TEXT, population INT);
+		CREATE TABLE capitals (state CHAR(2), population BIGINT) INHERITS (cities);
+		CREATE TABLE archived_cities () INHERITS (cities);
+	`)
+
+	if !result.IsValid {
+		t.Fatalf("expected valid schema, got errors: %v", result.Errors)
+	}
+
+	var names []string
+	for _, col := range result.Tables[1].Columns {
+		names = append(names, col.Name+" "+col.DataType)
+	}
+	want := []string{"id INT", "name TEXT", "population BIGINT", "state CHAR(2)"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("capitals columns = %v, want %v", names, want)
+	}
+	if len(result.Tables[2].Columns) != 3 {
+		t.Errorf("expected archived_cities to inherit 3 columns, got %d", len(result.Tables[2].Columns))
+	}
+	if hints := hintsOfType(result.Hints, HintInheritance); len(hints) != 2 {
+		t.Errorf("expected 2 %s hints, got %d", HintInheritance, len(hints))
+	}
+}
@@ -0,0 +1,464 @@
+package sqlschema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidateSQL orquesta la validacion completa de un schema SQL.
+// Retorna un ValidationResult con tablas extraidas, errores y warnings.
+func ValidateSQL(sqlContent string) ValidationResult {
+	result := ValidationResult{IsValid: true}
+
+	// 1. Contenido vacio
+	if strings.TrimSpace(sqlContent) == "" {
+		return validationFailed(ValidationDetail{
+			Code:     ErrEmptySQLContent,
+			Message:  "Field sqlContent is required",
+			Severity: SeverityError,
+		})
+	}
+
+	// 2. Debe contener al menos un CREATE TABLE. Las sentencias se separan
+	// respetando strings, comentarios y cuerpos $$ de funciones.
+	statements := splitStatements(sqlContent)
+	if !containsCreateTableStatement(sqlContent) {
+		return validationFailed(ValidationDetail{
+			Code:     ErrNoCreateTablesFound,
+			Message:  "No CREATE TABLE statements found",
+			Severity: SeverityError,
+		})
+	}
+
+	// 3. Tipos personalizados (CREATE TYPE ... AS ENUM / CREATE DOMAIN)
+	ddl := maskRoutines(sqlContent, statements)
+	types, invalidTypes := extractCustomTypes(ddl)
+	for _, ct := range invalidTypes {
+		result.IsValid = false
+		result.Errors = append(result.Errors, ValidationDetail{
+			Code:     ErrInvalidDataType,
+			Message:  fmt.Sprintf("Invalid base type %q for domain %q", ct.BaseType, ct.Name),
+			Severity: SeverityError,
+		})
+	}
+	result.CustomTypes = types
+	customTypes := indexCustomTypes(types)
+
+	// 4. Extraer y validar cada sentencia (respetando SET search_path)
+	searchPath := extractSearchPathChanges(ddl)
+	seenTables := make(map[string]bool)
+	partitionRoots := make(map[string]string) // particion -> tabla padre raiz
+
+	for _, statement := range statements {
+		if statement.kind == StmtCreateView {
+			if view, ok := parseView(statement.text); ok {
+				if view.Schema == "" {
+					view.Schema = schemaAt(searchPath, statement.offset)
+				}
+				if view.BaseSchema == "" {
+					view.BaseSchema = schemaAt(searchPath, statement.offset)
+				}
+				result.Views = append(result.Views, view)
+			}
+			continue
+		}
+		if statement.kind != StmtCreateTable || !statement.terminated {
+			continue
+		}
+		stmt := statement.text
+
+		// Particiones hijas: se fusionan con la tabla padre, ya declarada
+		if isPartitionOfStatement(stmt) {
+			if detail := attachPartition(&result, stmt, searchPath, statement.offset, seenTables, partitionRoots); detail != nil {
+				result.IsValid = false
+				result.Errors = append(result.Errors, *detail)
+			}
+			continue
+		}
+
+		// Validar caracteres invalidos despues del cierre (salvo INHERITS / PARTITION BY)
+		if hasTrailingGarbage(stmt) {
+			result.IsValid = false
+			result.Errors = append(result.Errors, ValidationDetail{
+				Code:     ErrInvalidSQLSyntax,
+				Message:  fmt.Sprintf("Unexpected characters after closing parenthesis in statement: %s", strings.TrimSpace(stmt)),
+				Severity: SeverityError,
+			})
+			continue
+		}
+
+		rawTableName := extractRawTableName(stmt)
+
+		// Validar nombre de tabla (reglas de identificadores con y sin comillas)
+		if rawTableName == "" || !isValidIdentifier(rawTableName) {
+			result.IsValid = false
+			result.Errors = append(result.Errors, ValidationDetail{
+				Code:     ErrInvalidTableName,
+				Message:  fmt.Sprintf("Invalid table name: %q", rawTableName),
+				Severity: SeverityError,
+				Table:    rawTableName,
+			})
+			continue
+		}
+		tableName := foldIdentifier(rawTableName)
+
+		if isPostgresReservedWord(rawTableName) {
+			result.Warnings = append(result.Warnings, reservedWordWarning(tableName, ""))
+		}
+
+		// Schema explicito o el activo por search_path
+		schema := extractTableSchema(stmt)
+		if schema == "" {
+			schema = schemaAt(searchPath, statement.offset)
+		}
+
+		// Detectar tablas duplicadas en todo el script
+		key := tableKey(schema, tableName)
+		if seenTables[key] {
+			result.IsValid = false
+			result.Errors = append(result.Errors, ValidationDetail{
+				Code:     ErrDuplicateTable,
+				Message:  fmt.Sprintf("Duplicate table %q", key),
+				Severity: SeverityError,
+				Table:    tableName,
+			})
+			continue
+		}
+		seenTables[key] = true
+
+		inherits, strategy, partitionKey, _ := extractTableOptions(stmt)
+
+		body := extractTableBody(stmt)
+		if body == "" && len(inherits) == 0 {
+			result.IsValid = false
+			result.Errors = append(result.Errors, ValidationDetail{
+				Code:     ErrInvalidSQLSyntax,
+				Message:  fmt.Sprintf("Table %q has empty body", tableName),
+				Severity: SeverityError,
+				Table:    tableName,
+			})
+			continue
+		}
+
+		elements := splitTableElements(body)
+		tableInfo := TableInfo{
+			Name:              tableName,
+			Schema:            schema,
+			PartitionStrategy: strategy,
+			PartitionKey:      partitionKey,
+			Statement:         strings.TrimSpace(stmt),
+		}
+		columnNames := make(map[string]bool)
+		hasPK := false
+
+		for _, elem := range elements {
+			elem = strings.TrimSpace(elem)
+			if elem == "" {
+				continue
+			}
+
+			if isTableLevelConstraint(elem) {
+				if !isValidTableConstraint(elem) {
+					result.IsValid = false
+					result.Errors = append(result.Errors, ValidationDetail{
+						Code:     ErrInvalidConstraintSyntax,
+						Message:  fmt.Sprintf("Invalid constraint in table %q: %s", tableName, elem),
+						Severity: SeverityError,
+						Table:    tableName,
+					})
+				}
+				upper := strings.ToUpper(elem)
+				if strings.Contains(upper, "PRIMARY KEY") {
+					hasPK = true
+					tableInfo.PrimaryKey = extractPrimaryKeyColumns(elem)
+				}
+				tableInfo.ForeignKeys = append(tableInfo.ForeignKeys, extractForeignKeys(elem, "")...)
+				result.Warnings = append(result.Warnings, collectCheckRules(&tableInfo, elem, "")...)
+				tableInfo.Constraints = append(tableInfo.Constraints, strings.TrimSpace(elem))
+			} else {
+				colName, valid := isValidColumnDefinition(elem, customTypes)
+				if !valid {
+					result.IsValid = false
+					tokens := tokenize(elem)
+					if len(tokens) >= 2 {
+						dt := extractDataType(tokens[1:])
+						if !isKnownDataType(dt, customTypes) {
+							result.Errors = append(result.Errors, ValidationDetail{
+								Code:     ErrInvalidDataType,
+								Message:  fmt.Sprintf("Invalid data type %q for column %q in table %q", dt, tokens[0], tableName),
+								Severity: SeverityError,
+								Table:    tableName,
+								Column:   tokens[0],
+							})
+						} else {
+							result.Errors = append(result.Errors, ValidationDetail{
+								Code:     ErrInvalidColumnName,
+								Message:  fmt.Sprintf("Invalid column definition in table %q: %s", tableName, elem),
+								Severity: SeverityError,
+								Table:    tableName,
+							})
+						}
+					} else {
+						result.Errors = append(result.Errors, ValidationDetail{
+							Code:     ErrInvalidSQLSyntax,
+							Message:  fmt.Sprintf("Incomplete column definition in table %q: %s", tableName, elem),
+							Severity: SeverityError,
+							Table:    tableName,
+						})
+					}
+					continue
+				}
+
+				if columnNames[colName] {
+					result.IsValid = false
+					result.Errors = append(result.Errors, ValidationDetail{
+						Code:     ErrDuplicateColumn,
+						Message:  fmt.Sprintf("Duplicate column %q in table %q", colName, tableName),
+						Severity: SeverityError,
+						Table:    tableName,
+						Column:   colName,
+					})
+					continue
+				}
+				columnNames[colName] = true
+
+				// Extraer info de columna
+				tokens := tokenize(elem)
+
+				if isPostgresReservedWord(tokens[0]) {
+					result.Warnings = append(result.Warnings, reservedWordWarning(tableName, colName))
+				}
+				dt := ""
+				if len(tokens) >= 2 {
+					dt = extractDataType(tokens[1:])
+				}
+
+				// Detectar PK inline
+				if strings.Contains(strings.ToUpper(elem), "PRIMARY KEY") {
+					hasPK = true
+					tableInfo.PrimaryKey = []string{colName}
+				}
+				tableInfo.ForeignKeys = append(tableInfo.ForeignKeys, extractForeignKeys(elem, colName)...)
+				result.Warnings = append(result.Warnings, collectCheckRules(&tableInfo, elem, colName)...)
+
+				colInfo := ColumnInfo{
+					Name:     colName,
+					DataType: dt,
+					Raw:      strings.TrimSpace(elem),
+				}
+				if ct, ok := lookupCustomType(dt, customTypes); ok {
+					colInfo.BaseType = ct.BaseType
+					colInfo.EnumValues = ct.Values
+				}
+				colInfo.Generation, colInfo.GenerationExpr, colInfo.SequenceName = extractColumnGeneration(elem, dt)
+				tableInfo.Columns = append(tableInfo.Columns, colInfo)
+			}
+		}
+
+		// INHERITS: las columnas del padre van primero, como en PostgreSQL
+		unknownParent := false
+		for _, ref := range inherits {
+			parentSchema, parentName := splitSchemaAndName(ref)
+			if parentSchema == "" {
+				parentSchema = schemaAt(searchPath, statement.offset)
+			}
+			parent, ok := findTableByKey(result.Tables, tableKey(parentSchema, parentName))
+			if !ok {
+				unknownParent = true
+				result.IsValid = false
+				result.Errors = append(result.Errors, ValidationDetail{
+					Code:     ErrUnknownParentTable,
+					Message:  fmt.Sprintf("Table %q inherits from unknown table %q", tableName, parentName),
+					Severity: SeverityError,
+					Table:    tableName,
+				})
+				continue
+			}
+			tableInfo.Inherits = append(tableInfo.Inherits, parent.QualifiedName())
+			tableInfo.Columns = mergeInheritedColumns(parent.Columns, tableInfo.Columns)
+		}
+		if unknownParent {
+			continue
+		}
+
+		if len(tableInfo.Columns) == 0 {
+			result.IsValid = false
+			result.Errors = append(result.Errors, ValidationDetail{
+				Code:     ErrInvalidSQLSyntax,
+				Message:  fmt.Sprintf("Table %q has no valid columns", tableName),
+				Severity: SeverityError,
+				Table:    tableName,
+			})
+			continue
+		}
+
+		tableInfo.HasPrimaryKey = hasPK
+		if !hasPK {
+			result.Warnings = append(result.Warnings, ValidationDetail{
+				Code:     WarnNoPrimaryKey,
+				Message:  fmt.Sprintf("Table %q has no PRIMARY KEY defined", tableName),
+				Severity: SeverityWarning,
+				Table:    tableName,
+			})
+		}
+
+		result.Tables = append(result.Tables, tableInfo)
+	}
+
+	// 5. COMMENT ON TABLE / COLUMN como descripciones
+	applyComments(result.Tables, statements, searchPath)
+
+	// 6. Detectar patrones de modelado sobre las tablas validas
+	result.Hints = append(result.Hints, detectSelfReferences(result.Tables)...)
+	result.Hints = append(result.Hints, detectTimeSeries(result.Tables)...)
+	result.Hints = append(result.Hints, detectEnumAttributes(result.Tables)...)
+	result.Hints = append(result.Hints, detectGeneratedColumns(result.Tables)...)
+	result.Hints = append(result.Hints, detectValidationRules(result.Tables)...)
+	result.Hints = append(result.Hints, detectViewAccessPatterns(result.Tables, result.Views)...)
+	result.Hints = append(result.Hints, detectDescriptions(result.Tables)...)
+	result.Hints = append(result.Hints, detectPartitionedTables(result.Tables)...)
+	result.Hints = append(result.Hints, detectInheritance(result.Tables)...)
+	result.Hints = append(result.Hints, detectMultiSchema(result.Tables, SchemaMappingPrefix)...)
+
+	// 7. Palabras reservadas de DynamoDB en los nombres de atributo finales
+	result.Warnings = append(result.Warnings, detectReservedAttributes(result.Tables, result.Hints)...)
+
+	return result
+}
+
+// attachPartition agrega una particion hija (PARTITION OF) a su tabla padre.
+// Las subparticiones se agregan a la tabla raiz. Retorna el error si la
+// sentencia es invalida o el padre no existe.
+func attachPartition(result *ValidationResult, stmt string, searchPath []searchPathChange, offset int, seenTables map[string]bool, roots map[string]string) *ValidationDetail {
+	partition, parentSchema, parentName, ok := parsePartitionOf(stmt)
+	if !ok {
+		return &ValidationDetail{
+			Code:     ErrInvalidSQLSyntax,
+			Message:  fmt.Sprintf("Invalid partition definition: %s", strings.TrimSpace(stmt)),
+			Severity: SeverityError,
+		}
+	}
+
+	if partition.Schema == "" {
+		partition.Schema = schemaAt(searchPath, offset)
+	}
+	if parentSchema == "" {
+		parentSchema = schemaAt(searchPath, offset)
+	}
+
+	key := tableKey(partition.Schema, partition.Name)
+	if seenTables[key] {
+		return &ValidationDetail{
+			Code:     ErrDuplicateTable,
+			Message:  fmt.Sprintf("Duplicate table %q", key),
+			Severity: SeverityError,
+			Table:    partition.Name,
+		}
+	}
+	seenTables[key] = true
+
+	parentKey := tableKey(parentSchema, parentName)
+	if root, isPartition := roots[parentKey]; isPartition {
+		parentKey = root
+	}
+
+	for i := range result.Tables {
+		if tableKey(result.Tables[i].Schema, result.Tables[i].Name) == parentKey {
+			result.Tables[i].Partitions = append(result.Tables[i].Partitions, partition)
+			roots[key] = parentKey
+			return nil
+		}
+	}
+
+	return &ValidationDetail{
+		Code:     ErrUnknownParentTable,
+		Message:  fmt.Sprintf("Partition %q references unknown parent table %q", partition.Name, parentName),
+		Severity: SeverityError,
+		Table:    partition.Name,
+	}
+}
+
+// findTableByKey busca una tabla por su clave schema.nombre
+func findTableByKey(tables []TableInfo, key string) (TableInfo, bool) {
+	for _, table := range tables {
+		if tableKey(table.Schema, table.Name) == key {
+			return table, true
+		}
+	}
+	return TableInfo{}, false
+}
+
+// mergeInheritedColumns combina las columnas del padre con las de la hija. Una
+// columna redefinida en la hija conserva la posicion del padre.
+func mergeInheritedColumns(parent, child []ColumnInfo) []ColumnInfo {
+	childByName := make(map[string]ColumnInfo)
+	for _, col := range child {
+		childByName[col.Name] = col
+	}
+
+	merged := make([]ColumnInfo, 0, len(parent)+len(child))
+	inParent := make(map[string]bool)
+	for _, col := range parent {
+		if redefined, ok := childByName[col.Name]; ok {
+			col = redefined
+		}
+		merged = append(merged, col)
+		inParent[col.Name] = true
+	}
+	for _, col := range child {
+		if !inParent[col.Name] {
+			merged = append(merged, col)
+		}
+	}
+	return merged
+}
+
+// collectCheckRules parsea los CHECK de un elemento y agrega las reglas a la
+// tabla. Retorna un warning por cada expresion que no se pudo traducir.
+func collectCheckRules(table *TableInfo, elem, colName string) []ValidationDetail {
+	var warnings []ValidationDetail
+
+	for _, expr := range extractCheckExpressions(elem) {
+		rules, ok := parseCheckExpression(expr)
+		if !ok {
+			warnings = append(warnings, ValidationDetail{
+				Code:     WarnUnsupportedCheck,
+				Message:  fmt.Sprintf("CHECK (%s) in table %q cannot be translated to a validation rule; enforce it in the application", expr, table.Name),
+				Severity: SeverityWarning,
+				Table:    table.Name,
+				Column:   colName,
+			})
+			continue
+		}
+		table.ValidationRules = append(table.ValidationRules, rules...)
+	}
+
+	return warnings
+}
+
+// reservedWordWarning avisa de un identificador sin comillas que es palabra
+// reservada de PostgreSQL (order, user). Column vacio indica la tabla.
+func reservedWordWarning(tableName, colName string) ValidationDetail {
+	if colName == "" {
+		return ValidationDetail{
+			Code:     WarnReservedWord,
+			Message:  fmt.Sprintf("Table name %q is a PostgreSQL reserved word; quote it (\"%s\") or rename it", tableName, tableName),
+			Severity: SeverityWarning,
+			Table:    tableName,
+		}
+	}
+	return ValidationDetail{
+		Code:     WarnReservedWord,
+		Message:  fmt.Sprintf("Column %q in table %q is a PostgreSQL reserved word; quote it (\"%s\") or rename it", colName, tableName, colName),
+		Severity: SeverityWarning,
+		Table:    tableName,
+		Column:   colName,
+	}
+}
+
+func validationFailed(detail ValidationDetail) ValidationResult {
+	return ValidationResult{
+		IsValid: false,
+		Errors:  []ValidationDetail{detail},
+	}
+}
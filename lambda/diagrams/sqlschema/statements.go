@@ -0,0 +1,358 @@
+package sqlschema
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ============================================================================
+// DIVISION DEL SCRIPT EN SENTENCIAS
+// ============================================================================
+
+// Tipos de sentencia reconocidos en un dump de PostgreSQL
+const (
+	StmtCreateTable = "CREATE_TABLE"
+	StmtCreateView  = "CREATE_VIEW"
+	StmtRoutine     = "ROUTINE" // CREATE FUNCTION / PROCEDURE, DO
+	StmtTrigger     = "TRIGGER"
+	StmtComment     = "COMMENT"
+	StmtOther       = "OTHER"
+)
+
+// Identificador opcionalmente calificado: schema.nombre, "Schema"."Nombre"
+const qualifiedIdentPattern = `(?:"(?:[^"]|"")+"|[\w$]+)(?:\.(?:"(?:[^"]|"")+"|[\w$]+)){0,2}`
+
+var (
+	dollarQuoteTagRegex = regexp.MustCompile(`^\$(?:[A-Za-z_][A-Za-z0-9_]*)?\$`)
+
+	statementKinds = []struct {
+		kind  string
+		regex *regexp.Regexp
+	}{
+		{StmtCreateTable, regexp.MustCompile(`(?is)^CREATE\s+TABLE\s`)},
+		{StmtCreateView, regexp.MustCompile(`(?is)^CREATE\s+(?:OR\s+REPLACE\s+)?(?:TEMP(?:ORARY)?\s+)?(?:RECURSIVE\s+)?(?:MATERIALIZED\s+)?VIEW\s`)},
+		{StmtRoutine, regexp.MustCompile(`(?is)^(?:CREATE\s+(?:OR\s+REPLACE\s+)?(?:FUNCTION|PROCEDURE)\s|DO\s)`)},
+		{StmtTrigger, regexp.MustCompile(`(?is)^CREATE\s+(?:OR\s+REPLACE\s+)?(?:CONSTRAINT\s+)?TRIGGER\s`)},
+		{StmtComment, regexp.MustCompile(`(?is)^COMMENT\s+ON\s`)},
+	}
+
+	createViewRegex = regexp.MustCompile(`(?is)^CREATE\s+(?:OR\s+REPLACE\s+)?(?:TEMP(?:ORARY)?\s+)?(?:RECURSIVE\s+)?(MATERIALIZED\s+)?VIEW\s+(?:IF\s+NOT\s+EXISTS\s+)?(` + qualifiedIdentPattern + `)(?:\s*\([^)]*\))?\s+(?:WITH\s*\([^)]*\)\s+)?AS\s+(.*?)\s*;?\s*$`)
+	viewSourceRegex = regexp.MustCompile(`(?i)\b(FROM|JOIN)\s+(` + qualifiedIdentPattern + `)(?:\s+(?:AS\s+)?("(?:[^"]|"")+"|[A-Za-z_]\w*))?`)
+	viewWhereRegex  = regexp.MustCompile(`(?is)\bWHERE\s+(.*?)(?:\bGROUP\s+BY\b|\bORDER\s+BY\b|\bLIMIT\b|\bHAVING\b|\bUNION\b|\bWITH\s+(?:NO\s+)?DATA\b|$)`)
+	viewOrderRegex  = regexp.MustCompile(`(?is)\bORDER\s+BY\s+(.*?)(?:\bLIMIT\b|\bOFFSET\b|\bWITH\s+(?:NO\s+)?DATA\b|$)`)
+	viewEqualsRegex = regexp.MustCompile(`(?i)(?:("(?:[^"]|"")+"|[A-Za-z_]\w*)\.)?("(?:[^"]|"")+"|[A-Za-z_]\w*)\s*(?:=|\bIN\s*\()`)
+	viewRangeRegex  = regexp.MustCompile(`(?i)(?:("(?:[^"]|"")+"|[A-Za-z_]\w*)\.)?("(?:[^"]|"")+"|[A-Za-z_]\w*)\s*(?:>=|<=|>|<|\bBETWEEN\b)`)
+
+	commentOnRegex = regexp.MustCompile(`(?is)^COMMENT\s+ON\s+(TABLE|COLUMN)\s+(` + qualifiedIdentPattern + `)\s+IS\s+(NULL|E?'(?:[^']|'')*')\s*;?\s*$`)
+)
+
+// Palabras que pueden seguir a FROM/JOIN y no son alias
+var viewClauseKeywords = map[string]bool{
+	"where": true, "on": true, "using": true, "join": true, "inner": true,
+	"left": true, "right": true, "full": true, "cross": true, "natural": true,
+	"group": true, "order": true, "limit": true, "offset": true, "having": true,
+	"union": true, "except": true, "intersect": true, "window": true, "with": true,
+	"and": true, "or": true, "not": true, "in": true, "is": true, "between": true,
+	"like": true, "ilike": true, "as": true,
+}
+
+// sqlStatement es una sentencia del script con su posicion y tipo
+type sqlStatement struct {
+	kind       string
+	text       string
+	offset     int
+	terminated bool // termina en ';'
+}
+
+// splitStatements divide el script en sentencias por ';' ignorando los que
+// aparecen dentro de strings, identificadores con comillas, comentarios y
+// cuerpos con dollar-quoting ($$ ... $$, $body$ ... $body$). Un regex lazy
+// hasta ';' cortaria un CREATE FUNCTION a la mitad del cuerpo.
+func splitStatements(sql string) []sqlStatement {
+	var statements []sqlStatement
+	start := 0
+
+	for i := 0; i < len(sql); i++ {
+		switch {
+		case sql[i] == '\'' || sql[i] == '"':
+			i = skipUntil(sql, i+1, sql[i:i+1])
+		case strings.HasPrefix(sql[i:], "--"):
+			i = skipUntil(sql, i, "\n")
+		case strings.HasPrefix(sql[i:], "/*"):
+			i = skipUntil(sql, i+2, "*/")
+		case sql[i] == '$' && (i == 0 || !isIdentifierByte(sql[i-1])):
+			if tag := dollarQuoteTagRegex.FindString(sql[i:]); tag != "" {
+				i = skipUntil(sql, i+len(tag), tag)
+			}
+		case sql[i] == ';':
+			statements = appendStatement(statements, sql, start, i+1, true)
+			start = i + 1
+		}
+	}
+
+	return appendStatement(statements, sql, start, len(sql), false)
+}
+
+// skipUntil retorna la posicion del ultimo byte de la primera aparicion de
+// closing desde from, o el final del script si no esta cerrada.
+func skipUntil(sql string, from int, closing string) int {
+	if from > len(sql) {
+		return len(sql)
+	}
+	idx := strings.Index(sql[from:], closing)
+	if idx == -1 {
+		return len(sql)
+	}
+	return from + idx + len(closing) - 1
+}
+
+func isIdentifierByte(ch byte) bool {
+	return ch == '_' || ch == '$' || (ch >= '0' && ch <= '9') || (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z')
+}
+
+// appendStatement agrega sql[start:end] sin los espacios y comentarios iniciales
+func appendStatement(statements []sqlStatement, sql string, start, end int, terminated bool) []sqlStatement {
+	for start < end {
+		rest := sql[start:end]
+		trimmed := strings.TrimLeft(rest, " \t\r\n")
+		start += len(rest) - len(trimmed)
+
+		switch {
+		case strings.HasPrefix(trimmed, "--"):
+			start = skipUntil(sql[:end], start, "\n") + 1
+		case strings.HasPrefix(trimmed, "/*"):
+			start = skipUntil(sql[:end], start+2, "*/") + 1
+		default:
+			text := sql[start:end]
+			if strings.TrimSpace(strings.TrimSuffix(text, ";")) == "" {
+				return statements
+			}
+			return append(statements, sqlStatement{
+				kind:       statementKind(text),
+				text:       text,
+				offset:     start,
+				terminated: terminated,
+			})
+		}
+	}
+	return statements
+}
+
+func statementKind(text string) string {
+	for _, k := range statementKinds {
+		if k.regex.MatchString(text) {
+			return k.kind
+		}
+	}
+	return StmtOther
+}
+
+// maskRoutines reemplaza por espacios el texto de funciones y bloques DO,
+// conservando las posiciones. Asi los regex de CREATE TYPE / search_path no
+// ven el SQL dinamico de los cuerpos.
+func maskRoutines(sql string, statements []sqlStatement) string {
+	masked := []byte(sql)
+	for _, stmt := range statements {
+		if stmt.kind != StmtRoutine {
+			continue
+		}
+		for i := stmt.offset; i < stmt.offset+len(stmt.text); i++ {
+			if masked[i] != '\n' {
+				masked[i] = ' '
+			}
+		}
+	}
+	return string(masked)
+}
+
+// ============================================================================
+// VISTAS
+// ============================================================================
+
+// parseView extrae la tabla base, joins y columnas de filtro/orden de un
+// CREATE VIEW. Es un analisis superficial: solo busca la forma de la consulta.
+func parseView(stmt string) (ViewInfo, bool) {
+	m := createViewRegex.FindStringSubmatch(stmt)
+	if m == nil {
+		return ViewInfo{}, false
+	}
+
+	view := ViewInfo{Materialized: m[1] != ""}
+	view.Schema, view.Name = splitSchemaAndName(m[2])
+	query := m[3]
+	// Solo interesa la consulta principal: subconsultas y EXTRACT(x FROM col)
+	// quedan en blanco, conservando las posiciones.
+	topLevel := blankNested(query)
+
+	// alias -> tabla, para resolver columnas calificadas (o.user_id)
+	aliases := make(map[string]string)
+	for _, src := range viewSourceRegex.FindAllStringSubmatch(topLevel, -1) {
+		schema, table := splitSchemaAndName(src[2])
+		if viewClauseKeywords[table] {
+			continue
+		}
+		aliases[table] = table
+		if alias := foldIdentifier(src[3]); alias != "" && !viewClauseKeywords[alias] {
+			aliases[alias] = table
+		}
+
+		if view.BaseTable == "" {
+			view.BaseTable, view.BaseSchema = table, schema
+		} else if table != view.BaseTable && !containsString(view.JoinedTables, table) {
+			view.JoinedTables = append(view.JoinedTables, table)
+		}
+	}
+	if view.BaseTable == "" {
+		return ViewInfo{}, false
+	}
+
+	if loc := viewWhereRegex.FindStringSubmatchIndex(topLevel); loc != nil {
+		where := query[loc[2]:loc[3]]
+		view.FilterColumns = viewColumns(viewEqualsRegex, where, view.BaseTable, aliases)
+		view.RangeColumns = viewColumns(viewRangeRegex, where, view.BaseTable, aliases)
+	}
+
+	if loc := viewOrderRegex.FindStringSubmatchIndex(topLevel); loc != nil {
+		for _, part := range strings.Split(query[loc[2]:loc[3]], ",") {
+			fields := strings.Fields(part)
+			if len(fields) == 0 {
+				continue
+			}
+			if col, ok := resolveViewColumn(fields[0], view.BaseTable, aliases); ok && !containsString(view.OrderColumns, col) {
+				view.OrderColumns = append(view.OrderColumns, col)
+			}
+		}
+	}
+
+	return view, true
+}
+
+// blankNested reemplaza por espacios el contenido entre parentesis (y los
+// strings) para buscar clausulas solo en el nivel superior de la consulta.
+func blankNested(query string) string {
+	blanked := []byte(query)
+	depth := 0
+	inQuotes := false
+
+	for i := 0; i < len(blanked); i++ {
+		ch := blanked[i]
+		switch {
+		case ch == '\'':
+			inQuotes = !inQuotes
+		case ch == '(' && !inQuotes:
+			depth++
+			continue
+		case ch == ')' && !inQuotes:
+			depth--
+			if depth < 0 {
+				depth = 0
+			}
+			continue
+		}
+		if depth > 0 || inQuotes || ch == '\'' {
+			blanked[i] = ' '
+		}
+	}
+	return string(blanked)
+}
+
+// viewColumns obtiene las columnas de la tabla base que aparecen en el WHERE
+// con el tipo de comparacion del regex.
+func viewColumns(re *regexp.Regexp, where, baseTable string, aliases map[string]string) []string {
+	var cols []string
+	for _, m := range re.FindAllStringSubmatch(where, -1) {
+		ref := m[2]
+		if m[1] != "" {
+			ref = m[1] + "." + m[2]
+		}
+		col, ok := resolveViewColumn(ref, baseTable, aliases)
+		if ok && !viewClauseKeywords[col] && !containsString(cols, col) {
+			cols = append(cols, col)
+		}
+	}
+	return cols
+}
+
+// resolveViewColumn retorna la columna si pertenece a la tabla base: sin
+// calificar o calificada con su nombre o alias.
+func resolveViewColumn(ref, baseTable string, aliases map[string]string) (string, bool) {
+	parts := splitQualifiedName(strings.TrimSpace(ref))
+	col := foldIdentifier(parts[len(parts)-1])
+	if !isValidIdentifier(col) {
+		return "", false
+	}
+	if len(parts) == 1 {
+		return col, true
+	}
+	return col, aliases[foldIdentifier(parts[len(parts)-2])] == baseTable
+}
+
+// splitSchemaAndName separa schema.nombre con case folding; schema vacio si no
+// esta calificado.
+func splitSchemaAndName(ref string) (string, string) {
+	parts := splitQualifiedName(ref)
+	name := foldIdentifier(parts[len(parts)-1])
+	if len(parts) == 1 {
+		return "", name
+	}
+	return foldIdentifier(parts[len(parts)-2]), name
+}
+
+func containsString(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// ============================================================================
+// COMMENT ON
+// ============================================================================
+
+// applyComments copia los COMMENT ON TABLE / COLUMN como descripcion de la
+// tabla o columna correspondiente. Comentarios sobre objetos desconocidos se
+// ignoran.
+func applyComments(tables []TableInfo, statements []sqlStatement, searchPath []searchPathChange) {
+	for _, stmt := range statements {
+		if stmt.kind != StmtComment {
+			continue
+		}
+		m := commentOnRegex.FindStringSubmatch(stmt.text)
+		if m == nil || strings.EqualFold(m[3], "NULL") {
+			continue
+		}
+		text := strings.ReplaceAll(strings.TrimPrefix(strings.TrimPrefix(m[3], "E"), "e"), "''", "'")
+		text = text[1 : len(text)-1]
+
+		parts := splitQualifiedName(m[2])
+		column := ""
+		if strings.EqualFold(m[1], "COLUMN") {
+			if len(parts) < 2 {
+				continue
+			}
+			column = foldIdentifier(parts[len(parts)-1])
+			parts = parts[:len(parts)-1]
+		}
+
+		schema, name := splitSchemaAndName(strings.Join(parts, "."))
+		if schema == "" {
+			schema = schemaAt(searchPath, stmt.offset)
+		}
+
+		for i := range tables {
+			if tableKey(tables[i].Schema, tables[i].Name) != tableKey(schema, name) {
+				continue
+			}
+			if column == "" {
+				tables[i].Description = text
+			}
+			for j := range tables[i].Columns {
+				if tables[i].Columns[j].Name == column {
+					tables[i].Columns[j].Description = text
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package sqlschema
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitStatements(t *testing.T) {
+	sql := `-- dump de prueba
+CREATE TABLE users (id INT PRIMARY KEY, note TEXT DEFAULT 'a;b');
+
+CREATE FUNCTION touch() RETURNS trigger AS $$
+BEGIN
+  NEW.updated_at := now();
+  EXECUTE 'CREATE TABLE tmp (id INT);';
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER users_touch BEFORE UPDATE ON users FOR EACH ROW EXECUTE FUNCTION touch();
+/* vista; con punto y coma */
+CREATE VIEW active_users AS SELECT * FROM users;
+COMMENT ON COLUMN users.note IS 'Nota; libre';
+DO $body$ BEGIN PERFORM 1; END $body$;
+CREATE TABLE "odd;name" (id INT);
+SELECT 1`
+
+	got := splitStatements(sql)
+	wantKinds := []string{
+		StmtCreateTable, StmtRoutine, StmtTrigger, StmtCreateView,
+		StmtComment, StmtRoutine, StmtCreateTable, StmtOther,
+	}
+
+	var kinds []string
+	for _, stmt := range got {
+		kinds = append(kinds, stmt.kind)
+	}
+	if !reflect.DeepEqual(kinds, wantKinds) {
+		t.Fatalf("splitStatements() kinds = %v, want %v", kinds, wantKinds)
+	}
+
+	if got[0].text != `CREATE TABLE users (id INT PRIMARY KEY, note TEXT DEFAULT 'a;b');` {
+		t.Errorf("unexpected first statement: %q", got[0].text)
+	}
+	if sql[got[3].offset:got[3].offset+len(got[3].text)] != got[3].text {
+		t.Errorf("offset of %q does not match the script", got[3].text)
+	}
+	if got[len(got)-1].terminated {
+		t.Errorf("last statement without ';' should not be terminated")
+	}
+}
+
+func TestValidateSQL_IgnoresFunctionBodies(t *testing.T) {
+	result := ValidateSQL(`
+		CREATE TABLE users (id INT PRIMARY KEY, updated_at TIMESTAMPTZ);
+
+		CREATE OR REPLACE FUNCTION audit() RETURNS trigger AS $fn$
+		BEGIN
+			CREATE TABLE IF NOT EXISTS audit_tmp (id INT);
+			RETURN NEW;
+		END;
+		$fn$ LANGUAGE plpgsql;
+
+		CREATE TRIGGER users_audit AFTER INSERT ON users
+			FOR EACH ROW EXECUTE FUNCTION audit();
+	`)
+
+	if !result.IsValid {
+		t.Fatalf("expected valid schema, got errors: %v", result.Errors)
+	}
+	if len(result.Tables) != 1 || result.Tables[0].Name != "users" {
+		t.Errorf("expected only table users, got %v", result.Tables)
+	}
+}
+
+func TestParseView(t *testing.T) {
+	tests := []struct {
+		name string
+		stmt string
+		want ViewInfo
+	}{
+		{
+			name: "Filtro por igualdad y orden",
+			stmt: `CREATE VIEW recent_orders AS
+				SELECT o.id, o.total, EXTRACT(YEAR FROM o.created_at) AS year
+				FROM orders o
+				JOIN users u ON u.id = o.user_id
+				WHERE o.status = 'paid' AND o.created_at > now() - interval '7 days'
+				ORDER BY o.created_at DESC;`,
+			want: ViewInfo{
+				Name:          "recent_orders",
+				BaseTable:     "orders",
+				JoinedTables:  []string{"users"},
+				FilterColumns: []string{"status"},
+				RangeColumns:  []string{"created_at"},
+				OrderColumns:  []string{"created_at"},
+			},
+		},
+		{
+			name: "Vista materializada calificada",
+			stmt: `CREATE MATERIALIZED VIEW sales.daily_totals AS
+				SELECT store_id, sum(total) FROM sales.orders GROUP BY store_id WITH DATA;`,
+			want: ViewInfo{
+				Name:         "daily_totals",
+				Schema:       "sales",
+				Materialized: true,
+				BaseTable:    "orders",
+				BaseSchema:   "sales",
+			},
+		},
+		{
+			name: "Subconsulta en el SELECT",
+			stmt: `CREATE VIEW user_stats AS
+				SELECT u.id, (SELECT count(*) FROM orders WHERE orders.user_id = u.id) AS orders
+				FROM users u WHERE u.tenant_id = 1;`,
+			want: ViewInfo{
+				Name:          "user_stats",
+				BaseTable:     "users",
+				FilterColumns: []string{"tenant_id"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseView(tt.stmt)
+			if !ok {
+				t.Fatalf("parseView() failed for %q", tt.stmt)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseView() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateSQL_ViewAccessPatterns(t *testing.T) {
+	result := ValidateSQL(`
+		CREATE TABLE orders (id INT PRIMARY KEY, customer_id INT, placed_at TIMESTAMPTZ);
+		CREATE VIEW customer_orders AS
+			SELECT * FROM orders WHERE customer_id = 42 ORDER BY placed_at DESC;
+		CREATE VIEW ghost AS SELECT * FROM missing_table;
+	`)
+
+	if len(result.Views) != 2 {
+		t.Fatalf("expected 2 views, got %d", len(result.Views))
+	}
+
+	hints := hintsOfType(result.Hints, HintViewAccess)
+	if len(hints) != 1 {
+		t.Fatalf("expected 1 %s hint, got %d", HintViewAccess, len(hints))
+	}
+	if want := "GSI customer_id-index with PK = <customer_id>, SK = <placed_at>"; hints[0].KeyPatterns[0] != want {
+		t.Errorf("key pattern = %q, want %q", hints[0].KeyPatterns[0], want)
+	}
+}
+
+func TestValidateSQL_CommentOn(t *testing.T) {
+	result := ValidateSQL(`
+		CREATE TABLE users (id INT PRIMARY KEY, email TEXT, nick TEXT);
+		COMMENT ON TABLE users IS 'Registered accounts';
+		COMMENT ON COLUMN public.users.email IS 'Login e-mail, it''s unique';
+		COMMENT ON COLUMN users.nick IS NULL;
+		COMMENT ON COLUMN users.unknown IS 'ignored';
+	`)
+
+	if !result.IsValid {
+		t.Fatalf("expected valid schema, got errors: %v", result.Errors)
+	}
+
+	table := result.Tables[0]
+	if table.Description != "Registered accounts" {
+		t.Errorf("table description = %q", table.Description)
+	}
+	if got := table.Columns[1].Description; got != "Login e-mail, it's unique" {
+		t.Errorf("email description = %q", got)
+	}
+	if got := table.Columns[2].Description; got != "" {
+		t.Errorf("nick description = %q, want empty", got)
+	}
+
+	hints := hintsOfType(result.Hints, HintDescriptions)
+	if len(hints) != 1 || hints[0].Descriptions["email"] != "Login e-mail, it's unique" {
+		t.Errorf("unexpected %s hints: %+v", HintDescriptions, hints)
+	}
+}
@@ -0,0 +1,851 @@
+package sqlschema
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ============================================================================
+// REGEX PATTERNS
+// ============================================================================
+
+var (
+	tableNameRegex  = regexp.MustCompile(`(?i)CREATE\s+TABLE\s+(?:IF\s+NOT\s+EXISTS\s+)?(?:("(?:[^"]|"")+"|[\w$]+)\.)?("(?:[^"]|"")+"|[\w$]+)\s*\(`)
+	identifierRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_$]*$`)
+
+	inlineReferencesRegex = regexp.MustCompile(`(?i)\bREFERENCES\s+((?:"[^"]+"|\w+)(?:\.(?:"[^"]+"|\w+))?)\s*(?:\(([^)]*)\))?`)
+	foreignKeyRegex       = regexp.MustCompile(`(?i)FOREIGN\s+KEY\s*\(([^)]+)\)\s*REFERENCES\s+((?:"[^"]+"|\w+)(?:\.(?:"[^"]+"|\w+))?)\s*(?:\(([^)]*)\))?`)
+	primaryKeyRegex       = regexp.MustCompile(`(?i)PRIMARY\s+KEY\s*\(([^)]+)\)`)
+
+	createEnumRegex   = regexp.MustCompile(`(?is)^CREATE\s+TYPE\s+(` + qualifiedIdentPattern + `)\s+AS\s+ENUM\s*(\(.*?)\s*;?\s*$`)
+	createDomainRegex = regexp.MustCompile(`(?is)^CREATE\s+DOMAIN\s+(` + qualifiedIdentPattern + `)\s+(?:AS\s+)?(.*?)\s*;?\s*$`)
+	quotedValueRegex  = regexp.MustCompile(`'((?:[^']|'')*)'`)
+
+	searchPathRegex = regexp.MustCompile(`(?i)\bSET\s+(?:SESSION\s+|LOCAL\s+)?search_path\s*(?:TO|=)\s*([^;]+);`)
+
+	identityRegex        = regexp.MustCompile(`(?i)\bGENERATED\s+(?:ALWAYS|BY\s+DEFAULT)\s+AS\s+IDENTITY\b`)
+	storedGeneratedRegex = regexp.MustCompile(`(?is)\bGENERATED\s+ALWAYS\s+AS\s*\((.*)\)\s*STORED\b`)
+	nextvalDefaultRegex  = regexp.MustCompile(`(?i)\bDEFAULT\s+nextval\s*\(\s*'([^']+)'`)
+)
+
+// Tipos de datos válidos de PostgreSQL
+var postgresDataTypes = map[string]bool{
+	// Numéricos
+	"smallint": true, "int2": true, "integer": true, "int": true, "int4": true,
+	"bigint": true, "int8": true, "decimal": true, "numeric": true,
+	"real": true, "float4": true, "double precision": true, "float8": true,
+	"smallserial": true, "serial2": true, "serial": true, "serial4": true,
+	"bigserial": true, "serial8": true, "money": true, "float": true,
+	// Caracteres
+	"character varying": true, "varchar": true, "character": true, "char": true,
+	"text": true, "citext": true,
+	// Binarios
+	"bytea": true,
+	// Fecha/Hora
+	"timestamp": true, "timestamp without time zone": true,
+	"timestamp with time zone": true, "timestamptz": true,
+	"date": true, "time": true, "time without time zone": true,
+	"time with time zone": true, "timetz": true, "interval": true,
+	// Booleano
+	"boolean": true, "bool": true,
+	// JSON
+	"json": true, "jsonb": true,
+	// UUID
+	"uuid": true,
+	// Red
+	"cidr": true, "inet": true, "macaddr": true, "macaddr8": true,
+	// Geométricos
+	"point": true, "line": true, "lseg": true, "box": true,
+	"path": true, "polygon": true, "circle": true,
+	// Text search
+	"tsvector": true, "tsquery": true,
+	// XML
+	"xml": true,
+	// Bit
+	"bit": true, "bit varying": true, "varbit": true,
+	// Rangos
+	"int4range": true, "int8range": true, "numrange": true,
+	"tsrange": true, "tstzrange": true, "daterange": true,
+}
+
+// ============================================================================
+// VALIDACIÓN PRINCIPAL
+// ============================================================================
+
+func isValidSchema(schema string) bool {
+	// 1. Debe contener al menos un CREATE TABLE
+	if !containsCreateTableStatement(schema) {
+		return false
+	}
+
+	// 2. Tipos personalizados (ENUM / DOMAIN) declarados en el script
+	types, invalid := extractCustomTypes(maskRoutines(schema, splitStatements(schema)))
+	if len(invalid) > 0 {
+		return false
+	}
+	customTypes := indexCustomTypes(types)
+
+	// 3. Extraer y validar cada sentencia
+	statements := extractCreateTableStatements(schema)
+	for _, stmt := range statements {
+		if !isValidCreateTableStatement(stmt, customTypes) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func containsCreateTableStatement(schema string) bool {
+	return len(extractCreateTableStatements(schema)) > 0
+}
+
+// extractCreateTableStatements retorna los CREATE TABLE terminados en ';'.
+// Vistas, funciones, triggers y COMMENT ON se ignoran.
+func extractCreateTableStatements(schema string) []string {
+	var statements []string
+	for _, stmt := range splitStatements(schema) {
+		if stmt.kind == StmtCreateTable && stmt.terminated {
+			statements = append(statements, stmt.text)
+		}
+	}
+	return statements
+}
+
+// ============================================================================
+// VALIDACIÓN DE SENTENCIA CREATE TABLE
+// ============================================================================
+
+// hasTrailingGarbage detecta texto despues del cierre del cuerpo que no sea
+// INHERITS (...) o PARTITION BY {RANGE | LIST | HASH} (...).
+func hasTrailingGarbage(stmt string) bool {
+	start := strings.Index(stmt, "(")
+	if start == -1 || tableBodyEnd(stmt, start) == -1 {
+		return true
+	}
+	_, _, _, ok := extractTableOptions(stmt)
+	return !ok
+}
+
+func isValidCreateTableStatement(stmt string, customTypes map[string]CustomType) bool {
+	// Las particiones hijas no tienen cuerpo propio: heredan las columnas
+	if isPartitionOfStatement(stmt) {
+		_, _, _, ok := parsePartitionOf(stmt)
+		return ok
+	}
+
+	// 0. Verificar que no haya caracteres invalidos despues del cierre )
+	if hasTrailingGarbage(stmt) {
+		return false
+	}
+
+	// 1. Validar nombre de tabla
+	rawName := extractRawTableName(stmt)
+	if rawName == "" || !isValidIdentifier(rawName) {
+		return false
+	}
+
+	// 2. Extraer y validar el cuerpo (columnas y constraints). Con INHERITS
+	// el cuerpo puede estar vacio: las columnas vienen del padre.
+	body := extractTableBody(stmt)
+	if body == "" {
+		inherits, _, _, _ := extractTableOptions(stmt)
+		return len(inherits) > 0
+	}
+
+	// 3. Parsear elementos (columnas y constraints)
+	elements := splitTableElements(body)
+	if len(elements) == 0 {
+		return false
+	}
+
+	// 4. Validar cada elemento
+	columnCount := 0
+	columnNames := make(map[string]bool)
+
+	for _, elem := range elements {
+		elem = strings.TrimSpace(elem)
+		if elem == "" {
+			continue
+		}
+
+		if isTableLevelConstraint(elem) {
+			// Validar constraint de tabla
+			if !isValidTableConstraint(elem) {
+				return false
+			}
+		} else {
+			// Validar columna
+			colName, valid := isValidColumnDefinition(elem, customTypes)
+			if !valid {
+				return false
+			}
+
+			// Verificar duplicados (colName ya viene con case folding)
+			if columnNames[colName] {
+				return false // Columna duplicada
+			}
+			columnNames[colName] = true
+			columnCount++
+		}
+	}
+
+	// Debe tener al menos una columna
+	return columnCount > 0
+}
+
+// ============================================================================
+// EXTRACCIÓN
+// ============================================================================
+
+func extractTableName(stmt string) string {
+	return foldIdentifier(extractRawTableName(stmt))
+}
+
+// extractRawTableName retorna el nombre de tabla tal como fue escrito
+// (con comillas si las tiene), para validarlo con las reglas de identificadores.
+func extractRawTableName(stmt string) string {
+	matches := tableNameRegex.FindStringSubmatch(stmt)
+	if len(matches) >= 3 {
+		return matches[2] // Nombre de tabla (sin schema)
+	}
+	return ""
+}
+
+// extractTableSchema retorna el schema explicito de la tabla (billing.invoices -> billing)
+func extractTableSchema(stmt string) string {
+	matches := tableNameRegex.FindStringSubmatch(stmt)
+	if len(matches) >= 3 {
+		return foldIdentifier(matches[1])
+	}
+	return ""
+}
+
+// searchPathChange registra un SET search_path y su posicion en el script
+type searchPathChange struct {
+	offset int
+	schema string
+}
+
+// extractSearchPathChanges obtiene los SET search_path del script en orden.
+// Solo importa el primer schema de la lista ("$user" se ignora): es donde
+// PostgreSQL crea las tablas no calificadas.
+func extractSearchPathChanges(schema string) []searchPathChange {
+	var changes []searchPathChange
+	for _, loc := range searchPathRegex.FindAllStringSubmatchIndex(schema, -1) {
+		first := ""
+		for _, part := range strings.Split(schema[loc[2]:loc[3]], ",") {
+			name := foldIdentifier(strings.Trim(strings.TrimSpace(part), "'"))
+			if name != "" && name != "$user" {
+				first = name
+				break
+			}
+		}
+		changes = append(changes, searchPathChange{offset: loc[0], schema: first})
+	}
+	return changes
+}
+
+// schemaAt retorna el schema activo por search_path en una posicion del script
+func schemaAt(changes []searchPathChange, offset int) string {
+	current := ""
+	for _, c := range changes {
+		if c.offset > offset {
+			break
+		}
+		current = c.schema
+	}
+	return current
+}
+
+// tableKey identifica una tabla a nivel de script. schema y name ya vienen
+// con case folding, asi que "Users" y users son tablas distintas.
+func tableKey(schema, name string) string {
+	if schema == "" {
+		schema = defaultSchema
+	}
+	return schema + "." + name
+}
+
+func extractTableBody(stmt string) string {
+	// Encontrar primer ( y su ) de cierre (puede seguir PARTITION BY (...))
+	start := strings.Index(stmt, "(")
+	if start == -1 {
+		return ""
+	}
+	end := tableBodyEnd(stmt, start)
+	if end == -1 {
+		return ""
+	}
+
+	return strings.TrimSpace(stmt[start+1 : end])
+}
+
+// extractForeignKeys obtiene la FK declarada en un elemento de tabla, ya sea
+// inline en la columna (col INT REFERENCES t(id)) o como constraint de tabla
+// (FOREIGN KEY (col) REFERENCES t(id)).
+func extractForeignKeys(elem string, colName string) []ForeignKeyInfo {
+	if m := foreignKeyRegex.FindStringSubmatch(elem); m != nil {
+		return []ForeignKeyInfo{{
+			Columns:    splitIdentifierList(m[1]),
+			RefSchema:  tableReferenceSchema(m[2]),
+			RefTable:   cleanTableReference(m[2]),
+			RefColumns: splitIdentifierList(m[3]),
+		}}
+	}
+
+	if colName == "" {
+		return nil
+	}
+
+	if m := inlineReferencesRegex.FindStringSubmatch(elem); m != nil {
+		return []ForeignKeyInfo{{
+			Columns:    []string{colName},
+			RefSchema:  tableReferenceSchema(m[1]),
+			RefTable:   cleanTableReference(m[1]),
+			RefColumns: splitIdentifierList(m[2]),
+		}}
+	}
+
+	return nil
+}
+
+// extractPrimaryKeyColumns obtiene las columnas de un PRIMARY KEY (a, b) a nivel de tabla
+func extractPrimaryKeyColumns(elem string) []string {
+	if m := primaryKeyRegex.FindStringSubmatch(elem); m != nil {
+		return splitIdentifierList(m[1])
+	}
+	return nil
+}
+
+// cleanTableReference quita comillas y schema de una referencia (public."Users" -> Users)
+func cleanTableReference(ref string) string {
+	parts := splitQualifiedName(ref)
+	return foldIdentifier(parts[len(parts)-1])
+}
+
+// tableReferenceSchema retorna el schema de una referencia calificada (billing.invoices -> billing)
+func tableReferenceSchema(ref string) string {
+	parts := splitQualifiedName(ref)
+	if len(parts) < 2 {
+		return ""
+	}
+	return foldIdentifier(parts[len(parts)-2])
+}
+
+// splitQualifiedName separa schema.tabla respetando puntos dentro de comillas
+func splitQualifiedName(ref string) []string {
+	var parts []string
+	var current strings.Builder
+	inQuotes := false
+
+	for i := 0; i < len(ref); i++ {
+		ch := ref[i]
+		switch {
+		case ch == '"':
+			inQuotes = !inQuotes
+			current.WriteByte(ch)
+		case ch == '.' && !inQuotes:
+			parts = append(parts, current.String())
+			current.Reset()
+		default:
+			current.WriteByte(ch)
+		}
+	}
+
+	return append(parts, current.String())
+}
+
+func splitIdentifierList(list string) []string {
+	var names []string
+	for _, part := range strings.Split(list, ",") {
+		name := foldIdentifier(strings.TrimSpace(part))
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func splitTableElements(body string) []string {
+	var elements []string
+	var current strings.Builder
+	depth := 0
+	inQuotes := false
+	inIdentifier := false
+
+	for i := 0; i < len(body); i++ {
+		ch := body[i]
+
+		switch ch {
+		case '\'':
+			if !inIdentifier && (i == 0 || body[i-1] != '\\') {
+				inQuotes = !inQuotes
+			}
+			current.WriteByte(ch)
+		case '"':
+			// Identificador entre comillas dobles ("Order Items")
+			if !inQuotes {
+				inIdentifier = !inIdentifier
+			}
+			current.WriteByte(ch)
+		case '(':
+			if !inQuotes && !inIdentifier {
+				depth++
+			}
+			current.WriteByte(ch)
+		case ')':
+			if !inQuotes && !inIdentifier {
+				depth--
+			}
+			current.WriteByte(ch)
+		case ',':
+			if !inQuotes && !inIdentifier && depth == 0 {
+				elements = append(elements, current.String())
+				current.Reset()
+			} else {
+				current.WriteByte(ch)
+			}
+		default:
+			current.WriteByte(ch)
+		}
+	}
+
+	if current.Len() > 0 {
+		elements = append(elements, current.String())
+	}
+
+	return elements
+}
+
+// ============================================================================
+// VALIDACIÓN DE IDENTIFICADORES
+// ============================================================================
+
+// Limite de PostgreSQL para identificadores (NAMEDATALEN - 1), en bytes
+const maxIdentifierBytes = 63
+
+// isValidIdentifier valida un identificador tal como fue escrito. Sin comillas
+// debe cumplir [a-zA-Z_][a-zA-Z0-9_$]*; entre comillas acepta cualquier
+// caracter excepto NUL, con "" como comilla escapada. En ambos casos el
+// nombre resultante no puede superar 63 bytes.
+func isValidIdentifier(name string) bool {
+	if isQuotedIdentifier(name) {
+		inner := name[1 : len(name)-1]
+		if strings.Contains(strings.ReplaceAll(inner, `""`, ""), `"`) {
+			return false
+		}
+		folded := foldIdentifier(name)
+		return folded != "" && len(folded) <= maxIdentifierBytes && !strings.ContainsRune(folded, 0)
+	}
+
+	if name == "" || len(name) > maxIdentifierBytes {
+		return false
+	}
+	return identifierRegex.MatchString(name)
+}
+
+func isQuotedIdentifier(name string) bool {
+	return len(name) >= 2 && name[0] == '"' && name[len(name)-1] == '"'
+}
+
+// foldIdentifier aplica el case folding de PostgreSQL: sin comillas se pasa a
+// minusculas, entre comillas se conserva tal cual (quitando el escape "").
+func foldIdentifier(name string) string {
+	if isQuotedIdentifier(name) {
+		return strings.ReplaceAll(name[1:len(name)-1], `""`, `"`)
+	}
+	return asciiLower(name)
+}
+
+// ============================================================================
+// VALIDACIÓN DE COLUMNAS
+// ============================================================================
+
+// columnConstraintKeywords son palabras clave validas despues del tipo de dato.
+// KEY no es valido solo; debe ir como "PRIMARY KEY".
+var columnConstraintKeywords = map[string]bool{
+	"NOT": true, "NULL": true, "DEFAULT": true,
+	"PRIMARY": true, "UNIQUE": true,
+	"CHECK": true, "REFERENCES": true, "CONSTRAINT": true,
+	"COLLATE": true, "GENERATED": true, "ALWAYS": true,
+	"AS": true, "STORED": true, "IDENTITY": true,
+	"ON": true, "UPDATE": true, "DELETE": true,
+	"CASCADE": true, "RESTRICT": true, "NO": true,
+	"ACTION": true, "SET": true, "DEFERRABLE": true,
+	"INITIALLY": true, "DEFERRED": true, "IMMEDIATE": true,
+	"NOW()": true, "TRUE": true, "FALSE": true, "BY": true,
+}
+
+// Tokens que requieren otro token adyacente para ser validos
+var mustBeFollowedBy = map[string][]string{
+	"PRIMARY": {"KEY"},
+	"NOT":     {"NULL", "DEFERRABLE"},
+}
+
+var mustBePrecededBy = map[string]string{
+	"KEY": "PRIMARY", // KEY solo valido despues de PRIMARY
+}
+
+func isValidColumnDefinition(elem string, customTypes map[string]CustomType) (string, bool) {
+	tokens := tokenize(elem)
+	if len(tokens) < 2 {
+		return "", false
+	}
+
+	// Primer token: nombre de columna
+	if !isValidIdentifier(tokens[0]) {
+		return "", false
+	}
+	colName := foldIdentifier(tokens[0])
+
+	// Segundo token: tipo de dato
+	dataType := extractDataType(tokens[1:])
+	if !isKnownDataType(dataType, customTypes) {
+		return "", false
+	}
+
+	// Determinar cuantos tokens consume el tipo de dato
+	constraintStart := 2
+	normalizedDT := strings.ToLower(dataType)
+	if strings.Contains(normalizedDT, " ") {
+		constraintStart = 3 // tipo de dos palabras (e.g. "double precision")
+	}
+
+	// Validar tokens restantes como constraint de columna
+	for i := constraintStart; i < len(tokens); i++ {
+		tok := tokens[i]
+		upper := strings.ToUpper(tok)
+
+		// Ignorar valores entre parentesis (CHECK(...), REFERENCES table(col))
+		if strings.Contains(tok, "(") {
+			continue
+		}
+
+		// Ignorar literales numericos y strings (DEFAULT 0, DEFAULT 'value')
+		if len(tok) > 0 && (tok[0] == '\'' || (tok[0] >= '0' && tok[0] <= '9') || tok[0] == '-') {
+			continue
+		}
+
+		// Verificar tokens que requieren predecesor (e.g. KEY solo valido despues de PRIMARY)
+		if requiredPrev, isDep := mustBePrecededBy[upper]; isDep {
+			if i == constraintStart || strings.ToUpper(tokens[i-1]) != requiredPrev {
+				return "", false
+			}
+			continue
+		}
+
+		// Verificar que sea una keyword de constraint conocida
+		if columnConstraintKeywords[upper] {
+			// Verificar tokens que requieren sucesor (e.g. PRIMARY debe ir seguido de KEY)
+			if allowedNext, needs := mustBeFollowedBy[upper]; needs {
+				if i+1 >= len(tokens) {
+					return "", false
+				}
+				next := strings.ToUpper(tokens[i+1])
+				valid := false
+				for _, a := range allowedNext {
+					if next == a {
+						valid = true
+						break
+					}
+				}
+				if !valid {
+					return "", false
+				}
+			}
+			continue
+		}
+
+		// Token desconocido: podria ser un nombre de tabla despues de REFERENCES,
+		// o un valor DEFAULT. Verificar contexto.
+		if i > constraintStart {
+			prev := strings.ToUpper(tokens[i-1])
+			if prev == "REFERENCES" || prev == "DEFAULT" || prev == "COLLATE" {
+				continue
+			}
+		}
+
+		// Si llegamos aqui, es un token no reconocido -> invalido
+		return "", false
+	}
+
+	return colName, true
+}
+
+// Tipos serial: autoincrementales respaldados por una secuencia implicita
+var serialDataTypes = map[string]bool{
+	"smallserial": true, "serial2": true, "serial": true, "serial4": true,
+	"bigserial": true, "serial8": true,
+}
+
+// extractColumnGeneration detecta si el valor de la columna lo genera la base
+// de datos: serial, identity, DEFAULT nextval('seq') o columna STORED.
+// Retorna el tipo de generacion, la expresion (STORED) y la secuencia (nextval).
+func extractColumnGeneration(elem, dataType string) (string, string, string) {
+	if m := storedGeneratedRegex.FindStringSubmatch(elem); m != nil {
+		return GenerationStored, strings.TrimSpace(m[1]), ""
+	}
+	if identityRegex.MatchString(elem) {
+		return GenerationIdentity, "", ""
+	}
+	if m := nextvalDefaultRegex.FindStringSubmatch(elem); m != nil {
+		return GenerationSequence, "", m[1]
+	}
+	if serialDataTypes[normalizeDataType(dataType)] {
+		return GenerationSerial, "", ""
+	}
+	return "", "", ""
+}
+
+func tokenize(s string) []string {
+	var tokens []string
+	var current strings.Builder
+	inParens := 0
+	inIdentifier := false
+
+	s = strings.TrimSpace(s)
+
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+
+		switch ch {
+		case '"':
+			inIdentifier = !inIdentifier
+			current.WriteByte(ch)
+		case '(':
+			if !inIdentifier {
+				inParens++
+			}
+			current.WriteByte(ch)
+		case ')':
+			if !inIdentifier {
+				inParens--
+			}
+			current.WriteByte(ch)
+		case ' ', '\t', '\n', '\r':
+			if inParens > 0 || inIdentifier {
+				current.WriteByte(ch)
+			} else if current.Len() > 0 {
+				tokens = append(tokens, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteByte(ch)
+		}
+	}
+
+	if current.Len() > 0 {
+		tokens = append(tokens, current.String())
+	}
+
+	return tokens
+}
+
+func extractDataType(tokens []string) string {
+	if len(tokens) == 0 {
+		return ""
+	}
+
+	// Tipos de dos palabras
+	if len(tokens) >= 2 {
+		twoWord := strings.ToLower(tokens[0] + " " + tokens[1])
+		twoWordTypes := []string{
+			"double precision", "character varying", "bit varying",
+			"timestamp without", "timestamp with", "time without", "time with",
+		}
+		for _, t := range twoWordTypes {
+			if strings.HasPrefix(twoWord, t) {
+				return twoWord
+			}
+		}
+	}
+
+	return tokens[0]
+}
+
+func isValidDataType(dataType string) bool {
+	if dataType == "" {
+		return false
+	}
+	return postgresDataTypes[normalizeDataType(dataType)]
+}
+
+// isKnownDataType acepta tipos nativos de PostgreSQL y los tipos personalizados
+// (ENUM / DOMAIN) declarados en el mismo script.
+func isKnownDataType(dataType string, customTypes map[string]CustomType) bool {
+	if isValidDataType(dataType) {
+		return true
+	}
+	_, ok := lookupCustomType(dataType, customTypes)
+	return ok
+}
+
+func normalizeDataType(dataType string) string {
+	normalized := strings.ToLower(dataType)
+
+	// Quitar arrays []
+	normalized = strings.TrimSuffix(normalized, "[]")
+
+	// Quitar precisión (10,2)
+	if idx := strings.Index(normalized, "("); idx != -1 {
+		normalized = strings.TrimSpace(normalized[:idx])
+	}
+
+	return normalized
+}
+
+// ============================================================================
+// TIPOS PERSONALIZADOS (CREATE TYPE ... AS ENUM / CREATE DOMAIN)
+// ============================================================================
+
+// extractCustomTypes parsea los ENUM y DOMAIN declarados en el script, en
+// orden de aparicion por tipo. Retorna los tipos validos y los dominios cuyo
+// tipo base no es reconocido. Las sentencias salen de splitStatements, asi un
+// ';' o ')' dentro de una etiqueta o un nombre con comillas no las corta.
+func extractCustomTypes(schema string) ([]CustomType, []CustomType) {
+	var valid, invalid []CustomType
+	var domains []sqlStatement
+	known := make(map[string]CustomType)
+
+	for _, stmt := range splitStatements(schema) {
+		if createDomainRegex.MatchString(stmt.text) {
+			domains = append(domains, stmt)
+			continue
+		}
+		ct, ok := parseEnumType(stmt.text)
+		if !ok {
+			continue
+		}
+		known[strings.ToLower(ct.Name)] = ct
+		valid = append(valid, ct)
+	}
+
+	// Los dominios pueden basarse en un ENUM o en otro dominio declarado antes
+	for _, stmt := range domains {
+		m := createDomainRegex.FindStringSubmatch(stmt.text)
+		ct := CustomType{
+			Name:     customTypeName(m[1]),
+			Kind:     CustomTypeDomain,
+			BaseType: extractDataType(tokenize(m[2])),
+			Raw:      strings.TrimSpace(stmt.text),
+		}
+
+		base, isCustom := lookupCustomType(ct.BaseType, known)
+		if !isValidDataType(ct.BaseType) && !isCustom {
+			invalid = append(invalid, ct)
+			continue
+		}
+		if isCustom {
+			ct.Values = base.Values
+		}
+		known[strings.ToLower(ct.Name)] = ct
+		valid = append(valid, ct)
+	}
+
+	return valid, invalid
+}
+
+// parseEnumType parsea un CREATE TYPE ... AS ENUM. tokenizeCheck agrupa la
+// lista de etiquetas respetando strings, y nada puede seguir a la lista.
+func parseEnumType(stmt string) (CustomType, bool) {
+	m := createEnumRegex.FindStringSubmatch(stmt)
+	if m == nil {
+		return CustomType{}, false
+	}
+	tokens := tokenizeCheck(m[2])
+	if len(tokens) != 1 || !strings.HasSuffix(tokens[0], ")") {
+		return CustomType{}, false
+	}
+
+	ct := CustomType{
+		Name:     customTypeName(m[1]),
+		Kind:     CustomTypeEnum,
+		BaseType: "text",
+		Raw:      strings.TrimSpace(stmt),
+	}
+	labels := tokens[0][1 : len(tokens[0])-1]
+	for _, v := range quotedValueRegex.FindAllStringSubmatch(labels, -1) {
+		ct.Values = append(ct.Values, strings.ReplaceAll(v[1], "''", "'"))
+	}
+	return ct, true
+}
+
+// customTypeName quita el schema y las comillas del nombre de un tipo,
+// conservando como esta escrito un nombre sin comillas
+func customTypeName(ref string) string {
+	parts := splitQualifiedName(ref)
+	name := parts[len(parts)-1]
+	if isQuotedIdentifier(name) {
+		return foldIdentifier(name)
+	}
+	return name
+}
+
+// indexCustomTypes indexa los tipos personalizados por nombre en minusculas
+func indexCustomTypes(types []CustomType) map[string]CustomType {
+	index := make(map[string]CustomType, len(types))
+	for _, ct := range types {
+		index[strings.ToLower(ct.Name)] = ct
+	}
+	return index
+}
+
+// lookupCustomType busca un tipo personalizado ignorando schema, comillas y arrays
+func lookupCustomType(dataType string, customTypes map[string]CustomType) (CustomType, bool) {
+	if len(customTypes) == 0 {
+		return CustomType{}, false
+	}
+	ct, ok := customTypes[cleanTableReference(normalizeDataType(dataType))]
+	return ct, ok
+}
+
+// ============================================================================
+// VALIDACIÓN DE CONSTRAINTS
+// ============================================================================
+
+func isTableLevelConstraint(elem string) bool {
+	upper := strings.ToUpper(strings.TrimSpace(elem))
+	return strings.HasPrefix(upper, "CONSTRAINT ") ||
+		strings.HasPrefix(upper, "PRIMARY KEY") ||
+		strings.HasPrefix(upper, "FOREIGN KEY") ||
+		strings.HasPrefix(upper, "UNIQUE") ||
+		strings.HasPrefix(upper, "CHECK") ||
+		strings.HasPrefix(upper, "EXCLUDE")
+}
+
+func isValidTableConstraint(elem string) bool {
+	upper := strings.ToUpper(strings.TrimSpace(elem))
+
+	// Quitar "CONSTRAINT nombre" si existe
+	if strings.HasPrefix(upper, "CONSTRAINT ") {
+		parts := tokenize(elem)
+		if len(parts) < 3 {
+			return false
+		}
+		// Validar nombre del constraint
+		if !isValidIdentifier(parts[1]) {
+			return false
+		}
+		// Continuar con el resto
+		upper = strings.ToUpper(strings.Join(parts[2:], " "))
+	}
+
+	// Verificar tipo de constraint válido
+	validPrefixes := []string{
+		"PRIMARY KEY", "FOREIGN KEY", "UNIQUE", "CHECK", "EXCLUDE",
+	}
+
+	for _, prefix := range validPrefixes {
+		if strings.HasPrefix(upper, prefix) {
+			// CHECK requiere una expresion entre parentesis: CHECK (price > 0)
+			if prefix == "CHECK" {
+				exprs := extractCheckExpressions(upper)
+				return len(exprs) == 1 && exprs[0] != ""
+			}
+			return true
+		}
+	}
+
+	return false
+}
@@ -1,4 +1,4 @@
-package main
+package sqlschema
 
 import (
 	"fmt"
@@ -213,6 +213,100 @@ func TestExtractTableName(t *testing.T) {
 	}
 }
 
+func TestExtractTableSchema(t *testing.T) {
+	tests := []struct {
+		stmt string
+		want string
+	}{
+		{`CREATE TABLE users (id INT);`, ""},
+		{`CREATE TABLE billing.invoices (id INT);`, "billing"},
+		{`CREATE TABLE IF NOT EXISTS "sales".invoices (id INT);`, "sales"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.stmt, func(t *testing.T) {
+			got := extractTableSchema(tt.stmt)
+			if got != tt.want {
+				t.Errorf("extractTableSchema() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSchemaAt(t *testing.T) {
+	script := `CREATE TABLE a (id INT);
+SET search_path TO "$user", billing, public;
+CREATE TABLE b (id INT);
+SET search_path = 'sales';
+CREATE TABLE c (id INT);`
+
+	changes := extractSearchPathChanges(script)
+	if len(changes) != 2 {
+		t.Fatalf("expected 2 search_path changes, got %d", len(changes))
+	}
+
+	tests := []struct {
+		table string
+		want  string
+	}{
+		{"CREATE TABLE a", ""},
+		{"CREATE TABLE b", "billing"},
+		{"CREATE TABLE c", "sales"},
+	}
+	for _, tt := range tests {
+		if got := schemaAt(changes, strings.Index(script, tt.table)); got != tt.want {
+			t.Errorf("schemaAt(%s) = %q, want %q", tt.table, got, tt.want)
+		}
+	}
+}
+
+func TestValidateSQL_DuplicateTables(t *testing.T) {
+	tests := []struct {
+		name    string
+		sql     string
+		wantDup bool
+	}{
+		{
+			name:    "Mismo nombre en distinto schema",
+			sql:     `CREATE TABLE billing.invoices (id INT PRIMARY KEY); CREATE TABLE sales.invoices (id INT PRIMARY KEY);`,
+			wantDup: false,
+		},
+		{
+			name:    "Duplicada sin schema",
+			sql:     `CREATE TABLE users (id INT PRIMARY KEY); CREATE TABLE Users (id INT PRIMARY KEY);`,
+			wantDup: true,
+		},
+		{
+			name:    "public implicito",
+			sql:     `CREATE TABLE users (id INT PRIMARY KEY); CREATE TABLE public.users (id INT PRIMARY KEY);`,
+			wantDup: true,
+		},
+		{
+			name:    "Duplicada via search_path",
+			sql:     `SET search_path TO billing; CREATE TABLE invoices (id INT PRIMARY KEY); CREATE TABLE billing.invoices (id INT PRIMARY KEY);`,
+			wantDup: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ValidateSQL(tt.sql)
+			gotDup := false
+			for _, e := range result.Errors {
+				if e.Code == ErrDuplicateTable {
+					gotDup = true
+				}
+			}
+			if gotDup != tt.wantDup {
+				t.Errorf("duplicate reported = %v, want %v (errors: %+v)", gotDup, tt.wantDup, result.Errors)
+			}
+			if result.IsValid == tt.wantDup {
+				t.Errorf("IsValid = %v, want %v", result.IsValid, !tt.wantDup)
+			}
+		})
+	}
+}
+
 func TestExtractTableBody(t *testing.T) {
 	tests := []struct {
 		name string
@@ -246,6 +340,40 @@ func TestExtractTableBody(t *testing.T) {
 	}
 }
 
+func TestExtractForeignKeys(t *testing.T) {
+	tests := []struct {
+		name     string
+		elem     string
+		colName  string
+		wantCols []string
+		wantRef  string
+		wantRefs []string
+	}{
+		{"Inline", "user_id INT REFERENCES users(id)", "user_id", []string{"user_id"}, "users", []string{"id"}},
+		{"Inline sin columna", "user_id INT REFERENCES users", "user_id", []string{"user_id"}, "users", nil},
+		{"Inline con schema", `owner_id INT REFERENCES public."users"(id)`, "owner_id", []string{"owner_id"}, "users", []string{"id"}},
+		{"Tabla", "FOREIGN KEY (parent_id) REFERENCES categories(id)", "", []string{"parent_id"}, "categories", []string{"id"}},
+		{"Tabla compuesta", "CONSTRAINT fk FOREIGN KEY (a, b) REFERENCES other (x, y)", "", []string{"a", "b"}, "other", []string{"x", "y"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fks := extractForeignKeys(tt.elem, tt.colName)
+			if len(fks) != 1 {
+				t.Fatalf("extractForeignKeys() returned %d FKs, want 1", len(fks))
+			}
+			fk := fks[0]
+			if fmt.Sprint(fk.Columns) != fmt.Sprint(tt.wantCols) || fk.RefTable != tt.wantRef || fmt.Sprint(fk.RefColumns) != fmt.Sprint(tt.wantRefs) {
+				t.Errorf("extractForeignKeys() = %+v, want cols=%v ref=%s refCols=%v", fk, tt.wantCols, tt.wantRef, tt.wantRefs)
+			}
+		})
+	}
+
+	if fks := extractForeignKeys("name TEXT NOT NULL", "name"); len(fks) != 0 {
+		t.Errorf("expected no FKs for plain column, got %+v", fks)
+	}
+}
+
 func TestSplitTableElements(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -284,6 +412,13 @@ func TestIsValidIdentifier(t *testing.T) {
 		{"", false},
 		{strings.Repeat("a", 64), false}, // > 63 chars
 		{strings.Repeat("a", 63), true},  // exactamente 63
+		{`"Order Items"`, true},
+		{`"user"`, true},
+		{`"say ""hi"""`, true},
+		{`"bad"quote"`, false},
+		{`""`, false},
+		{`"` + strings.Repeat("ñ", 31) + `"`, true},  // 62 bytes
+		{`"` + strings.Repeat("ñ", 32) + `"`, false}, // 64 bytes, 32 caracteres
 	}
 
 	for _, tt := range tests {
@@ -296,6 +431,112 @@ func TestIsValidIdentifier(t *testing.T) {
 	}
 }
 
+func TestFoldIdentifier(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{"Users", "users"},
+		{`"Users"`, "Users"},
+		{`"Order Items"`, "Order Items"},
+		{`"say ""hi"""`, `say "hi"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			if got := foldIdentifier(tt.raw); got != tt.want {
+				t.Errorf("foldIdentifier(%s) = %q, want %q", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateSQL_QuotedIdentifiers(t *testing.T) {
+	result := ValidateSQL(`CREATE TABLE "Order Items" (
+		"Item ID" SERIAL PRIMARY KEY,
+		"user" INT,
+		"a,b" TEXT,
+		id INT,
+		"ID" INT
+	);`)
+	if !result.IsValid {
+		t.Fatalf("expected valid SQL, got errors: %+v", result.Errors)
+	}
+
+	table := result.Tables[0]
+	if table.Name != "Order Items" {
+		t.Errorf("table name = %q, want %q", table.Name, "Order Items")
+	}
+	var cols []string
+	for _, c := range table.Columns {
+		cols = append(cols, c.Name)
+	}
+	if got := strings.Join(cols, "|"); got != "Item ID|user|a,b|id|ID" {
+		t.Errorf("columns = %s", got)
+	}
+}
+
+func TestValidateSQL_CaseFoldingDuplicates(t *testing.T) {
+	result := ValidateSQL(`CREATE TABLE t (Name TEXT, NAME TEXT);`)
+	if result.IsValid || result.Errors[0].Code != ErrDuplicateColumn {
+		t.Errorf("unquoted Name/NAME should be duplicates, got %+v", result.Errors)
+	}
+
+	result = ValidateSQL(`CREATE TABLE Users (id INT PRIMARY KEY); CREATE TABLE "Users" (id INT PRIMARY KEY);`)
+	if !result.IsValid {
+		t.Errorf("Users and \"Users\" are different tables, got %+v", result.Errors)
+	}
+}
+
+func TestValidateSQL_ReservedWords(t *testing.T) {
+	result := ValidateSQL(`CREATE TABLE user (
+		id SERIAL PRIMARY KEY,
+		order INT,
+		"select" TEXT,
+		status TEXT,
+		"ſtatus" TEXT
+	);`)
+	if !result.IsValid {
+		t.Fatalf("reserved words must only warn, got errors: %+v", result.Errors)
+	}
+
+	var pg, ddb []string
+	for _, w := range result.Warnings {
+		switch w.Code {
+		case WarnReservedWord:
+			pg = append(pg, w.Table+"."+w.Column)
+		case WarnDynamoDBReservedWord:
+			ddb = append(ddb, w.Column)
+		}
+	}
+	if got := strings.Join(pg, ","); got != "user.,user.order" {
+		t.Errorf("PostgreSQL reserved warnings = %s", got)
+	}
+	if got := strings.Join(ddb, ","); got != "order,select,status" {
+		t.Errorf("DynamoDB reserved warnings = %s", got)
+	}
+}
+
+// Los atributos que agregan los hints tambien se revisan, no solo las columnas
+func TestDetectReservedAttributes_GeneratedAttributes(t *testing.T) {
+	tables := []TableInfo{{Name: "readings", Columns: []ColumnInfo{{Name: "id"}, {Name: "ttl"}}}}
+	hints := []DesignHint{
+		{Type: HintTimeSeries, Table: "readings", TTLAttribute: "ttl"},
+		{Type: HintTimeSeries, Table: "readings", TTLAttribute: "Size"},
+	}
+
+	warnings := detectReservedAttributes(tables, hints)
+	if len(warnings) != 2 {
+		t.Fatalf("expected 2 warnings (ttl once, Size), got %+v", warnings)
+	}
+	if warnings[0].Column != "ttl" || !strings.Contains(warnings[0].Message, `"ttl"`) {
+		t.Errorf("column warning = %+v", warnings[0])
+	}
+	if warnings[1].Column != "" || !strings.Contains(warnings[1].Message, `"Size"`) {
+		t.Errorf("generated attribute warning = %+v", warnings[1])
+	}
+}
+
 func TestIsValidDataType(t *testing.T) {
 	validTypes := []string{
 		"INT", "INTEGER", "BIGINT", "SMALLINT",
@@ -331,6 +572,94 @@ func TestIsValidDataType(t *testing.T) {
 	}
 }
 
+func TestExtractCustomTypes(t *testing.T) {
+	schema := `
+		CREATE TYPE order_status AS ENUM ('pending', 'paid', 'it''s shipped');
+		CREATE TYPE public."mood" AS ENUM ('sad','ok','happy');
+		CREATE DOMAIN email AS VARCHAR(255) CHECK (VALUE LIKE '%@%');
+		CREATE DOMAIN status_domain order_status NOT NULL;
+		CREATE DOMAIN broken AS not_a_type;
+	`
+
+	types, invalid := extractCustomTypes(schema)
+	index := indexCustomTypes(types)
+
+	status, ok := index["order_status"]
+	if !ok || status.Kind != CustomTypeEnum {
+		t.Fatalf("expected ENUM order_status, got %+v", status)
+	}
+	if fmt.Sprint(status.Values) != "[pending paid it's shipped]" {
+		t.Errorf("order_status values = %v", status.Values)
+	}
+	if mood := index["mood"]; len(mood.Values) != 3 {
+		t.Errorf("mood values = %v, want 3", mood.Values)
+	}
+
+	email := index["email"]
+	if email.Kind != CustomTypeDomain || email.BaseType != "VARCHAR(255)" {
+		t.Errorf("email = %+v, want DOMAIN over VARCHAR(255)", email)
+	}
+	if sd := index["status_domain"]; len(sd.Values) != 3 {
+		t.Errorf("domain over enum should inherit values, got %v", sd.Values)
+	}
+
+	if len(invalid) != 1 || invalid[0].Name != "broken" {
+		t.Errorf("invalid = %+v, want [broken]", invalid)
+	}
+}
+
+// Nombres con comillas y etiquetas con ')' o ';' no cortan la sentencia
+func TestExtractCustomTypes_QuotedNamesAndLabels(t *testing.T) {
+	schema := `
+		CREATE TYPE "Order Status" AS ENUM ('pending', 'paid (partial)', 'done; archived');
+		CREATE DOMAIN "Short Note" AS TEXT CHECK (VALUE <> ';');
+		CREATE TYPE trailing AS ENUM ('a') extra;
+	`
+
+	types, invalid := extractCustomTypes(schema)
+	index := indexCustomTypes(types)
+
+	status, ok := index["order status"]
+	if !ok || status.Name != "Order Status" {
+		t.Fatalf(`expected ENUM "Order Status", got %+v`, types)
+	}
+	if fmt.Sprint(status.Values) != "[pending paid (partial) done; archived]" {
+		t.Errorf("Order Status values = %q", status.Values)
+	}
+	if note := index["short note"]; note.Kind != CustomTypeDomain || note.BaseType != "TEXT" {
+		t.Errorf(`"Short Note" = %+v, want DOMAIN over TEXT`, note)
+	}
+	if _, ok := index["trailing"]; ok {
+		t.Error("an enum followed by extra tokens should be rejected")
+	}
+	if len(invalid) != 0 {
+		t.Errorf("invalid = %+v, want none", invalid)
+	}
+
+	table := `CREATE TABLE orders (id SERIAL PRIMARY KEY, state "Order Status" NOT NULL);`
+	if !isValidSchema(schema + table) {
+		t.Error(`expected a column of type "Order Status" to be valid`)
+	}
+}
+
+func TestIsValidSchema_CustomTypes(t *testing.T) {
+	schema := `
+		CREATE TYPE status AS ENUM ('active', 'inactive');
+		CREATE DOMAIN positive_int AS INTEGER CHECK (VALUE > 0);
+		CREATE TABLE accounts (
+			id SERIAL PRIMARY KEY,
+			state status NOT NULL DEFAULT 'active',
+			previous public.status[],
+			quota positive_int
+		);`
+	if !isValidSchema(schema) {
+		t.Error("expected schema with custom types to be valid")
+	}
+	if isValidSchema(`CREATE TABLE accounts (id SERIAL PRIMARY KEY, state status);`) {
+		t.Error("expected undeclared custom type to be invalid")
+	}
+}
+
 func TestIsValidColumnDefinition(t *testing.T) {
 	tests := []struct {
 		elem    string
@@ -349,7 +678,7 @@ func TestIsValidColumnDefinition(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.elem, func(t *testing.T) {
-			col, ok := isValidColumnDefinition(tt.elem)
+			col, ok := isValidColumnDefinition(tt.elem, nil)
 			if ok != tt.wantOk {
 				t.Errorf("isValidColumnDefinition(%q) ok = %v, want %v", tt.elem, ok, tt.wantOk)
 			}
@@ -407,6 +736,8 @@ func TestIsValidTableConstraint(t *testing.T) {
 		"CONSTRAINT",    // incompleto
 		"CONSTRAINT pk", // sin tipo
 		"INVALID (id)",
+		"CHECK",                   // sin expresion
+		"CONSTRAINT chk CHECK ()", // expresion vacia
 	}
 
 	for _, c := range valid {
@@ -530,7 +861,7 @@ func TestValidatorVerbose(t *testing.T) {
 		}
 
 		// Validación completa
-		valid := isValidCreateTableStatement(stmt)
+		valid := isValidCreateTableStatement(stmt, nil)
 		fmt.Printf("  Sentencia válida: %v\n", valid)
 	}
 
@@ -10,6 +10,8 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
+
+	"store"
 )
 
 var sqsClient *sqs.Client
@@ -31,16 +33,10 @@ func initSQSClient() {
 	}
 }
 
-// SQSMessage is the message body sent to the conversion queue.
-type SQSMessage struct {
-	ConversionID     string `json:"conversionId"`
-	SQLContent       string `json:"sqlContent"`
-	OptimizationType string `json:"optimizationType"`
-	TablesExtracted  int    `json:"tablesExtracted"`
-}
-
 // SendToQueue sends a conversion record to the SQS queue for async processing.
-func SendToQueue(ctx context.Context, record *ConversionRecord) error {
+// SQL content the store offloaded to S3 travels as its reference, keeping the
+// message under the SQS size limit.
+func SendToQueue(ctx context.Context, record *store.Conversion) error {
 	queueURL := os.Getenv("SQS_QUEUE_URL")
 	if queueURL == "" {
 		return fmt.Errorf("SQS_QUEUE_URL not set")
@@ -50,14 +46,7 @@ func SendToQueue(ctx context.Context, record *ConversionRecord) error {
 		return fmt.Errorf("SQS client not initialized")
 	}
 
-	msg := SQSMessage{
-		ConversionID:     record.ConversionID,
-		SQLContent:       record.SQLContent,
-		OptimizationType: record.OptimizationType,
-		TablesExtracted:  record.TablesExtracted,
-	}
-
-	body, err := json.Marshal(msg)
+	body, err := json.Marshal(store.NewQueueMessage(record))
 	if err != nil {
 		return fmt.Errorf("failed to marshal SQS message: %w", err)
 	}
@@ -2,37 +2,141 @@ package main
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
+	"strconv"
+	"time"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
+
+	"store"
 )
 
-func handler(ctx context.Context, sqsEvent events.SQSEvent) error {
+// conversions is the schemas table, wired to DynamoDB in main
+var conversions store.ConversionStore
+
+// handler marks each conversion of the batch as FAILED, with the context of
+// its failed deliveries, and reports the records whose update failed, so SQS
+// only redelivers those.
+func handler(ctx context.Context, sqsEvent events.SQSEvent) (events.SQSEventResponse, error) {
 	log.Printf("DLQ Handler: received %d message(s)", len(sqsEvent.Records))
 
+	var failures []events.SQSBatchItemFailure
 	for _, record := range sqsEvent.Records {
-		var msg SQSMessageBody
-		if err := json.Unmarshal([]byte(record.Body), &msg); err != nil {
-			log.Printf("ERROR: Failed to parse SQS message body: %v", err)
-			return err
+		if err := processRecord(ctx, record); err != nil {
+			log.Printf("ERROR processing message %s: %v", record.MessageId, err)
+			failures = append(failures, events.SQSBatchItemFailure{ItemIdentifier: record.MessageId})
 		}
+	}
 
-		log.Printf("[%s] Marking conversion as FAILED (max retries exceeded)", msg.ConversionID)
+	return events.SQSEventResponse{BatchItemFailures: failures}, nil
+}
 
-		if err := UpdateStatusToFailed(ctx, msg.ConversionID, "Max retries exceeded"); err != nil {
-			log.Printf("[%s] ERROR: Failed to update status to FAILED: %v", msg.ConversionID, err)
-			return err
-		}
+func processRecord(ctx context.Context, record events.SQSMessage) error {
+	var msg store.QueueMessage
+	if err := json.Unmarshal([]byte(record.Body), &msg); err != nil {
+		// Redelivering cannot fix the body: drop it instead of looping
+		log.Printf("ERROR: Discarding message %s with invalid body: %v", record.MessageId, err)
+		return nil
+	}
+
+	if conversions == nil {
+		return fmt.Errorf("conversion store not initialized")
+	}
 
-		log.Printf("[%s] Successfully marked as FAILED", msg.ConversionID)
+	current, err := conversions.Get(ctx, msg.ConversionID)
+	if errors.Is(err, store.ErrNotFound) {
+		// Expired or never created: nothing left to mark
+		log.Printf("[%s] Discarding message %s: conversion not found", msg.ConversionID, record.MessageId)
+		return nil
+	}
+	if err != nil {
+		log.Printf("[%s] ERROR: Failed to read conversion: %v", msg.ConversionID, err)
+		return err
+	}
+	if store.IsTerminal(current.Status) {
+		// The conversion finished on a later attempt; keep its result
+		log.Printf("[%s] Not marking as FAILED: conversion is already %s", msg.ConversionID, current.Status)
+		return nil
+	}
+
+	failure := failureContext(record, current)
+	log.Printf("[%s] Marking conversion as FAILED (max retries exceeded, %d receives, last error: %s)",
+		msg.ConversionID, failure.ReceiveCount, failure.LastErrorCode)
+
+	// The conditional write still protects a conversion completed since Get
+	err = conversions.Fail(ctx, msg.ConversionID, ErrCodeMaxRetriesExceeded, failureMessage(failure), failure)
+	if errors.Is(err, store.ErrInvalidTransition) {
+		log.Printf("[%s] Not marking as FAILED: %v", msg.ConversionID, err)
+		return nil
+	}
+	if err != nil {
+		log.Printf("[%s] ERROR: Failed to update status to FAILED: %v", msg.ConversionID, err)
+		return err
 	}
 
+	log.Printf("[%s] Successfully marked as FAILED", msg.ConversionID)
 	return nil
 }
 
+// failureContext collects what SQS and the worker know about the failed
+// deliveries of a message.
+func failureContext(record events.SQSMessage, current *store.Conversion) *store.Failure {
+	failure := &store.Failure{
+		MessageID:       record.MessageId,
+		FirstReceivedAt: epochMillis(record.Attributes["ApproximateFirstReceiveTimestamp"]),
+		SentAt:          epochMillis(record.Attributes["SentTimestamp"]),
+		LastErrorCode:   current.LastErrorCode,
+		LastError:       current.LastError,
+	}
+	failure.ReceiveCount, _ = strconv.Atoi(record.Attributes["ApproximateReceiveCount"])
+
+	if len(record.MessageAttributes) > 0 {
+		failure.MessageAttributes = make(map[string]string, len(record.MessageAttributes))
+		for name, attr := range record.MessageAttributes {
+			switch {
+			case attr.StringValue != nil:
+				failure.MessageAttributes[name] = *attr.StringValue
+			case attr.BinaryValue != nil:
+				failure.MessageAttributes[name] = base64.StdEncoding.EncodeToString(attr.BinaryValue)
+			}
+		}
+	}
+	return failure
+}
+
+// failureMessage is the human-readable errorMessage of the conversion
+func failureMessage(failure *store.Failure) string {
+	msg := "Max retries exceeded"
+	if failure.ReceiveCount > 0 {
+		msg = fmt.Sprintf("Max retries exceeded after %d deliveries", failure.ReceiveCount)
+	}
+	if failure.LastError != "" {
+		msg += ": " + failure.LastError
+	}
+	return msg
+}
+
+// epochMillis converts an SQS timestamp attribute (milliseconds since the
+// epoch) to RFC 3339, or "" when missing.
+func epochMillis(value string) string {
+	ms, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || ms <= 0 {
+		return ""
+	}
+	return time.UnixMilli(ms).UTC().Format(time.RFC3339)
+}
+
 func main() {
-	initDynamoClient()
+	dynamoStore, err := store.NewDynamoStoreFromEnv(context.Background())
+	if err != nil {
+		log.Printf("WARN: Failed to initialize DynamoDB store: %v", err)
+	} else {
+		conversions = dynamoStore
+	}
 	lambda.Start(handler)
 }
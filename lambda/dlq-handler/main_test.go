@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+
+	"store"
+)
+
+func dlqRecord(messageID, conversionID string) events.SQSMessage {
+	body, _ := json.Marshal(store.QueueMessage{ConversionID: conversionID, OptimizationType: "balanced"})
+	source := "diagrams"
+	return events.SQSMessage{
+		MessageId: messageID,
+		Body:      string(body),
+		Attributes: map[string]string{
+			"ApproximateReceiveCount":          "4",
+			"ApproximateFirstReceiveTimestamp": "1767225600000",
+			"SentTimestamp":                    "1767225599000",
+		},
+		MessageAttributes: map[string]events.SQSMessageAttribute{
+			"source": {StringValue: &source, DataType: "String"},
+		},
+	}
+}
+
+func TestHandler_RecordsFailureContext(t *testing.T) {
+	ctx := context.Background()
+	memStore := store.NewMemoryStore()
+	conversions = memStore
+	t.Cleanup(func() { conversions = nil })
+
+	tests := []struct {
+		name       string
+		setup      func(id string)
+		wantStatus string
+		wantFailed bool
+	}{
+		{"reintentos agotados", func(id string) {
+			memStore.Transition(ctx, id, store.StatusProcessing)
+			memStore.RecordError(ctx, id, "MODEL_THROTTLED", "ThrottlingException: rate exceeded")
+		}, store.StatusFailed, true},
+		{"pendiente sin intentos", func(id string) {}, store.StatusFailed, true},
+		{"completado no se sobrescribe", func(id string) {
+			memStore.Transition(ctx, id, store.StatusProcessing)
+			memStore.SaveResult(ctx, id, `{"tables":[]}`)
+		}, store.StatusCompleted, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := store.NewConversion("CREATE TABLE t (id INT);", "balanced", 1, nil)
+			memStore.Create(ctx, c)
+			tt.setup(c.ConversionID)
+
+			resp, err := handler(ctx, events.SQSEvent{Records: []events.SQSMessage{dlqRecord("msg-1", c.ConversionID)}})
+			if err != nil || len(resp.BatchItemFailures) != 0 {
+				t.Fatalf("unexpected failures: %v %+v", err, resp)
+			}
+
+			got, _ := memStore.Get(ctx, c.ConversionID)
+			if got.Status != tt.wantStatus {
+				t.Fatalf("status = %s, want %s", got.Status, tt.wantStatus)
+			}
+			if !tt.wantFailed {
+				if got.Failure != nil || got.NoSQLSchema == "" {
+					t.Errorf("completed conversion was modified: %+v", got)
+				}
+				return
+			}
+
+			f := got.Failure
+			if got.ErrorCode != ErrCodeMaxRetriesExceeded || f == nil {
+				t.Fatalf("expected failure context, got %+v", got)
+			}
+			if f.ReceiveCount != 4 || f.MessageID != "msg-1" || f.MessageAttributes["source"] != "diagrams" {
+				t.Errorf("unexpected failure: %+v", f)
+			}
+			if f.FirstReceivedAt != "2026-01-01T00:00:00Z" {
+				t.Errorf("firstReceivedAt = %q", f.FirstReceivedAt)
+			}
+			if f.LastErrorCode != got.LastErrorCode {
+				t.Errorf("lastErrorCode = %q, want %q", f.LastErrorCode, got.LastErrorCode)
+			}
+		})
+	}
+}
+
+func TestHandler_DiscardsUnknownConversions(t *testing.T) {
+	conversions = store.NewMemoryStore()
+	t.Cleanup(func() { conversions = nil })
+
+	resp, err := handler(context.Background(), events.SQSEvent{Records: []events.SQSMessage{
+		dlqRecord("msg-1", "missing"),
+		{MessageId: "msg-2", Body: "not json"},
+	}})
+	if err != nil || len(resp.BatchItemFailures) != 0 {
+		t.Fatalf("expected both messages to be discarded: %v %+v", err, resp)
+	}
+}
@@ -1,9 +1,4 @@
 package main
 
-// SQSMessageBody represents the message body sent from process_handler via SQS.
-type SQSMessageBody struct {
-	ConversionID     string `json:"conversionId"`
-	SQLContent       string `json:"sqlContent"`
-	OptimizationType string `json:"optimizationType"`
-	TablesExtracted  int    `json:"tablesExtracted"`
-}
+// ErrCodeMaxRetriesExceeded is recorded when a message exhausts its SQS retries.
+const ErrCodeMaxRetriesExceeded = "MAX_RETRIES_EXCEEDED"
@@ -3,13 +3,19 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"log"
 	"strings"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
+
+	"store"
 )
 
+// conversions is the schemas table, wired to DynamoDB in main
+var conversions store.ConversionStore
+
 func handler(ctx context.Context, req events.APIGatewayV2HTTPRequest) (V2Response, error) {
 	method := req.RequestContext.HTTP.Method
 	path := req.RequestContext.HTTP.Path
@@ -32,46 +38,58 @@ func handler(ctx context.Context, req events.APIGatewayV2HTTPRequest) (V2Respons
 }
 
 func handleGetByID(ctx context.Context, id string) (V2Response, error) {
-	record, err := GetConversionByID(ctx, id)
-	if err != nil {
-		log.Printf("ERROR: GetConversionByID failed: %v", err)
+	if conversions == nil {
+		log.Printf("ERROR: conversion store not initialized")
 		return jsonResponse(500, map[string]string{
 			"error":   "INTERNAL_SERVER_ERROR",
 			"message": "Failed to retrieve conversion",
 		})
 	}
 
-	if record == nil {
+	record, err := conversions.Get(ctx, id)
+	if errors.Is(err, store.ErrNotFound) {
 		return jsonResponse(404, map[string]string{
 			"error":   "NOT_FOUND",
 			"message": "Conversion not found",
 		})
 	}
+	if err != nil {
+		log.Printf("ERROR: Get conversion failed: %v", err)
+		return jsonResponse(500, map[string]string{
+			"error":   "INTERNAL_SERVER_ERROR",
+			"message": "Failed to retrieve conversion",
+		})
+	}
 
-	// Parse noSqlSchema from string to JSON object
-	parseNoSqlSchema(record)
-
-	return jsonResponse(200, record)
+	return jsonResponse(200, newConversionResponse(*record))
 }
 
 func handleListAll(ctx context.Context) (V2Response, error) {
-	records, err := ListConversions(ctx)
+	if conversions == nil {
+		log.Printf("ERROR: conversion store not initialized")
+		return jsonResponse(500, map[string]string{
+			"error":   "INTERNAL_SERVER_ERROR",
+			"message": "Failed to list conversions",
+		})
+	}
+
+	records, err := conversions.List(ctx)
 	if err != nil {
-		log.Printf("ERROR: ListConversions failed: %v", err)
+		log.Printf("ERROR: List conversions failed: %v", err)
 		return jsonResponse(500, map[string]string{
 			"error":   "INTERNAL_SERVER_ERROR",
 			"message": "Failed to list conversions",
 		})
 	}
 
-	// Parse noSqlSchema for all records
+	responses := make([]conversionResponse, 0, len(records))
 	for _, record := range records {
-		parseNoSqlSchema(record)
+		responses = append(responses, newConversionResponse(record))
 	}
 
 	return jsonResponse(200, map[string]interface{}{
-		"conversions": records,
-		"count":       len(records),
+		"conversions": responses,
+		"count":       len(responses),
 	})
 }
 
@@ -87,18 +105,45 @@ func jsonResponse(statusCode int, body interface{}) (V2Response, error) {
 }
 
 func main() {
-	initDynamoClient()
+	dynamoStore, err := store.NewDynamoStoreFromEnv(context.Background())
+	if err != nil {
+		log.Printf("WARN: Failed to initialize DynamoDB store: %v", err)
+	} else {
+		conversions = dynamoStore
+	}
 	lambda.Start(handler)
 }
 
-// parseNoSqlSchema converts the noSqlSchema string to a JSON object
-func parseNoSqlSchema(record map[string]interface{}) {
-	if noSqlSchemaStr, ok := record["noSqlSchema"].(string); ok && noSqlSchemaStr != "" {
-		var noSqlSchema interface{}
-		if err := json.Unmarshal([]byte(noSqlSchemaStr), &noSqlSchema); err == nil {
-			record["noSqlSchema"] = noSqlSchema
-		} else {
-			log.Printf("WARN: Failed to parse noSqlSchema JSON: %v", err)
-		}
+// conversionResponse is a conversion with noSqlSchema returned as JSON
+// instead of the string stored in DynamoDB. The numeric attributes
+// (expiresAt, tablesExtracted, version, attempts) are JSON numbers and
+// designHints a JSON array; see "API Endpoints" in the README.
+type conversionResponse struct {
+	store.Conversion
+	NoSQLSchema interface{} `json:"noSqlSchema,omitempty"`
+}
+
+// newConversionResponse parses noSqlSchema; a model response that is not
+// valid JSON is returned as is.
+func newConversionResponse(c store.Conversion) conversionResponse {
+	// The cached model response is internal to the worker
+	c.ModelResponse = ""
+	if len(c.DesignHints) > 0 && !json.Valid(c.DesignHints) {
+		log.Printf("[%s] WARN: Dropping designHints that are not valid JSON", c.ConversionID)
+		c.DesignHints = nil
+	}
+
+	resp := conversionResponse{Conversion: c}
+	if c.NoSQLSchema == "" {
+		return resp
+	}
+
+	var parsed interface{}
+	if err := json.Unmarshal([]byte(c.NoSQLSchema), &parsed); err == nil {
+		resp.NoSQLSchema = parsed
+	} else {
+		log.Printf("WARN: Failed to parse noSqlSchema JSON: %v", err)
+		resp.NoSQLSchema = c.NoSQLSchema
 	}
+	return resp
 }
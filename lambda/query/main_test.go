@@ -0,0 +1,187 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+
+	"store"
+)
+
+func getRequest(path, id string) events.APIGatewayV2HTTPRequest {
+	req := events.APIGatewayV2HTTPRequest{}
+	req.RequestContext.HTTP.Method = "GET"
+	req.RequestContext.HTTP.Path = path
+	if id != "" {
+		req.PathParameters = map[string]string{"id": id}
+	}
+	return req
+}
+
+func decodeBody(t *testing.T, resp V2Response) map[string]interface{} {
+	t.Helper()
+	var body map[string]interface{}
+	if err := json.Unmarshal([]byte(resp.Body), &body); err != nil {
+		t.Fatalf("body is not JSON: %v (%s)", err, resp.Body)
+	}
+	return body
+}
+
+func TestHandler(t *testing.T) {
+	ctx := context.Background()
+	memStore := store.NewMemoryStore()
+	conversions = memStore
+	t.Cleanup(func() { conversions = nil })
+
+	completed := store.NewConversion("CREATE TABLE t (id INT);", "balanced", 1, json.RawMessage(`[{"type":"TIME_SERIES"}]`))
+	memStore.Create(ctx, completed)
+	memStore.Transition(ctx, completed.ConversionID, store.StatusProcessing)
+	memStore.SaveModelResponse(ctx, completed.ConversionID, "raw model output", store.ModelInfo{})
+	memStore.SaveResult(ctx, completed.ConversionID, `{"tables":[{"tableName":"t"}]}`)
+
+	pending := store.NewConversion("CREATE TABLE p (id INT);", "read_heavy", 1, nil)
+	memStore.Create(ctx, pending)
+
+	tests := []struct {
+		name       string
+		req        events.APIGatewayV2HTTPRequest
+		wantStatus int
+		check      func(t *testing.T, body map[string]interface{})
+	}{
+		{"obtener por ID", getRequest("/api/v1/schemas/"+completed.ConversionID, completed.ConversionID), 200,
+			func(t *testing.T, body map[string]interface{}) {
+				if body["conversionId"] != completed.ConversionID || body["status"] != store.StatusCompleted {
+					t.Errorf("unexpected conversion: %v", body)
+				}
+				if schema, ok := body["noSqlSchema"].(map[string]interface{}); !ok || schema["tables"] == nil {
+					t.Errorf("noSqlSchema should be a JSON object, got %#v", body["noSqlSchema"])
+				}
+				if _, ok := body["designHints"].([]interface{}); !ok {
+					t.Errorf("designHints should be a JSON array, got %#v", body["designHints"])
+				}
+				if _, ok := body["expiresAt"].(float64); !ok {
+					t.Errorf("expiresAt should be a number, got %#v", body["expiresAt"])
+				}
+				if _, ok := body["modelResponse"]; ok {
+					t.Error("the cached model response must not be returned")
+				}
+			}},
+		{"no encontrada", getRequest("/api/v1/schemas/missing", "missing"), 404,
+			func(t *testing.T, body map[string]interface{}) {
+				if body["error"] != "NOT_FOUND" {
+					t.Errorf("unexpected error: %v", body)
+				}
+			}},
+		{"listar", getRequest("/api/v1/schemas", ""), 200,
+			func(t *testing.T, body map[string]interface{}) {
+				list, _ := body["conversions"].([]interface{})
+				if body["count"] != float64(2) || len(list) != 2 {
+					t.Errorf("unexpected list: %v", body)
+				}
+			}},
+		{"ruta inexistente", getRequest("/api/v1/other", ""), 404, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := handler(ctx, tt.req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (%s)", resp.StatusCode, tt.wantStatus, resp.Body)
+			}
+			if tt.check != nil {
+				tt.check(t, decodeBody(t, resp))
+			}
+		})
+	}
+}
+
+func TestHandler_StoreNotInitialized(t *testing.T) {
+	conversions = nil
+	resp, _ := handler(context.Background(), getRequest("/api/v1/schemas", ""))
+	if resp.StatusCode != 500 {
+		t.Errorf("status = %d, want 500", resp.StatusCode)
+	}
+}
+
+// Un resultado guardado en S3 o comprimido en el item llega resuelto como JSON
+func TestHandler_StoredPayloads(t *testing.T) {
+	ctx := context.Background()
+	schema := `{"tables":[{"tableName":"orders"}]}`
+
+	payloads := store.NewMemoryPayloadStore()
+	ref, _ := payloads.Put(ctx, "conversions/offloaded/noSqlSchema", []byte(schema))
+
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	zw.Write([]byte(schema))
+	zw.Close()
+
+	items := map[string]string{
+		"offloaded":  `{"conversionId": {"S": "offloaded"}, "status": {"S": "COMPLETED"}, "noSqlSchemaRef": {"S": "` + ref + `"}}`,
+		"compressed": `{"conversionId": {"S": "compressed"}, "status": {"S": "COMPLETED"}, "contentEncoding": {"S": "gzip"}, "noSqlSchema": {"B": "` + base64.StdEncoding.EncodeToString(gz.Bytes()) + `"}}`,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var in struct {
+			Key struct {
+				ConversionID struct {
+					S string `json:"S"`
+				} `json:"conversionId"`
+			} `json:"Key"`
+		}
+		json.NewDecoder(r.Body).Decode(&in)
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		w.Write([]byte(`{"Item": ` + items[in.Key.ConversionID.S] + `}`))
+	}))
+	defer srv.Close()
+
+	cfg := aws.Config{Region: "us-east-1", Credentials: credentials.NewStaticCredentialsProvider("test", "test", ""), HTTPClient: srv.Client()}
+	client := dynamodb.NewFromConfig(cfg, func(o *dynamodb.Options) {
+		o.BaseEndpoint = aws.String(srv.URL)
+	})
+	conversions = store.NewDynamoStore(client, "schemas").WithPayloads(payloads, 16)
+	t.Cleanup(func() { conversions = nil })
+
+	for _, id := range []string{"offloaded", "compressed"} {
+		t.Run(id, func(t *testing.T) {
+			resp, err := handler(ctx, getRequest("/api/v1/schemas/"+id, id))
+			if err != nil || resp.StatusCode != 200 {
+				t.Fatalf("status = %d, err = %v (%s)", resp.StatusCode, err, resp.Body)
+			}
+			body := decodeBody(t, resp)
+			got, _ := json.Marshal(body["noSqlSchema"])
+			if string(got) != schema {
+				t.Errorf("noSqlSchema = %s, want %s", got, schema)
+			}
+		})
+	}
+}
+
+// Hints guardados que no son JSON no rompen la respuesta
+func TestNewConversionResponse_InvalidDesignHints(t *testing.T) {
+	c := store.NewConversion("CREATE TABLE t (id INT);", "balanced", 1, json.RawMessage(`[{"type":`))
+	b, err := json.Marshal(newConversionResponse(*c))
+	if err != nil {
+		t.Fatalf("response does not marshal: %v", err)
+	}
+	var body map[string]interface{}
+	json.Unmarshal(b, &body)
+	if _, ok := body["designHints"]; ok {
+		t.Errorf("invalid designHints should be dropped: %s", b)
+	}
+	if body["tablesExtracted"] != float64(1) || body["version"] != float64(1) {
+		t.Errorf("unexpected numeric fields: %s", b)
+	}
+}
@@ -0,0 +1,239 @@
+// Package redrive lists the conversions that exhausted their retries and
+// sends them back to the conversion queue. It backs the admin endpoint of
+// the process handler and the sql2ddb dlq subcommand.
+//
+// The DLQ handler Lambda consumes the dead-letter queue and marks each
+// conversion FAILED with the context of its failed deliveries, so the DLQ
+// itself stays empty: the redrive reads those FAILED records from the
+// conversions table and builds a new message for each one.
+package redrive
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+
+	"store"
+)
+
+// Redrive actions reported per conversion
+const (
+	ActionRedriven     = "REDRIVEN"
+	ActionWouldRedrive = "WOULD_REDRIVE"
+	ActionSkipped      = "SKIPPED"
+	ActionFailed       = "FAILED"
+)
+
+// DefaultMaxMessages bounds how many dead-lettered conversions one call reads
+const DefaultMaxMessages = 100
+
+// SQSAPI is the subset of the SQS client the redriver uses.
+type SQSAPI interface {
+	SendMessage(ctx context.Context, in *sqs.SendMessageInput, optFns ...func(*sqs.Options)) (*sqs.SendMessageOutput, error)
+}
+
+// Redriver moves dead-lettered conversions back to the conversion queue.
+type Redriver struct {
+	client      SQSAPI
+	conversions store.ConversionStore
+	queueURL    string
+}
+
+// New returns a redriver that sends to queueURL.
+func New(client SQSAPI, conversions store.ConversionStore, queueURL string) *Redriver {
+	return &Redriver{client: client, conversions: conversions, queueURL: queueURL}
+}
+
+// NewSQSClient builds an SQS client from the default AWS config, honoring
+// SQS_ENDPOINT (LocalStack) like the Lambdas.
+func NewSQSClient(ctx context.Context) (*sqs.Client, error) {
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	if endpoint := os.Getenv("SQS_ENDPOINT"); endpoint != "" {
+		return sqs.NewFromConfig(cfg, func(o *sqs.Options) {
+			o.BaseEndpoint = aws.String(endpoint)
+		}), nil
+	}
+	return sqs.NewFromConfig(cfg), nil
+}
+
+// Message is a dead-lettered conversion with the context the DLQ handler
+// recorded about its last message.
+type Message struct {
+	MessageID    string         `json:"messageId,omitempty"`
+	ConversionID string         `json:"conversionId"`
+	ReceiveCount int            `json:"receiveCount"`
+	SentAt       string         `json:"sentAt,omitempty"`
+	FailedAt     string         `json:"failedAt,omitempty"`
+	Status       string         `json:"status,omitempty"`
+	ErrorCode    string         `json:"errorCode,omitempty"`
+	ErrorMessage string         `json:"errorMessage,omitempty"`
+	Failure      *store.Failure `json:"failure,omitempty"`
+}
+
+// Options selects the conversions to redrive.
+type Options struct {
+	// ConversionIDs to redrive; ignored when All is set.
+	ConversionIDs []string
+	// All redrives every dead-lettered conversion, up to MaxMessages.
+	All bool
+	// DryRun reports what would be redriven without changing anything.
+	DryRun      bool
+	MaxMessages int
+}
+
+// Result is the outcome of the redrive of one conversion.
+type Result struct {
+	MessageID    string `json:"messageId,omitempty"`
+	ConversionID string `json:"conversionId"`
+	Action       string `json:"action"`
+	Reason       string `json:"reason,omitempty"`
+}
+
+// List returns up to maxMessages conversions that exhausted their retries.
+func (r *Redriver) List(ctx context.Context, maxMessages int) ([]Message, error) {
+	conversions, err := r.deadLettered(ctx, maxMessages)
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]Message, 0, len(conversions))
+	for i := range conversions {
+		messages = append(messages, describe(&conversions[i]))
+	}
+	return messages, nil
+}
+
+// Redrive resets the selected FAILED conversions to PENDING and sends a new
+// message for each one to the conversion queue. Conversions in any other
+// status, or expired, are skipped.
+func (r *Redriver) Redrive(ctx context.Context, opts Options) ([]Result, error) {
+	if !opts.All && len(opts.ConversionIDs) == 0 {
+		return nil, errors.New("select conversion IDs or all conversions")
+	}
+	if r.queueURL == "" {
+		return nil, errors.New("conversion queue URL is required")
+	}
+
+	var results []Result
+	if opts.All {
+		conversions, err := r.deadLettered(ctx, opts.MaxMessages)
+		if err != nil {
+			return nil, err
+		}
+		for i := range conversions {
+			results = append(results, r.redriveConversion(ctx, &conversions[i], opts.DryRun))
+		}
+		return results, nil
+	}
+
+	for _, id := range opts.ConversionIDs {
+		c, err := r.conversions.Get(ctx, id)
+		switch {
+		case errors.Is(err, store.ErrNotFound):
+			results = append(results, Result{ConversionID: id, Action: ActionSkipped, Reason: "conversion not found or expired"})
+		case err != nil:
+			results = append(results, Result{ConversionID: id, Action: ActionFailed, Reason: err.Error()})
+		default:
+			results = append(results, r.redriveConversion(ctx, c, opts.DryRun))
+		}
+	}
+	return results, nil
+}
+
+// redriveConversion requeues the conversion before sending the message, so
+// the worker never receives it while the record is still FAILED.
+func (r *Redriver) redriveConversion(ctx context.Context, c *store.Conversion, dryRun bool) Result {
+	result := Result{ConversionID: c.ConversionID}
+	if c.Failure != nil {
+		result.MessageID = c.Failure.MessageID
+	}
+
+	switch {
+	case c.Status != store.StatusFailed:
+		result.Action, result.Reason = ActionSkipped, fmt.Sprintf("conversion is %s", c.Status)
+		return result
+	case dryRun:
+		result.Action = ActionWouldRedrive
+		return result
+	}
+
+	fail := func(err error) Result {
+		result.Action, result.Reason = ActionFailed, err.Error()
+		return result
+	}
+
+	body, err := json.Marshal(store.NewQueueMessage(c))
+	if err != nil {
+		return fail(fmt.Errorf("failed to marshal queue message: %w", err))
+	}
+	if err := r.conversions.Requeue(ctx, c.ConversionID); err != nil {
+		return fail(err)
+	}
+	_, err = r.client.SendMessage(ctx, &sqs.SendMessageInput{
+		QueueUrl:    aws.String(r.queueURL),
+		MessageBody: aws.String(string(body)),
+	})
+	if err != nil {
+		// Put the record back to FAILED, so a new redrive finds it
+		if failErr := r.conversions.Fail(ctx, c.ConversionID, c.ErrorCode, c.ErrorMessage, c.Failure); failErr != nil {
+			log.Printf("[%s] WARN: Conversion left PENDING without a message: %v", c.ConversionID, failErr)
+		}
+		return fail(fmt.Errorf("SQS SendMessage failed: %w", err))
+	}
+
+	log.Printf("[%s] Redriven to the conversion queue", c.ConversionID)
+	result.Action = ActionRedriven
+	return result
+}
+
+// deadLettered returns up to maxMessages FAILED conversions the DLQ handler
+// marked after their retries ran out.
+func (r *Redriver) deadLettered(ctx context.Context, maxMessages int) ([]store.Conversion, error) {
+	if maxMessages <= 0 {
+		maxMessages = DefaultMaxMessages
+	}
+
+	all, err := r.conversions.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var conversions []store.Conversion
+	for _, c := range all {
+		if c.Status != store.StatusFailed || c.Failure == nil {
+			continue
+		}
+		conversions = append(conversions, c)
+		if len(conversions) == maxMessages {
+			break
+		}
+	}
+	return conversions, nil
+}
+
+func describe(c *store.Conversion) Message {
+	msg := Message{
+		ConversionID: c.ConversionID,
+		FailedAt:     c.FailedAt,
+		Status:       c.Status,
+		ErrorCode:    c.ErrorCode,
+		ErrorMessage: c.ErrorMessage,
+		Failure:      c.Failure,
+	}
+	if c.Failure != nil {
+		msg.MessageID = c.Failure.MessageID
+		msg.ReceiveCount = c.Failure.ReceiveCount
+		msg.SentAt = c.Failure.SentAt
+	}
+	return msg
+}
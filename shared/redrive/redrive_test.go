@@ -0,0 +1,165 @@
+package redrive
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+
+	"store"
+)
+
+// fakeSQS records the messages sent to the conversion queue
+type fakeSQS struct {
+	mu   sync.Mutex
+	sent []string
+	err  error
+}
+
+func (f *fakeSQS) SendMessage(ctx context.Context, in *sqs.SendMessageInput, _ ...func(*sqs.Options)) (*sqs.SendMessageOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return nil, f.err
+	}
+	f.sent = append(f.sent, aws.ToString(in.MessageBody))
+	return &sqs.SendMessageOutput{}, nil
+}
+
+// setup stores a conversion the DLQ handler failed, one that failed
+// permanently and a completed one
+func setup(t *testing.T) (*Redriver, *fakeSQS, *store.MemoryStore, string, string, string) {
+	ctx := context.Background()
+	memStore := store.NewMemoryStore()
+
+	deadLettered := store.NewConversion("CREATE TABLE t (id INT);", "balanced", 1, json.RawMessage(`[{"type":"TIME_SERIES"}]`))
+	memStore.Create(ctx, deadLettered)
+	memStore.Transition(ctx, deadLettered.ConversionID, store.StatusProcessing)
+	memStore.Fail(ctx, deadLettered.ConversionID, "MAX_RETRIES_EXCEEDED", "Max retries exceeded",
+		&store.Failure{MessageID: "msg-1", ReceiveCount: 3, LastErrorCode: "MODEL_THROTTLED"})
+
+	permanent := store.NewConversion("CREATE TABLE t (id INT);", "balanced", 1, nil)
+	memStore.Create(ctx, permanent)
+	memStore.Transition(ctx, permanent.ConversionID, store.StatusProcessing)
+	memStore.Fail(ctx, permanent.ConversionID, "MODEL_INVALID_OUTPUT", "invalid design", nil)
+
+	completed := store.NewConversion("CREATE TABLE t (id INT);", "balanced", 1, nil)
+	memStore.Create(ctx, completed)
+	memStore.Transition(ctx, completed.ConversionID, store.StatusProcessing)
+	memStore.SaveResult(ctx, completed.ConversionID, "{}")
+
+	fake := &fakeSQS{}
+	return New(fake, memStore, "queue-url"), fake, memStore, deadLettered.ConversionID, permanent.ConversionID, completed.ConversionID
+}
+
+func TestList(t *testing.T) {
+	r, fake, _, deadLetteredID, _, _ := setup(t)
+
+	messages, err := r.List(context.Background(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(messages) != 1 {
+		t.Fatalf("expected only the dead-lettered conversion, got %+v", messages)
+	}
+	if m := messages[0]; m.ConversionID != deadLetteredID || m.MessageID != "msg-1" || m.ReceiveCount != 3 || m.Failure == nil {
+		t.Errorf("unexpected message: %+v", m)
+	}
+	if len(fake.sent) != 0 {
+		t.Errorf("List must not send messages")
+	}
+}
+
+func TestRedrive(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        func(deadLetteredID, permanentID, completedID string) Options
+		wantActions map[string]int
+		wantSent    int
+	}{
+		{"simulación", func(string, string, string) Options { return Options{All: true, DryRun: true} },
+			map[string]int{ActionWouldRedrive: 1}, 0},
+		{"todos los agotados", func(string, string, string) Options { return Options{All: true} },
+			map[string]int{ActionRedriven: 1}, 1},
+		{"seleccionados", func(d, p, c string) Options { return Options{ConversionIDs: []string{d, p, c, "missing"}} },
+			map[string]int{ActionRedriven: 2, ActionSkipped: 2}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			r, fake, memStore, deadLetteredID, permanentID, completedID := setup(t)
+
+			results, err := r.Redrive(ctx, tt.opts(deadLetteredID, permanentID, completedID))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			actions := map[string]int{}
+			for _, result := range results {
+				actions[result.Action]++
+			}
+			if len(actions) != len(tt.wantActions) {
+				t.Errorf("got actions %v, want %v", actions, tt.wantActions)
+			}
+			for action, want := range tt.wantActions {
+				if actions[action] != want {
+					t.Errorf("%s: got %d, want %d (%+v)", action, actions[action], want, results)
+				}
+			}
+			if len(fake.sent) != tt.wantSent {
+				t.Fatalf("sent %d messages, want %d", len(fake.sent), tt.wantSent)
+			}
+
+			wantStatus := store.StatusFailed
+			if tt.wantSent > 0 {
+				wantStatus = store.StatusPending
+
+				var msg store.QueueMessage
+				if err := json.Unmarshal([]byte(fake.sent[0]), &msg); err != nil || msg.ConversionID != deadLetteredID || msg.SQLContent == "" || len(msg.DesignHints) == 0 {
+					t.Errorf("unexpected message: %s", fake.sent[0])
+				}
+			}
+			if got, _ := memStore.Get(ctx, deadLetteredID); got.Status != wantStatus {
+				t.Errorf("dead-lettered conversion status = %s, want %s", got.Status, wantStatus)
+			}
+			if got, _ := memStore.Get(ctx, completedID); got.Status != store.StatusCompleted {
+				t.Errorf("completed conversion was modified: %s", got.Status)
+			}
+		})
+	}
+}
+
+func TestRedrive_SendFailureKeepsConversionFailed(t *testing.T) {
+	ctx := context.Background()
+	r, fake, memStore, deadLetteredID, _, _ := setup(t)
+	fake.err = errors.New("unavailable")
+
+	results, err := r.Redrive(ctx, Options{ConversionIDs: []string{deadLetteredID}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].Action != ActionFailed {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+
+	// Sigue en FAILED con su contexto: un nuevo redrive la encuentra
+	messages, _ := r.List(ctx, 0)
+	if len(messages) != 1 || messages[0].ConversionID != deadLetteredID {
+		t.Errorf("expected the conversion to stay dead-lettered, got %+v", messages)
+	}
+	if got, _ := memStore.Get(ctx, deadLetteredID); got.Status != store.StatusFailed {
+		t.Errorf("status = %s, want FAILED", got.Status)
+	}
+}
+
+func TestRedrive_RequiresSelection(t *testing.T) {
+	r, _, _, _, _, _ := setup(t)
+	if _, err := r.Redrive(context.Background(), Options{}); err == nil {
+		t.Error("expected an error without conversion IDs or All")
+	}
+}
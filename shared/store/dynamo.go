@@ -0,0 +1,742 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// ContentHashIndex is the GSI of the schemas table that finds conversions of
+// equivalent SQL (contentHash, createdAt), projecting status and expiresAt.
+const ContentHashIndex = "contentHash-createdAt-index"
+
+// DynamoStore is the ConversionStore backed by the DynamoDB schemas table.
+// With a PayloadStore, SQL content, results and model responses above the
+// threshold are stored there and the item keeps a reference (*Ref
+// attributes), which Get resolves. List leaves them as references.
+type DynamoStore struct {
+	client    *dynamodb.Client
+	tableName string
+	payloads  PayloadStore
+	threshold int
+}
+
+// NewDynamoStore returns a store over an existing client and table.
+func NewDynamoStore(client *dynamodb.Client, tableName string) *DynamoStore {
+	return &DynamoStore{client: client, tableName: tableName}
+}
+
+// WithPayloads offloads payloads larger than threshold bytes to payloads.
+func (s *DynamoStore) WithPayloads(payloads PayloadStore, threshold int) *DynamoStore {
+	s.payloads = payloads
+	s.threshold = threshold
+	return s
+}
+
+// NewDynamoStoreFromEnv builds the store the way every Lambda did: default AWS
+// config, DYNAMODB_ENDPOINT (LocalStack) and DYNAMODB_TABLE_NAME. When
+// PAYLOAD_BUCKET is set, large payloads are offloaded to S3 above
+// PAYLOAD_THRESHOLD_BYTES (DefaultPayloadThreshold).
+func NewDynamoStoreFromEnv(ctx context.Context) (*DynamoStore, error) {
+	tableName := os.Getenv("DYNAMODB_TABLE_NAME")
+	if tableName == "" {
+		return nil, fmt.Errorf("DYNAMODB_TABLE_NAME not set")
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	var client *dynamodb.Client
+	if endpoint := os.Getenv("DYNAMODB_ENDPOINT"); endpoint != "" {
+		client = dynamodb.NewFromConfig(cfg, func(o *dynamodb.Options) {
+			o.BaseEndpoint = aws.String(endpoint)
+		})
+	} else {
+		client = dynamodb.NewFromConfig(cfg)
+	}
+
+	s := NewDynamoStore(client, tableName)
+	if os.Getenv("PAYLOAD_BUCKET") == "" {
+		return s, nil
+	}
+
+	payloads, err := NewS3PayloadStoreFromEnv(ctx)
+	if err != nil {
+		return nil, err
+	}
+	threshold := DefaultPayloadThreshold
+	if n, err := strconv.Atoi(os.Getenv("PAYLOAD_THRESHOLD_BYTES")); err == nil && n > 0 {
+		threshold = n
+	}
+	return s.WithPayloads(payloads, threshold), nil
+}
+
+// Create stores a new conversion record; it never overwrites an existing one.
+// An offloaded SQL content or result sets c.SQLContentRef or c.NoSQLSchemaRef.
+func (s *DynamoStore) Create(ctx context.Context, c *Conversion) error {
+	item, err := s.newItem(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(s.tableName),
+		Item:                item,
+		ConditionExpression: aws.String("attribute_not_exists(conversionId)"),
+	})
+	var condErr *types.ConditionalCheckFailedException
+	if errors.As(err, &condErr) {
+		return ErrAlreadyExists
+	}
+	if err != nil {
+		return fmt.Errorf("DynamoDB PutItem failed: %w", err)
+	}
+
+	log.Printf("[%s] DynamoDB record created (status: %s)", c.ConversionID, c.Status)
+	return nil
+}
+
+// newItem builds the item of a new conversion, offloading its payloads. A
+// payload that already has a reference (a result reused from the cache) is
+// copied under the new conversion instead of being downloaded and uploaded
+// again, so every record owns the objects it references.
+func (s *DynamoStore) newItem(ctx context.Context, c *Conversion) (map[string]types.AttributeValue, error) {
+	item := marshalConversion(c)
+	for _, p := range []struct {
+		name  string
+		value string
+		ref   *string
+	}{
+		{"sqlContent", c.SQLContent, &c.SQLContentRef},
+		{"noSqlSchema", c.NoSQLSchema, &c.NoSQLSchemaRef},
+	} {
+		if *p.ref != "" {
+			ref, err := s.copyPayload(ctx, c.ConversionID, p.name, *p.ref)
+			if err != nil {
+				return nil, err
+			}
+			delete(item, p.name)
+			item[p.name+"Ref"] = &types.AttributeValueMemberS{Value: ref}
+			*p.ref = ref
+			continue
+		}
+		if p.value == "" {
+			continue
+		}
+		attrs, ref, err := s.offload(ctx, c.ConversionID, p.name, p.value)
+		if err != nil {
+			return nil, err
+		}
+		delete(item, p.name)
+		for name, value := range attrs {
+			item[name] = value
+		}
+		*p.ref = ref
+	}
+	return item, nil
+}
+
+// Get retrieves a single conversion record by its ID, with its offloaded
+// payloads resolved.
+func (s *DynamoStore) Get(ctx context.Context, conversionID string) (*Conversion, error) {
+	c, err := s.getItem(ctx, conversionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.resolve(ctx, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// getItem retrieves a conversion record leaving its offloaded payloads as
+// references.
+func (s *DynamoStore) getItem(ctx context.Context, conversionID string) (*Conversion, error) {
+	result, err := s.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(s.tableName),
+		Key:       conversionKey(conversionID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("DynamoDB GetItem failed: %w", err)
+	}
+
+	if result.Item == nil {
+		return nil, ErrNotFound
+	}
+
+	c := unmarshalConversion(result.Item)
+	return &c, nil
+}
+
+// List retrieves all conversion records via Scan, following pagination.
+// Offloaded payloads are left as references: listing does not download one
+// object per record.
+func (s *DynamoStore) List(ctx context.Context) ([]Conversion, error) {
+	var conversions []Conversion
+	input := &dynamodb.ScanInput{TableName: aws.String(s.tableName)}
+
+	for {
+		result, err := s.client.Scan(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("DynamoDB Scan failed: %w", err)
+		}
+		for _, item := range result.Items {
+			conversions = append(conversions, unmarshalConversion(item))
+		}
+		if len(result.LastEvaluatedKey) == 0 {
+			break
+		}
+		input.ExclusiveStartKey = result.LastEvaluatedKey
+	}
+
+	log.Printf("Listed %d conversion records", len(conversions))
+	return conversions, nil
+}
+
+// FindCompleted queries the contentHash index, newest first, for a live
+// COMPLETED conversion and returns the full record. The index projects status
+// and expiresAt, so the filter needs no GetItem per candidate. An offloaded
+// result is returned as its reference, which is all ReuseResult needs.
+func (s *DynamoStore) FindCompleted(ctx context.Context, contentHash string) (*Conversion, error) {
+	if contentHash == "" {
+		return nil, ErrNotFound
+	}
+
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(s.tableName),
+		IndexName:              aws.String(ContentHashIndex),
+		KeyConditionExpression: aws.String("contentHash = :h"),
+		// Expired records linger until TTL deletes them
+		FilterExpression:         aws.String("#s = :completed AND expiresAt > :now"),
+		ExpressionAttributeNames: map[string]string{"#s": "status"},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":h":         &types.AttributeValueMemberS{Value: contentHash},
+			":completed": &types.AttributeValueMemberS{Value: StatusCompleted},
+			":now":       &types.AttributeValueMemberN{Value: strconv.FormatInt(time.Now().Unix(), 10)},
+		},
+		ScanIndexForward: aws.Bool(false),
+	}
+
+	for {
+		result, err := s.client.Query(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("DynamoDB Query failed: %w", err)
+		}
+		if len(result.Items) > 0 {
+			return s.getItem(ctx, unmarshalConversion(result.Items[0]).ConversionID)
+		}
+		if len(result.LastEvaluatedKey) == 0 {
+			return nil, ErrNotFound
+		}
+		input.ExclusiveStartKey = result.LastEvaluatedKey
+	}
+}
+
+// Transition moves a conversion record to status. The allowed source
+// statuses are enforced with a ConditionExpression, so concurrent or
+// redelivered updates cannot move a record backwards.
+func (s *DynamoStore) Transition(ctx context.Context, conversionID, status string) error {
+	return s.transition(ctx, conversionID, status, nil)
+}
+
+// Fail marks a conversion record FAILED with its error code and message, and
+// the delivery context as a map attribute when given.
+func (s *DynamoStore) Fail(ctx context.Context, conversionID, errorCode, errorMessage string, failure *Failure) error {
+	extra := map[string]types.AttributeValue{
+		"errorCode":    &types.AttributeValueMemberS{Value: errorCode},
+		"errorMessage": &types.AttributeValueMemberS{Value: errorMessage},
+	}
+	if failure != nil {
+		extra["failure"] = marshalFailure(failure)
+	}
+	return s.transition(ctx, conversionID, StatusFailed, extra)
+}
+
+// SaveResult sets status to COMPLETED and stores the NoSQL schema result.
+func (s *DynamoStore) SaveResult(ctx context.Context, conversionID, noSqlSchema string) error {
+	attrs, _, err := s.offload(ctx, conversionID, "noSqlSchema", noSqlSchema)
+	if err != nil {
+		return err
+	}
+	return s.transition(ctx, conversionID, StatusCompleted, attrs)
+}
+
+// SaveModelResponse caches the model response and the model info while the
+// conversion is PROCESSING; the status and version are left unchanged.
+func (s *DynamoStore) SaveModelResponse(ctx context.Context, conversionID, response string, model ModelInfo) error {
+	attrs, _, err := s.offload(ctx, conversionID, "modelResponse", response)
+	if err != nil {
+		return err
+	}
+	for attr, value := range map[string]string{
+		"promptVersion": model.PromptVersion,
+		"modelId":       model.ModelID,
+	} {
+		if value != "" {
+			attrs[attr] = &types.AttributeValueMemberS{Value: value}
+		}
+	}
+	if err := s.updateProcessing(ctx, conversionID, attrs); err != nil {
+		return err
+	}
+
+	log.Printf("[%s] Model response cached", conversionID)
+	return nil
+}
+
+// RecordError stores the last retryable error while the conversion is
+// PROCESSING; the status and version are left unchanged.
+func (s *DynamoStore) RecordError(ctx context.Context, conversionID, errorCode, errorMessage string) error {
+	return s.updateProcessing(ctx, conversionID, map[string]types.AttributeValue{
+		"lastErrorCode": &types.AttributeValueMemberS{Value: errorCode},
+		"lastError":     &types.AttributeValueMemberS{Value: errorMessage},
+	})
+}
+
+// Requeue resets a conversion record to PENDING for a redrive, removing the
+// error attributes and the cached model response.
+func (s *DynamoStore) Requeue(ctx context.Context, conversionID string) error {
+	names := map[string]string{"#s": "status", "#v": "version", "#r": "redrivenAt"}
+	values := map[string]types.AttributeValue{
+		":pending": &types.AttributeValueMemberS{Value: StatusPending},
+		":zero":    &types.AttributeValueMemberN{Value: "0"},
+		":one":     &types.AttributeValueMemberN{Value: "1"},
+		":now":     &types.AttributeValueMemberS{Value: time.Now().UTC().Format(time.RFC3339)},
+	}
+
+	allowed := make([]string, len(requeueStatuses))
+	for i, from := range requeueStatuses {
+		allowed[i] = fmt.Sprintf(":from%d", i)
+		values[allowed[i]] = &types.AttributeValueMemberS{Value: from}
+	}
+
+	removed := []string{"errorCode", "errorMessage", "failure", "lastErrorCode", "lastError", "modelResponse", "modelResponseRef", "failedAt", "promptVersion", "modelId"}
+	remove := make([]string, len(removed))
+	for i, name := range removed {
+		remove[i] = fmt.Sprintf("#d%d", i)
+		names[remove[i]] = name
+	}
+
+	_, err := s.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(s.tableName),
+		Key:       conversionKey(conversionID),
+		UpdateExpression: aws.String("SET #s = :pending, #v = if_not_exists(#v, :zero) + :one, #r = :now REMOVE " +
+			strings.Join(remove, ", ")),
+		ConditionExpression:                 aws.String(fmt.Sprintf("#s IN (%s)", strings.Join(allowed, ", "))),
+		ExpressionAttributeNames:            names,
+		ExpressionAttributeValues:           values,
+		ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+	})
+	if err := conditionError(err, conversionID, StatusPending); err != nil {
+		return err
+	}
+
+	log.Printf("[%s] Conversion requeued (status: %s)", conversionID, StatusPending)
+	return nil
+}
+
+// updateProcessing sets attributes on a conversion record that is PROCESSING
+func (s *DynamoStore) updateProcessing(ctx context.Context, conversionID string, attrs map[string]types.AttributeValue) error {
+	update := make([]string, 0, len(attrs))
+	names := map[string]string{"#s": "status"}
+	values := map[string]types.AttributeValue{
+		":processing": &types.AttributeValueMemberS{Value: StatusProcessing},
+	}
+	for i, name := range sortedKeys(attrs) {
+		placeholder := fmt.Sprintf("a%d", i)
+		update = append(update, fmt.Sprintf("#%s = :%s", placeholder, placeholder))
+		names["#"+placeholder] = name
+		values[":"+placeholder] = attrs[name]
+	}
+
+	_, err := s.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:                           aws.String(s.tableName),
+		Key:                                 conversionKey(conversionID),
+		UpdateExpression:                    aws.String("SET " + strings.Join(update, ", ")),
+		ConditionExpression:                 aws.String("#s = :processing"),
+		ExpressionAttributeNames:            names,
+		ExpressionAttributeValues:           values,
+		ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+	})
+	return conditionError(err, conversionID, StatusProcessing)
+}
+
+// transition sets the status, increments version, records the transition
+// timestamp and sets the extra attributes, if the current status allows it.
+func (s *DynamoStore) transition(ctx context.Context, conversionID, status string, extra map[string]types.AttributeValue) error {
+	sources := sourceStatuses(status)
+	if len(sources) == 0 {
+		return fmt.Errorf("no status can transition to %s", status)
+	}
+
+	update := []string{"#s = :status", "#v = if_not_exists(#v, :zero) + :one"}
+	names := map[string]string{"#s": "status", "#v": "version"}
+	values := map[string]types.AttributeValue{
+		":status": &types.AttributeValueMemberS{Value: status},
+		":zero":   &types.AttributeValueMemberN{Value: "0"},
+		":one":    &types.AttributeValueMemberN{Value: "1"},
+	}
+
+	if status == StatusProcessing {
+		update = append(update, "#n = if_not_exists(#n, :zero) + :one")
+		names["#n"] = "attempts"
+	}
+
+	if attr, ok := transitionTimestamps[status]; ok {
+		update = append(update, "#t = :now")
+		names["#t"] = attr
+		values[":now"] = &types.AttributeValueMemberS{Value: time.Now().UTC().Format(time.RFC3339)}
+	}
+
+	i := 0
+	for _, name := range sortedKeys(extra) {
+		placeholder := fmt.Sprintf("a%d", i)
+		update = append(update, fmt.Sprintf("#%s = :%s", placeholder, placeholder))
+		names["#"+placeholder] = name
+		values[":"+placeholder] = extra[name]
+		i++
+	}
+
+	allowed := make([]string, len(sources))
+	for i, from := range sources {
+		allowed[i] = fmt.Sprintf(":from%d", i)
+		values[allowed[i]] = &types.AttributeValueMemberS{Value: from}
+	}
+
+	_, err := s.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:                           aws.String(s.tableName),
+		Key:                                 conversionKey(conversionID),
+		UpdateExpression:                    aws.String("SET " + strings.Join(update, ", ")),
+		ConditionExpression:                 aws.String(fmt.Sprintf("#s IN (%s)", strings.Join(allowed, ", "))),
+		ExpressionAttributeNames:            names,
+		ExpressionAttributeValues:           values,
+		ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+	})
+
+	if err := conditionError(err, conversionID, status); err != nil {
+		return err
+	}
+
+	log.Printf("[%s] Status updated to %s", conversionID, status)
+	return nil
+}
+
+// offload returns the attributes that store value: the value itself, or a
+// reference to the payload store when value exceeds the threshold. The
+// reference is "" when the value stays inline. The threshold applies to the
+// uncompressed size, since the SQL content also travels in the SQS message.
+func (s *DynamoStore) offload(ctx context.Context, conversionID, name, value string) (map[string]types.AttributeValue, string, error) {
+	if s.payloads == nil || len(value) <= s.threshold {
+		attrs, err := inlinePayload(name, value)
+		return attrs, "", err
+	}
+
+	ref, err := s.payloads.Put(ctx, payloadKey(conversionID, name), []byte(value))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to offload %s: %w", name, err)
+	}
+	log.Printf("[%s] %s offloaded to %s (%d bytes)", conversionID, name, ref, len(value))
+	return map[string]types.AttributeValue{name + "Ref": &types.AttributeValueMemberS{Value: ref}}, ref, nil
+}
+
+// copyPayload copies an offloaded payload of another conversion under
+// conversionID and returns the new reference.
+func (s *DynamoStore) copyPayload(ctx context.Context, conversionID, name, ref string) (string, error) {
+	if s.payloads == nil {
+		return "", fmt.Errorf("cannot copy %s from %s without a payload store", name, ref)
+	}
+	copied, err := s.payloads.Copy(ctx, ref, payloadKey(conversionID, name))
+	if err != nil {
+		return "", fmt.Errorf("failed to copy %s: %w", name, err)
+	}
+	log.Printf("[%s] %s copied from %s", conversionID, name, ref)
+	return copied, nil
+}
+
+// resolve loads the offloaded payloads of a conversion. Without a payload
+// store (e.g. the DLQ handler, which never reads them) the references are
+// left unresolved.
+func (s *DynamoStore) resolve(ctx context.Context, c *Conversion) error {
+	if s.payloads == nil {
+		return nil
+	}
+
+	for _, p := range []struct {
+		ref   string
+		value *string
+	}{
+		{c.SQLContentRef, &c.SQLContent},
+		{c.NoSQLSchemaRef, &c.NoSQLSchema},
+		{c.ModelResponseRef, &c.ModelResponse},
+	} {
+		if p.ref == "" {
+			continue
+		}
+		data, err := s.payloads.Get(ctx, p.ref)
+		if err != nil {
+			return fmt.Errorf("failed to resolve payload %s: %w", p.ref, err)
+		}
+		*p.value = string(data)
+	}
+	return nil
+}
+
+// inlinePayload returns the attributes of a payload stored in the item:
+// values from compressMinBytes are stored gzip-compressed as binary, with the
+// contentEncoding marker.
+func inlinePayload(name, value string) (map[string]types.AttributeValue, error) {
+	if len(value) < compressMinBytes {
+		return map[string]types.AttributeValue{name: &types.AttributeValueMemberS{Value: value}}, nil
+	}
+
+	data, err := compress(value)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]types.AttributeValue{
+		name:              &types.AttributeValueMemberB{Value: data},
+		"contentEncoding": &types.AttributeValueMemberS{Value: EncodingGzip},
+	}, nil
+}
+
+// conditionError maps a failed status condition to ErrNotFound or a
+// *TransitionError with the current status (ALL_OLD item).
+func conditionError(err error, conversionID, to string) error {
+	var condErr *types.ConditionalCheckFailedException
+	if errors.As(err, &condErr) {
+		if len(condErr.Item) == 0 {
+			return ErrNotFound
+		}
+		current := unmarshalConversion(condErr.Item)
+		return &TransitionError{ConversionID: conversionID, From: current.Status, To: to}
+	}
+	if err != nil {
+		return fmt.Errorf("DynamoDB UpdateItem failed: %w", err)
+	}
+	return nil
+}
+
+func conversionKey(conversionID string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"conversionId": &types.AttributeValueMemberS{Value: conversionID},
+	}
+}
+
+// marshalConversion converts a conversion to a DynamoDB item. designHints and
+// noSqlSchema are stored as JSON strings; Create and the result updates
+// replace the payloads with the attributes offload returns.
+func marshalConversion(c *Conversion) map[string]types.AttributeValue {
+	item := map[string]types.AttributeValue{
+		"conversionId":     &types.AttributeValueMemberS{Value: c.ConversionID},
+		"status":           &types.AttributeValueMemberS{Value: c.Status},
+		"createdAt":        &types.AttributeValueMemberS{Value: c.CreatedAt},
+		"expiresAt":        &types.AttributeValueMemberN{Value: strconv.FormatInt(c.ExpiresAt, 10)},
+		"conversionDate":   &types.AttributeValueMemberS{Value: c.ConversionDate},
+		"sqlContent":       &types.AttributeValueMemberS{Value: c.SQLContent},
+		"optimizationType": &types.AttributeValueMemberS{Value: c.OptimizationType},
+		"tablesExtracted":  &types.AttributeValueMemberN{Value: strconv.Itoa(c.TablesExtracted)},
+	}
+	if len(c.DesignHints) > 0 {
+		item["designHints"] = &types.AttributeValueMemberS{Value: string(c.DesignHints)}
+	}
+	if c.NoSQLSchema != "" {
+		item["noSqlSchema"] = &types.AttributeValueMemberS{Value: c.NoSQLSchema}
+	}
+	if c.ErrorCode != "" {
+		item["errorCode"] = &types.AttributeValueMemberS{Value: c.ErrorCode}
+	}
+	if c.ErrorMessage != "" {
+		item["errorMessage"] = &types.AttributeValueMemberS{Value: c.ErrorMessage}
+	}
+	if c.LastErrorCode != "" {
+		item["lastErrorCode"] = &types.AttributeValueMemberS{Value: c.LastErrorCode}
+	}
+	if c.LastError != "" {
+		item["lastError"] = &types.AttributeValueMemberS{Value: c.LastError}
+	}
+	if c.Failure != nil {
+		item["failure"] = marshalFailure(c.Failure)
+	}
+	if c.Version != 0 {
+		item["version"] = &types.AttributeValueMemberN{Value: strconv.FormatInt(c.Version, 10)}
+	}
+	if c.Attempts != 0 {
+		item["attempts"] = &types.AttributeValueMemberN{Value: strconv.Itoa(c.Attempts)}
+	}
+	if c.ModelResponse != "" {
+		item["modelResponse"] = &types.AttributeValueMemberS{Value: c.ModelResponse}
+	}
+	for attr, ref := range map[string]string{
+		"sqlContentRef":    c.SQLContentRef,
+		"noSqlSchemaRef":   c.NoSQLSchemaRef,
+		"modelResponseRef": c.ModelResponseRef,
+	} {
+		if ref != "" {
+			item[attr] = &types.AttributeValueMemberS{Value: ref}
+		}
+	}
+	for attr, value := range map[string]string{
+		"contentHash":    c.ContentHash,
+		"cachedFrom":     c.CachedFrom,
+		"outputLanguage": c.OutputLanguage,
+		"promptVersion":  c.PromptVersion,
+		"modelId":        c.ModelID,
+		"startedAt":      c.StartedAt,
+		"completedAt":    c.CompletedAt,
+		"failedAt":       c.FailedAt,
+		"cancelledAt":    c.CancelledAt,
+		"redrivenAt":     c.RedrivenAt,
+	} {
+		if value != "" {
+			item[attr] = &types.AttributeValueMemberS{Value: value}
+		}
+	}
+	return item
+}
+
+// unmarshalConversion converts a DynamoDB item to a conversion; missing or
+// malformed attributes are left empty. Compressed payload attributes are
+// decompressed according to contentEncoding.
+func unmarshalConversion(item map[string]types.AttributeValue) Conversion {
+	str := func(name string) string {
+		if v, ok := item[name].(*types.AttributeValueMemberS); ok {
+			return v.Value
+		}
+		return ""
+	}
+	payload := func(name string) string {
+		v, ok := item[name].(*types.AttributeValueMemberB)
+		if !ok {
+			return str(name)
+		}
+		value, err := decompress(str("contentEncoding"), v.Value)
+		if err != nil {
+			log.Printf("[%s] WARN: %s: %v", str("conversionId"), name, err)
+		}
+		return value
+	}
+	num := func(name string) int64 {
+		if v, ok := item[name].(*types.AttributeValueMemberN); ok {
+			n, _ := strconv.ParseInt(v.Value, 10, 64)
+			return n
+		}
+		return 0
+	}
+
+	c := Conversion{
+		ConversionID:     str("conversionId"),
+		Status:           str("status"),
+		CreatedAt:        str("createdAt"),
+		ExpiresAt:        num("expiresAt"),
+		ConversionDate:   str("conversionDate"),
+		SQLContent:       payload("sqlContent"),
+		OptimizationType: str("optimizationType"),
+		TablesExtracted:  int(num("tablesExtracted")),
+		NoSQLSchema:      payload("noSqlSchema"),
+		ErrorCode:        str("errorCode"),
+		ErrorMessage:     str("errorMessage"),
+		LastErrorCode:    str("lastErrorCode"),
+		LastError:        str("lastError"),
+		Version:          num("version"),
+		Attempts:         int(num("attempts")),
+		ModelResponse:    payload("modelResponse"),
+		SQLContentRef:    str("sqlContentRef"),
+		NoSQLSchemaRef:   str("noSqlSchemaRef"),
+		ModelResponseRef: str("modelResponseRef"),
+		StartedAt:        str("startedAt"),
+		CompletedAt:      str("completedAt"),
+		FailedAt:         str("failedAt"),
+		CancelledAt:      str("cancelledAt"),
+		RedrivenAt:       str("redrivenAt"),
+		ContentHash:      str("contentHash"),
+		CachedFrom:       str("cachedFrom"),
+		OutputLanguage:   str("outputLanguage"),
+		PromptVersion:    str("promptVersion"),
+		ModelID:          str("modelId"),
+	}
+	if hints := str("designHints"); hints != "" {
+		c.DesignHints = []byte(hints)
+	}
+	if failure, ok := item["failure"].(*types.AttributeValueMemberM); ok {
+		c.Failure = unmarshalFailure(failure.Value)
+	}
+	return c
+}
+
+// marshalFailure stores the failure context as a map attribute, so it can be
+// read in the console without decoding JSON.
+func marshalFailure(f *Failure) types.AttributeValue {
+	m := map[string]types.AttributeValue{
+		"receiveCount": &types.AttributeValueMemberN{Value: strconv.Itoa(f.ReceiveCount)},
+	}
+	for attr, value := range map[string]string{
+		"messageId":       f.MessageID,
+		"firstReceivedAt": f.FirstReceivedAt,
+		"sentAt":          f.SentAt,
+		"lastErrorCode":   f.LastErrorCode,
+		"lastError":       f.LastError,
+	} {
+		if value != "" {
+			m[attr] = &types.AttributeValueMemberS{Value: value}
+		}
+	}
+	if len(f.MessageAttributes) > 0 {
+		attrs := make(map[string]types.AttributeValue, len(f.MessageAttributes))
+		for name, value := range f.MessageAttributes {
+			attrs[name] = &types.AttributeValueMemberS{Value: value}
+		}
+		m["messageAttributes"] = &types.AttributeValueMemberM{Value: attrs}
+	}
+	return &types.AttributeValueMemberM{Value: m}
+}
+
+func unmarshalFailure(m map[string]types.AttributeValue) *Failure {
+	str := func(name string) string {
+		if v, ok := m[name].(*types.AttributeValueMemberS); ok {
+			return v.Value
+		}
+		return ""
+	}
+
+	f := &Failure{
+		MessageID:       str("messageId"),
+		FirstReceivedAt: str("firstReceivedAt"),
+		SentAt:          str("sentAt"),
+		LastErrorCode:   str("lastErrorCode"),
+		LastError:       str("lastError"),
+	}
+	if v, ok := m["receiveCount"].(*types.AttributeValueMemberN); ok {
+		f.ReceiveCount, _ = strconv.Atoi(v.Value)
+	}
+	if attrs, ok := m["messageAttributes"].(*types.AttributeValueMemberM); ok {
+		f.MessageAttributes = make(map[string]string, len(attrs.Value))
+		for name, value := range attrs.Value {
+			if s, ok := value.(*types.AttributeValueMemberS); ok {
+				f.MessageAttributes[name] = s.Value
+			}
+		}
+	}
+	return f
+}
+
+func sortedKeys(m map[string]types.AttributeValue) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,49 @@
+package store
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+)
+
+// EncodingGzip is the contentEncoding of records whose binary payload
+// attributes (sqlContent, noSqlSchema, modelResponse) are gzip-compressed.
+// Records written before compression keep them as plain strings.
+const EncodingGzip = "gzip"
+
+// compressMinBytes is the size from which inline payloads are compressed;
+// smaller values stay readable strings in the console.
+const compressMinBytes = 1024
+
+// compress gzips value with the default level
+func compress(value string) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write([]byte(value)); err != nil {
+		return nil, fmt.Errorf("failed to compress payload: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress payload: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// decompress decodes a binary payload attribute stored with encoding.
+func decompress(encoding string, data []byte) (string, error) {
+	if encoding != EncodingGzip {
+		return "", fmt.Errorf("unsupported content encoding %q", encoding)
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("failed to decompress payload: %w", err)
+	}
+	defer zr.Close()
+
+	value, err := io.ReadAll(zr)
+	if err != nil {
+		return "", fmt.Errorf("failed to decompress payload: %w", err)
+	}
+	return string(value), nil
+}
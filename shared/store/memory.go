@@ -0,0 +1,247 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryStore is an in-memory ConversionStore for tests and the dev server.
+// Expired conversions are hidden, as DynamoDB TTL would delete them. When
+// opened with OpenFileStore every write is persisted to a JSON file.
+type MemoryStore struct {
+	mu          sync.RWMutex
+	conversions map[string]Conversion
+	path        string
+}
+
+// NewMemoryStore returns an empty in-memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{conversions: make(map[string]Conversion)}
+}
+
+// OpenFileStore returns a MemoryStore loaded from and persisted to path. The
+// file is created on the first write.
+func OpenFileStore(path string) (*MemoryStore, error) {
+	s := NewMemoryStore()
+	s.path = path
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read store file: %w", err)
+	}
+	if err := json.Unmarshal(data, &s.conversions); err != nil {
+		return nil, fmt.Errorf("failed to parse store file %s: %w", path, err)
+	}
+	return s, nil
+}
+
+// Create stores a new conversion.
+func (s *MemoryStore) Create(ctx context.Context, c *Conversion) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.conversions[c.ConversionID]; ok {
+		return ErrAlreadyExists
+	}
+	s.conversions[c.ConversionID] = *c
+	return s.persist()
+}
+
+// Get returns a copy of a conversion.
+func (s *MemoryStore) Get(ctx context.Context, conversionID string) (*Conversion, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	c, ok := s.conversions[conversionID]
+	if !ok || expired(c) {
+		return nil, ErrNotFound
+	}
+	return &c, nil
+}
+
+// List returns every live conversion, newest first.
+func (s *MemoryStore) List(ctx context.Context) ([]Conversion, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	conversions := make([]Conversion, 0, len(s.conversions))
+	for _, c := range s.conversions {
+		if !expired(c) {
+			conversions = append(conversions, c)
+		}
+	}
+	sort.Slice(conversions, func(i, j int) bool {
+		return conversions[i].CreatedAt > conversions[j].CreatedAt
+	})
+	return conversions, nil
+}
+
+// Transition moves a conversion to status if the current status allows it.
+func (s *MemoryStore) Transition(ctx context.Context, conversionID, status string) error {
+	return s.transition(conversionID, status, func(c *Conversion) {})
+}
+
+// Fail marks a conversion FAILED with its error code and message.
+func (s *MemoryStore) Fail(ctx context.Context, conversionID, errorCode, errorMessage string, failure *Failure) error {
+	return s.transition(conversionID, StatusFailed, func(c *Conversion) {
+		c.ErrorCode = errorCode
+		c.ErrorMessage = errorMessage
+		c.Failure = failure
+	})
+}
+
+// RecordError stores the last retryable error of a PROCESSING conversion.
+func (s *MemoryStore) RecordError(ctx context.Context, conversionID, errorCode, errorMessage string) error {
+	return s.updateProcessing(conversionID, func(c *Conversion) {
+		c.LastErrorCode = errorCode
+		c.LastError = errorMessage
+	})
+}
+
+// SaveModelResponse caches the model response of a PROCESSING conversion.
+func (s *MemoryStore) SaveModelResponse(ctx context.Context, conversionID, response string, model ModelInfo) error {
+	return s.updateProcessing(conversionID, func(c *Conversion) {
+		c.ModelResponse = response
+		c.PromptVersion, c.ModelID = model.PromptVersion, model.ModelID
+	})
+}
+
+// updateProcessing applies an update that leaves the status and version
+// unchanged, if the conversion is PROCESSING
+func (s *MemoryStore) updateProcessing(conversionID string, apply func(*Conversion)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.conversions[conversionID]
+	if !ok {
+		return ErrNotFound
+	}
+	if c.Status != StatusProcessing {
+		return &TransitionError{ConversionID: conversionID, From: c.Status, To: StatusProcessing}
+	}
+
+	apply(&c)
+	s.conversions[conversionID] = c
+	return s.persist()
+}
+
+// SaveResult stores the result and marks the conversion COMPLETED.
+func (s *MemoryStore) SaveResult(ctx context.Context, conversionID, noSqlSchema string) error {
+	return s.transition(conversionID, StatusCompleted, func(c *Conversion) {
+		c.NoSQLSchema = noSqlSchema
+	})
+}
+
+// Requeue resets a conversion to PENDING for a redrive.
+func (s *MemoryStore) Requeue(ctx context.Context, conversionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.conversions[conversionID]
+	if !ok {
+		return ErrNotFound
+	}
+	if !CanRequeue(c.Status) {
+		return &TransitionError{ConversionID: conversionID, From: c.Status, To: StatusPending}
+	}
+
+	c.Status = StatusPending
+	c.Version++
+	c.RedrivenAt = time.Now().UTC().Format(time.RFC3339)
+	c.ErrorCode, c.ErrorMessage, c.Failure = "", "", nil
+	c.LastErrorCode, c.LastError = "", ""
+	c.ModelResponse, c.FailedAt = "", ""
+	c.PromptVersion, c.ModelID = "", ""
+
+	s.conversions[conversionID] = c
+	return s.persist()
+}
+
+// FindCompleted returns the newest live COMPLETED conversion with contentHash.
+func (s *MemoryStore) FindCompleted(ctx context.Context, contentHash string) (*Conversion, error) {
+	if contentHash == "" {
+		return nil, ErrNotFound
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var found *Conversion
+	for _, c := range s.conversions {
+		if c.ContentHash != contentHash || c.Status != StatusCompleted || expired(c) {
+			continue
+		}
+		if found == nil || c.CreatedAt > found.CreatedAt {
+			c := c
+			found = &c
+		}
+	}
+	if found == nil {
+		return nil, ErrNotFound
+	}
+	return found, nil
+}
+
+func (s *MemoryStore) transition(conversionID, status string, apply func(*Conversion)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.conversions[conversionID]
+	if !ok {
+		return ErrNotFound
+	}
+	if !CanTransition(c.Status, status) {
+		return &TransitionError{ConversionID: conversionID, From: c.Status, To: status}
+	}
+
+	c.Status = status
+	c.Version++
+	if status == StatusProcessing {
+		c.Attempts++
+	}
+	setTimestamp(&c, status, time.Now().UTC().Format(time.RFC3339))
+	apply(&c)
+
+	s.conversions[conversionID] = c
+	return s.persist()
+}
+
+// persist writes the store through a temp file, so a crash never leaves a
+// truncated file. Callers hold the lock.
+func (s *MemoryStore) persist() error {
+	if s.path == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(s.conversions, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal store: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".store-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to persist store: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to persist store: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to persist store: %w", err)
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
+
+func expired(c Conversion) bool {
+	return c.ExpiresAt != 0 && c.ExpiresAt < time.Now().Unix()
+}
@@ -0,0 +1,181 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// DefaultPayloadThreshold is the size above which SQL content, results and
+// model responses are offloaded. Several payloads share one DynamoDB item
+// (400 KB) and the SQL also travels in the SQS message (256 KB).
+const DefaultPayloadThreshold = 64 * 1024
+
+// PayloadStore keeps payloads too large to store inline (claim check): the
+// record and the queue message carry the reference Put returns.
+type PayloadStore interface {
+	// Put stores data under key and returns its reference.
+	Put(ctx context.Context, key string, data []byte) (string, error)
+	// Get returns the payload of a reference returned by Put.
+	Get(ctx context.Context, ref string) ([]byte, error)
+	// Copy stores the payload of ref under key, without downloading it, and
+	// returns the new reference.
+	Copy(ctx context.Context, ref, key string) (string, error)
+}
+
+// payloadKey is the object key of a conversion payload
+func payloadKey(conversionID, name string) string {
+	return fmt.Sprintf("conversions/%s/%s", conversionID, name)
+}
+
+// S3PayloadStore is the PayloadStore backed by an S3 bucket. References are
+// s3://bucket/key URIs.
+type S3PayloadStore struct {
+	client *s3.Client
+	bucket string
+}
+
+// NewS3PayloadStore returns a payload store over an existing client and bucket.
+func NewS3PayloadStore(client *s3.Client, bucket string) *S3PayloadStore {
+	return &S3PayloadStore{client: client, bucket: bucket}
+}
+
+// NewS3PayloadStoreFromEnv builds the payload store from PAYLOAD_BUCKET and
+// S3_ENDPOINT (LocalStack, with path-style addressing).
+func NewS3PayloadStoreFromEnv(ctx context.Context) (*S3PayloadStore, error) {
+	bucket := os.Getenv("PAYLOAD_BUCKET")
+	if bucket == "" {
+		return nil, fmt.Errorf("PAYLOAD_BUCKET not set")
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	var client *s3.Client
+	if endpoint := os.Getenv("S3_ENDPOINT"); endpoint != "" {
+		client = s3.NewFromConfig(cfg, func(o *s3.Options) {
+			o.BaseEndpoint = aws.String(endpoint)
+			o.UsePathStyle = true
+		})
+	} else {
+		client = s3.NewFromConfig(cfg)
+	}
+
+	return NewS3PayloadStore(client, bucket), nil
+}
+
+// Put uploads data to the bucket.
+func (s *S3PayloadStore) Put(ctx context.Context, key string, data []byte) (string, error) {
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+		Body:   bytes.NewReader(data),
+	})
+	if err != nil {
+		return "", fmt.Errorf("S3 PutObject failed: %w", err)
+	}
+	return fmt.Sprintf("s3://%s/%s", s.bucket, key), nil
+}
+
+// Get downloads the object of an s3:// reference.
+func (s *S3PayloadStore) Get(ctx context.Context, ref string) ([]byte, error) {
+	bucket, key, err := parseS3Ref(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("S3 GetObject failed: %w", err)
+	}
+	defer out.Body.Close()
+	return io.ReadAll(out.Body)
+}
+
+// Copy copies the object of an s3:// reference to key in the bucket. The
+// copy is a new object, so the bucket lifecycle expires it with the record
+// that owns it.
+func (s *S3PayloadStore) Copy(ctx context.Context, ref, key string) (string, error) {
+	bucket, sourceKey, err := parseS3Ref(ref)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = s.client.CopyObject(ctx, &s3.CopyObjectInput{
+		Bucket:     aws.String(s.bucket),
+		Key:        aws.String(key),
+		CopySource: aws.String(bucket + "/" + sourceKey),
+	})
+	if err != nil {
+		return "", fmt.Errorf("S3 CopyObject failed: %w", err)
+	}
+	return fmt.Sprintf("s3://%s/%s", s.bucket, key), nil
+}
+
+// parseS3Ref splits an s3://bucket/key reference
+func parseS3Ref(ref string) (bucket, key string, err error) {
+	bucket, key, ok := strings.Cut(strings.TrimPrefix(ref, "s3://"), "/")
+	if !strings.HasPrefix(ref, "s3://") || !ok {
+		return "", "", fmt.Errorf("invalid payload reference %q", ref)
+	}
+	return bucket, key, nil
+}
+
+// MemoryPayloadStore is an in-memory PayloadStore for tests.
+type MemoryPayloadStore struct {
+	mu      sync.RWMutex
+	objects map[string][]byte
+}
+
+// NewMemoryPayloadStore returns an empty in-memory payload store.
+func NewMemoryPayloadStore() *MemoryPayloadStore {
+	return &MemoryPayloadStore{objects: make(map[string][]byte)}
+}
+
+// Put stores a copy of data; the reference is mem://key.
+func (s *MemoryPayloadStore) Put(ctx context.Context, key string, data []byte) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ref := "mem://" + key
+	s.objects[ref] = append([]byte(nil), data...)
+	return ref, nil
+}
+
+// Get returns a stored payload.
+func (s *MemoryPayloadStore) Get(ctx context.Context, ref string) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	data, ok := s.objects[ref]
+	if !ok {
+		return nil, errors.New("payload not found: " + ref)
+	}
+	return data, nil
+}
+
+// Copy stores the payload of ref under key.
+func (s *MemoryPayloadStore) Copy(ctx context.Context, ref, key string) (string, error) {
+	data, err := s.Get(ctx, ref)
+	if err != nil {
+		return "", err
+	}
+	return s.Put(ctx, key, data)
+}
+
+var (
+	_ PayloadStore = (*S3PayloadStore)(nil)
+	_ PayloadStore = (*MemoryPayloadStore)(nil)
+)
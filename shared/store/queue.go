@@ -0,0 +1,34 @@
+package store
+
+import "encoding/json"
+
+// QueueMessage is the body of a conversion queue message: sent by the process
+// handler and the redrive, read by the conversion worker and the DLQ handler.
+type QueueMessage struct {
+	ConversionID     string          `json:"conversionId"`
+	SQLContent       string          `json:"sqlContent,omitempty"`
+	SQLContentRef    string          `json:"sqlContentRef,omitempty"`
+	OptimizationType string          `json:"optimizationType"`
+	OutputLanguage   string          `json:"outputLanguage,omitempty"`
+	TablesExtracted  int             `json:"tablesExtracted"`
+	DesignHints      json.RawMessage `json:"designHints,omitempty"`
+}
+
+// NewQueueMessage builds the queue message of a conversion. SQL content
+// offloaded to S3 travels as its reference, keeping the message under the
+// SQS size limit; the worker resolves it through the store.
+func NewQueueMessage(c *Conversion) QueueMessage {
+	msg := QueueMessage{
+		ConversionID:     c.ConversionID,
+		SQLContent:       c.SQLContent,
+		OptimizationType: c.OptimizationType,
+		OutputLanguage:   c.OutputLanguage,
+		TablesExtracted:  c.TablesExtracted,
+		DesignHints:      c.DesignHints,
+	}
+	if c.SQLContentRef != "" {
+		msg.SQLContent = ""
+		msg.SQLContentRef = c.SQLContentRef
+	}
+	return msg
+}
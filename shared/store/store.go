@@ -0,0 +1,257 @@
+// Package store is the conversions table shared by the Lambdas, the dev
+// server and the CLI. ConversionStore is implemented by DynamoStore for the
+// deployed stack and by MemoryStore for tests and local runs, so the record
+// layout lives in one place.
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Conversion statuses
+const (
+	StatusPending    = "PENDING"
+	StatusProcessing = "PROCESSING"
+	StatusCompleted  = "COMPLETED"
+	StatusFailed     = "FAILED"
+	StatusCancelled  = "CANCELLED"
+)
+
+// transitions lists the statuses each status can move to. PROCESSING can be
+// re-entered so a message redelivered after a worker timeout is retried;
+// COMPLETED, FAILED and CANCELLED are terminal.
+var transitions = map[string][]string{
+	StatusPending:    {StatusProcessing, StatusFailed, StatusCancelled},
+	StatusProcessing: {StatusProcessing, StatusCompleted, StatusFailed, StatusCancelled},
+}
+
+// transitionTimestamps is the attribute recording when a status was entered
+var transitionTimestamps = map[string]string{
+	StatusProcessing: "startedAt",
+	StatusCompleted:  "completedAt",
+	StatusFailed:     "failedAt",
+	StatusCancelled:  "cancelledAt",
+}
+
+// CanTransition reports whether a conversion in status from can move to to.
+func CanTransition(from, to string) bool {
+	for _, next := range transitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTerminal reports whether no transition leaves status.
+func IsTerminal(status string) bool {
+	return len(transitions[status]) == 0
+}
+
+// sourceStatuses returns the statuses a conversion can move to status from
+func sourceStatuses(status string) []string {
+	var sources []string
+	for _, from := range []string{StatusPending, StatusProcessing} {
+		if CanTransition(from, status) {
+			sources = append(sources, from)
+		}
+	}
+	return sources
+}
+
+// requeueStatuses are the statuses Requeue accepts
+var requeueStatuses = []string{StatusPending, StatusProcessing, StatusFailed}
+
+// CanRequeue reports whether a conversion in status can be redriven.
+func CanRequeue(status string) bool {
+	for _, s := range requeueStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// Conversions are public and ephemeral: DynamoDB TTL removes them after a day
+const recordTTL = 24 * time.Hour
+
+var (
+	// ErrNotFound is returned when a conversion does not exist or has expired.
+	ErrNotFound = errors.New("conversion not found")
+	// ErrAlreadyExists is returned by Create when the ID is already stored.
+	ErrAlreadyExists = errors.New("conversion already exists")
+	// ErrInvalidTransition is returned when the current status does not allow
+	// the requested one, e.g. a redelivered message on a COMPLETED conversion.
+	ErrInvalidTransition = errors.New("invalid status transition")
+)
+
+// TransitionError describes a rejected transition. It matches
+// ErrInvalidTransition with errors.Is.
+type TransitionError struct {
+	ConversionID string
+	From         string
+	To           string
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("conversion %s: cannot transition from %s to %s", e.ConversionID, e.From, e.To)
+}
+
+func (e *TransitionError) Is(target error) bool {
+	return target == ErrInvalidTransition
+}
+
+// Conversion is a record of the conversions table.
+type Conversion struct {
+	ConversionID     string          `json:"conversionId"`
+	Status           string          `json:"status"`
+	CreatedAt        string          `json:"createdAt"`
+	ExpiresAt        int64           `json:"expiresAt"`
+	ConversionDate   string          `json:"conversionDate"`
+	SQLContent       string          `json:"sqlContent"`
+	OptimizationType string          `json:"optimizationType"`
+	TablesExtracted  int             `json:"tablesExtracted"`
+	DesignHints      json.RawMessage `json:"designHints,omitempty"`
+	NoSQLSchema      string          `json:"noSqlSchema,omitempty"`
+	ErrorCode        string          `json:"errorCode,omitempty"`
+	ErrorMessage     string          `json:"errorMessage,omitempty"`
+	LastErrorCode    string          `json:"lastErrorCode,omitempty"`
+	LastError        string          `json:"lastError,omitempty"`
+	Failure          *Failure        `json:"failure,omitempty"`
+	Version          int64           `json:"version"`
+	Attempts         int             `json:"attempts"`
+	ModelResponse    string          `json:"modelResponse,omitempty"`
+	StartedAt        string          `json:"startedAt,omitempty"`
+	CompletedAt      string          `json:"completedAt,omitempty"`
+	FailedAt         string          `json:"failedAt,omitempty"`
+	CancelledAt      string          `json:"cancelledAt,omitempty"`
+	RedrivenAt       string          `json:"redrivenAt,omitempty"`
+	ContentHash      string          `json:"contentHash,omitempty"`
+	CachedFrom       string          `json:"cachedFrom,omitempty"`
+	OutputLanguage   string          `json:"outputLanguage,omitempty"`
+	PromptVersion    string          `json:"promptVersion,omitempty"`
+	ModelID          string          `json:"modelId,omitempty"`
+
+	// References to payloads offloaded by DynamoStore (claim check); Get
+	// resolves them into the fields above, List does not.
+	SQLContentRef    string `json:"-"`
+	NoSQLSchemaRef   string `json:"-"`
+	ModelResponseRef string `json:"-"`
+}
+
+// Failure is the delivery context of a conversion that exhausted its SQS
+// retries, recorded by the DLQ handler when it marks the conversion FAILED.
+type Failure struct {
+	MessageID         string            `json:"messageId,omitempty"`
+	ReceiveCount      int               `json:"receiveCount"`
+	FirstReceivedAt   string            `json:"firstReceivedAt,omitempty"`
+	SentAt            string            `json:"sentAt,omitempty"`
+	LastErrorCode     string            `json:"lastErrorCode,omitempty"`
+	LastError         string            `json:"lastError,omitempty"`
+	MessageAttributes map[string]string `json:"messageAttributes,omitempty"`
+}
+
+// ConversionStore persists conversions through their lifecycle:
+// PENDING → PROCESSING → COMPLETED or FAILED, or CANCELLED before completing.
+// Every transition increments Version and records its timestamp; entering
+// PROCESSING also increments Attempts.
+type ConversionStore interface {
+	// Create stores a new conversion.
+	Create(ctx context.Context, c *Conversion) error
+	// Get returns a conversion, or ErrNotFound.
+	Get(ctx context.Context, conversionID string) (*Conversion, error)
+	// List returns every stored conversion. Offloaded payloads are returned
+	// as their references (SQLContentRef, NoSQLSchemaRef, ModelResponseRef).
+	List(ctx context.Context) ([]Conversion, error)
+	// Transition sets the status of a conversion. It returns a
+	// *TransitionError when the current status does not allow it.
+	Transition(ctx context.Context, conversionID, status string) error
+	// Fail marks a conversion FAILED with a machine-readable error code, a
+	// message and optionally the delivery context, with the same rules as
+	// Transition.
+	Fail(ctx context.Context, conversionID, errorCode, errorMessage string, failure *Failure) error
+	// RecordError stores the last retryable error of a PROCESSING
+	// conversion, so the DLQ handler can report it if retries run out.
+	RecordError(ctx context.Context, conversionID, errorCode, errorMessage string) error
+	// SaveModelResponse caches the raw model response of a PROCESSING
+	// conversion, so a retry after a crash does not invoke the model again,
+	// and records the prompt version and model that produced it.
+	SaveModelResponse(ctx context.Context, conversionID, response string, model ModelInfo) error
+	// SaveResult stores the converted schema and marks the conversion
+	// COMPLETED, with the same rules as Transition.
+	SaveResult(ctx context.Context, conversionID, noSqlSchema string) error
+	// Requeue resets a PENDING, PROCESSING or FAILED conversion to PENDING
+	// and clears its error, cached response and model info, so a redriven message runs
+	// it from scratch. It is the only way out of FAILED; COMPLETED and
+	// CANCELLED conversions return a *TransitionError.
+	Requeue(ctx context.Context, conversionID string) error
+	// FindCompleted returns the newest live COMPLETED conversion with
+	// contentHash, or ErrNotFound.
+	FindCompleted(ctx context.Context, contentHash string) (*Conversion, error)
+}
+
+// NewConversion builds a PENDING conversion with a new ID and its expiration.
+// designHints is the JSON array of hints found by the validator, or nil.
+func NewConversion(sqlContent, optimizationType string, tablesExtracted int, designHints json.RawMessage) *Conversion {
+	now := time.Now().UTC()
+	return &Conversion{
+		ConversionID:     uuid.New().String(),
+		Status:           StatusPending,
+		CreatedAt:        now.Format(time.RFC3339),
+		ExpiresAt:        now.Add(recordTTL).Unix(),
+		ConversionDate:   now.Format("2006-01-02"),
+		SQLContent:       sqlContent,
+		OptimizationType: optimizationType,
+		TablesExtracted:  tablesExtracted,
+		DesignHints:      designHints,
+		Version:          1,
+	}
+}
+
+// ReuseResult completes a new conversion with the result of an equivalent
+// COMPLETED one (result cache), linking it through CachedFrom. A result
+// offloaded to S3 keeps the reference of the source; DynamoStore.Create
+// copies that object under the new conversion, since the bucket expires
+// objects by age and the source's may go before the new record does.
+func (c *Conversion) ReuseResult(source *Conversion) {
+	c.Status = StatusCompleted
+	c.NoSQLSchema = source.NoSQLSchema
+	c.NoSQLSchemaRef = source.NoSQLSchemaRef
+	c.CachedFrom = source.ConversionID
+	c.PromptVersion = source.PromptVersion
+	c.ModelID = source.ModelID
+	c.CompletedAt = time.Now().UTC().Format(time.RFC3339)
+}
+
+// ModelInfo identifies what produced a model response: the prompt template
+// version and the model, so a result can be reproduced and compared across
+// prompt revisions.
+type ModelInfo struct {
+	PromptVersion string
+	ModelID       string
+}
+
+var (
+	_ ConversionStore = (*DynamoStore)(nil)
+	_ ConversionStore = (*MemoryStore)(nil)
+)
+
+// setTimestamp records when a conversion entered status
+func setTimestamp(c *Conversion, status, at string) {
+	switch status {
+	case StatusProcessing:
+		c.StartedAt = at
+	case StatusCompleted:
+		c.CompletedAt = at
+	case StatusFailed:
+		c.FailedAt = at
+	case StatusCancelled:
+		c.CancelledAt = at
+	}
+}
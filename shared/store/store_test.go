@@ -0,0 +1,572 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+func TestMemoryStore_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore()
+
+	c := NewConversion("CREATE TABLE t (id INT);", "balanced", 1, []byte(`[{"type":"AUTO_INCREMENT"}]`))
+	if err := s.Create(ctx, c); err != nil {
+		t.Fatal(err)
+	}
+
+	steps := []struct {
+		name       string
+		apply      func() error
+		wantStatus string
+	}{
+		{"procesando", func() error { return s.Transition(ctx, c.ConversionID, StatusProcessing) }, StatusProcessing},
+		{"completado", func() error { return s.SaveResult(ctx, c.ConversionID, `{"tables":[]}`) }, StatusCompleted},
+	}
+
+	for _, step := range steps {
+		if err := step.apply(); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		got, err := s.Get(ctx, c.ConversionID)
+		if err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		if got.Status != step.wantStatus {
+			t.Errorf("%s: status = %s, want %s", step.name, got.Status, step.wantStatus)
+		}
+	}
+
+	got, _ := s.Get(ctx, c.ConversionID)
+	if got.NoSQLSchema != `{"tables":[]}` {
+		t.Errorf("noSqlSchema = %q", got.NoSQLSchema)
+	}
+}
+
+func TestMemoryStore_NotFoundAndExpired(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore()
+
+	if _, err := s.Get(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	if err := s.Fail(ctx, "missing", "MODEL_ERROR", "boom", nil); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+
+	// Los registros vencidos se ocultan, como con el TTL de DynamoDB
+	old := NewConversion("CREATE TABLE t (id INT);", "balanced", 1, nil)
+	old.ExpiresAt = time.Now().Add(-time.Hour).Unix()
+	s.Create(ctx, old)
+
+	if _, err := s.Get(ctx, old.ConversionID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expired conversion should not be returned, got %v", err)
+	}
+	if list, _ := s.List(ctx); len(list) != 0 {
+		t.Errorf("expected empty list, got %d", len(list))
+	}
+}
+
+func TestOpenFileStore_Persists(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "store.json")
+
+	s, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewConversion("CREATE TABLE t (id INT);", "read_heavy", 1, nil)
+	s.Create(ctx, c)
+	s.Fail(ctx, c.ConversionID, "MAX_RETRIES_EXCEEDED", "Max retries exceeded", nil)
+
+	reloaded, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := reloaded.Get(ctx, c.ConversionID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != StatusFailed || got.ErrorCode != "MAX_RETRIES_EXCEEDED" || got.ErrorMessage != "Max retries exceeded" {
+		t.Errorf("unexpected persisted conversion: %+v", got)
+	}
+}
+
+func TestMarshalConversion_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		c    Conversion
+	}{
+		{"pendiente con hints", *NewConversion("CREATE TABLE t (id INT);", "balanced", 1, []byte(`[{"type":"TIME_SERIES"}]`))},
+		{"completado", Conversion{ConversionID: "a", Status: StatusCompleted, ExpiresAt: 1, TablesExtracted: 3, NoSQLSchema: `{"tables":[]}`}},
+		{"fallido", Conversion{ConversionID: "b", Status: StatusFailed, ErrorCode: "MODEL_THROTTLED", ErrorMessage: "throttled", Version: 3, Attempts: 2, ModelResponse: "{}", StartedAt: "2026-01-01T00:00:00Z", FailedAt: "2026-01-01T00:01:00Z"}},
+		{"fallido en la DLQ", Conversion{ConversionID: "c", Status: StatusFailed, ErrorCode: "MAX_RETRIES_EXCEEDED", LastErrorCode: "MODEL_TIMEOUT", LastError: "timeout", Failure: &Failure{
+			MessageID: "m-1", ReceiveCount: 4, FirstReceivedAt: "2026-01-01T00:00:00Z", SentAt: "2026-01-01T00:00:00Z",
+			LastErrorCode: "MODEL_TIMEOUT", LastError: "timeout", MessageAttributes: map[string]string{"source": "api"},
+		}}},
+		{"resultado en cache", Conversion{ConversionID: "e", Status: StatusCompleted, ContentHash: "abc123", CachedFrom: "a", NoSQLSchema: `{"tables":[]}`, CompletedAt: "2026-01-01T00:00:00Z"}},
+		{"prompt y modelo", Conversion{ConversionID: "f", Status: StatusCompleted, OutputLanguage: "en", PromptVersion: "v2", ModelID: "anthropic.claude", NoSQLSchema: `{"tables":[]}`}},
+		{"con payloads en S3", Conversion{ConversionID: "d", Status: StatusCompleted, SQLContentRef: "s3://b/conversions/d/sqlContent", NoSQLSchemaRef: "s3://b/conversions/d/noSqlSchema", ModelResponseRef: "s3://b/conversions/d/modelResponse"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := unmarshalConversion(marshalConversion(&tt.c))
+			if !reflect.DeepEqual(got, tt.c) {
+				t.Errorf("round trip mismatch:\n got %+v\nwant %+v", got, tt.c)
+			}
+		})
+	}
+}
+
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to string
+		want     bool
+	}{
+		{"pendiente a procesando", StatusPending, StatusProcessing, true},
+		{"pendiente a cancelado", StatusPending, StatusCancelled, true},
+		{"pendiente a completado", StatusPending, StatusCompleted, false},
+		{"reintento de procesando", StatusProcessing, StatusProcessing, true},
+		{"procesando a completado", StatusProcessing, StatusCompleted, true},
+		{"procesando a fallido", StatusProcessing, StatusFailed, true},
+		{"completado a procesando (mensaje redelivered)", StatusCompleted, StatusProcessing, false},
+		{"completado a fallido (DLQ tardía)", StatusCompleted, StatusFailed, false},
+		{"cancelado a procesando", StatusCancelled, StatusProcessing, false},
+		{"fallido a completado", StatusFailed, StatusCompleted, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CanTransition(tt.from, tt.to); got != tt.want {
+				t.Errorf("CanTransition(%s, %s) = %v, want %v", tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMemoryStore_RejectsInvalidTransitions(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore()
+
+	c := NewConversion("CREATE TABLE t (id INT);", "balanced", 1, nil)
+	s.Create(ctx, c)
+	s.Transition(ctx, c.ConversionID, StatusProcessing)
+	s.SaveResult(ctx, c.ConversionID, `{"tables":[]}`)
+
+	err := s.Fail(ctx, c.ConversionID, "MAX_RETRIES_EXCEEDED", "Max retries exceeded", nil)
+	if !errors.Is(err, ErrInvalidTransition) {
+		t.Fatalf("expected ErrInvalidTransition, got %v", err)
+	}
+	var transitionErr *TransitionError
+	if !errors.As(err, &transitionErr) || transitionErr.From != StatusCompleted {
+		t.Errorf("expected transition error from COMPLETED, got %v", err)
+	}
+
+	got, _ := s.Get(ctx, c.ConversionID)
+	if got.Status != StatusCompleted || got.ErrorMessage != "" {
+		t.Errorf("completed conversion was modified: %+v", got)
+	}
+	if got.Version != 3 {
+		t.Errorf("version = %d, want 3", got.Version)
+	}
+	if got.StartedAt == "" || got.CompletedAt == "" || got.FailedAt != "" {
+		t.Errorf("unexpected timestamps: started=%q completed=%q failed=%q", got.StartedAt, got.CompletedAt, got.FailedAt)
+	}
+
+	if err := s.Create(ctx, c); !errors.Is(err, ErrAlreadyExists) {
+		t.Errorf("expected ErrAlreadyExists, got %v", err)
+	}
+}
+
+func TestMemoryStore_AttemptsAndModelResponse(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore()
+
+	c := NewConversion("CREATE TABLE t (id INT);", "balanced", 1, nil)
+	s.Create(ctx, c)
+
+	if err := s.SaveModelResponse(ctx, c.ConversionID, "{}", ModelInfo{}); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("caching a response of a PENDING conversion should fail, got %v", err)
+	}
+
+	// Dos entregas del mismo mensaje: el segundo intento reutiliza la respuesta
+	s.Transition(ctx, c.ConversionID, StatusProcessing)
+	model := ModelInfo{PromptVersion: "v2", ModelID: "anthropic.claude"}
+	if err := s.SaveModelResponse(ctx, c.ConversionID, `{"tables":[]}`, model); err != nil {
+		t.Fatal(err)
+	}
+	s.Transition(ctx, c.ConversionID, StatusProcessing)
+
+	got, _ := s.Get(ctx, c.ConversionID)
+	if got.Attempts != 2 {
+		t.Errorf("attempts = %d, want 2", got.Attempts)
+	}
+	if got.ModelResponse != `{"tables":[]}` {
+		t.Errorf("modelResponse = %q", got.ModelResponse)
+	}
+	if got.PromptVersion != "v2" || got.ModelID != "anthropic.claude" {
+		t.Errorf("model info = %s %s", got.PromptVersion, got.ModelID)
+	}
+
+	// Un redrive vuelve a generar la respuesta: se descarta junto con su origen
+	if err := s.Requeue(ctx, c.ConversionID); err != nil {
+		t.Fatal(err)
+	}
+	got, _ = s.Get(ctx, c.ConversionID)
+	if got.ModelResponse != "" || got.PromptVersion != "" || got.ModelID != "" {
+		t.Errorf("requeue kept the model response: %+v", got)
+	}
+}
+
+func TestMemoryStore_RecordErrorAndFailure(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore()
+
+	c := NewConversion("CREATE TABLE t (id INT);", "balanced", 1, nil)
+	s.Create(ctx, c)
+
+	if err := s.RecordError(ctx, c.ConversionID, "MODEL_THROTTLED", "throttled"); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("recording an error of a PENDING conversion should fail, got %v", err)
+	}
+
+	s.Transition(ctx, c.ConversionID, StatusProcessing)
+	if err := s.RecordError(ctx, c.ConversionID, "MODEL_THROTTLED", "throttled"); err != nil {
+		t.Fatal(err)
+	}
+
+	failure := &Failure{MessageID: "m-1", ReceiveCount: 3, LastErrorCode: "MODEL_THROTTLED", LastError: "throttled"}
+	if err := s.Fail(ctx, c.ConversionID, "MAX_RETRIES_EXCEEDED", "Max retries exceeded", failure); err != nil {
+		t.Fatal(err)
+	}
+
+	got, _ := s.Get(ctx, c.ConversionID)
+	if got.Status != StatusFailed || got.LastErrorCode != "MODEL_THROTTLED" {
+		t.Errorf("unexpected conversion: %+v", got)
+	}
+	if !reflect.DeepEqual(got.Failure, failure) {
+		t.Errorf("failure = %+v, want %+v", got.Failure, failure)
+	}
+}
+
+func TestMemoryStore_FindCompleted(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore()
+
+	newWithHash := func(hash, createdAt string) *Conversion {
+		c := NewConversion("CREATE TABLE t (id INT);", "balanced", 1, nil)
+		c.ContentHash, c.CreatedAt = hash, createdAt
+		s.Create(ctx, c)
+		return c
+	}
+	complete := func(c *Conversion) {
+		s.Transition(ctx, c.ConversionID, StatusProcessing)
+		s.SaveModelResponse(ctx, c.ConversionID, `{"tables":[]}`, ModelInfo{PromptVersion: "v2", ModelID: "m"})
+		s.SaveResult(ctx, c.ConversionID, `{"tables":[]}`)
+	}
+
+	older := newWithHash("h1", "2026-01-01T00:00:00Z")
+	complete(older)
+	newer := newWithHash("h1", "2026-01-01T00:05:00Z")
+	complete(newer)
+	newWithHash("h1", "2026-01-01T00:10:00Z") // pendiente: no es un resultado
+	failed := newWithHash("h2", "2026-01-01T00:00:00Z")
+	s.Transition(ctx, failed.ConversionID, StatusFailed)
+
+	tests := []struct {
+		name    string
+		hash    string
+		wantID  string
+		wantErr error
+	}{
+		{"el completado más reciente", "h1", newer.ConversionID, nil},
+		{"fallido no es un resultado", "h2", "", ErrNotFound},
+		{"hash desconocido", "h3", "", ErrNotFound},
+		{"sin hash", "", "", ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.FindCompleted(ctx, tt.hash)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("FindCompleted() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && got.ConversionID != tt.wantID {
+				t.Errorf("FindCompleted() = %s, want %s", got.ConversionID, tt.wantID)
+			}
+		})
+	}
+
+	// Un nuevo registro enlazado al resultado queda COMPLETED de inmediato
+	source, _ := s.FindCompleted(ctx, "h1")
+	c := NewConversion("CREATE TABLE t (id INT);", "balanced", 1, nil)
+	c.ReuseResult(source)
+	if c.Status != StatusCompleted || c.CachedFrom != newer.ConversionID || c.NoSQLSchema != `{"tables":[]}` || c.PromptVersion != "v2" || c.ModelID != "m" {
+		t.Errorf("unexpected cached conversion: %+v", c)
+	}
+}
+
+func TestMemoryStore_Requeue(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore()
+
+	tests := []struct {
+		name    string
+		setup   func(id string)
+		wantErr error
+	}{
+		{"fallido vuelve a PENDING", func(id string) {
+			s.Transition(ctx, id, StatusProcessing)
+			s.Fail(ctx, id, "MAX_RETRIES_EXCEEDED", "Max retries exceeded", &Failure{ReceiveCount: 3})
+		}, nil},
+		{"procesando vuelve a PENDING", func(id string) { s.Transition(ctx, id, StatusProcessing) }, nil},
+		{"completado no se reencola", func(id string) {
+			s.Transition(ctx, id, StatusProcessing)
+			s.SaveResult(ctx, id, "{}")
+		}, ErrInvalidTransition},
+		{"cancelado no se reencola", func(id string) { s.Transition(ctx, id, StatusCancelled) }, ErrInvalidTransition},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewConversion("CREATE TABLE t (id INT);", "balanced", 1, nil)
+			s.Create(ctx, c)
+			tt.setup(c.ConversionID)
+
+			err := s.Requeue(ctx, c.ConversionID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Requeue() = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			got, _ := s.Get(ctx, c.ConversionID)
+			if got.Status != StatusPending || got.RedrivenAt == "" {
+				t.Errorf("unexpected conversion: %+v", got)
+			}
+			if got.ErrorCode != "" || got.Failure != nil || got.FailedAt != "" {
+				t.Errorf("error attributes were not cleared: %+v", got)
+			}
+		})
+	}
+}
+
+func TestDynamoStore_OffloadAndResolve(t *testing.T) {
+	ctx := context.Background()
+	payloads := NewMemoryPayloadStore()
+	s := NewDynamoStore(nil, "schemas").WithPayloads(payloads, 16)
+
+	tests := []struct {
+		name    string
+		value   string
+		wantRef bool
+	}{
+		{"bajo el umbral queda en el item", "CREATE TABLE t;", false},
+		{"sobre el umbral va al payload store", strings.Repeat("x", 17), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attrs, ref, err := s.offload(ctx, "conv-1", "sqlContent", tt.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if (ref != "") != tt.wantRef {
+				t.Fatalf("ref = %q, wantRef %v", ref, tt.wantRef)
+			}
+
+			c := unmarshalConversion(attrs)
+			if err := s.resolve(ctx, &c); err != nil {
+				t.Fatal(err)
+			}
+			if c.SQLContent != tt.value {
+				t.Errorf("resolved sqlContent = %q, want %q", c.SQLContent, tt.value)
+			}
+		})
+	}
+
+	missing := Conversion{ConversionID: "conv-2", NoSQLSchemaRef: "mem://missing"}
+	if err := s.resolve(ctx, &missing); err == nil {
+		t.Error("expected an error for a missing payload")
+	}
+}
+
+// countingPayloads counts the payloads read
+type countingPayloads struct {
+	PayloadStore
+	gets int
+}
+
+func (p *countingPayloads) Get(ctx context.Context, ref string) ([]byte, error) {
+	p.gets++
+	return p.PayloadStore.Get(ctx, ref)
+}
+
+func TestDynamoStore_ListLeavesPayloadsUnresolved(t *testing.T) {
+	ctx := context.Background()
+	payloads := &countingPayloads{PayloadStore: NewMemoryPayloadStore()}
+	ref, _ := payloads.Put(ctx, payloadKey("conv-1", "noSqlSchema"), []byte(`{"tables":[]}`))
+
+	item := `{"conversionId": {"S": "conv-1"}, "status": {"S": "COMPLETED"}, "noSqlSchemaRef": {"S": "` + ref + `"}}`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		switch r.Header.Get("X-Amz-Target") {
+		case "DynamoDB_20120810.Scan":
+			w.Write([]byte(`{"Items": [` + item + `], "Count": 1}`))
+		case "DynamoDB_20120810.GetItem":
+			w.Write([]byte(`{"Item": ` + item + `}`))
+		default:
+			http.Error(w, "unexpected operation", http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	cfg := aws.Config{Region: "us-east-1", Credentials: credentials.NewStaticCredentialsProvider("test", "test", ""), HTTPClient: srv.Client()}
+	client := dynamodb.NewFromConfig(cfg, func(o *dynamodb.Options) {
+		o.BaseEndpoint = aws.String(srv.URL)
+	})
+	s := NewDynamoStore(client, "schemas").WithPayloads(payloads, 16)
+
+	// El listado no descarga el resultado de cada registro
+	listed, err := s.List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(listed) != 1 || listed[0].NoSQLSchemaRef != ref || listed[0].NoSQLSchema != "" {
+		t.Fatalf("unexpected list: %+v", listed)
+	}
+	if payloads.gets != 0 {
+		t.Errorf("List read %d payloads, want 0", payloads.gets)
+	}
+
+	// Get sí lo resuelve
+	got, err := s.Get(ctx, "conv-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.NoSQLSchema != `{"tables":[]}` || payloads.gets != 1 {
+		t.Errorf("Get resolved %q with %d reads", got.NoSQLSchema, payloads.gets)
+	}
+}
+
+func TestDynamoStore_NewItemCopiesOffloadedResult(t *testing.T) {
+	ctx := context.Background()
+	payloads := NewMemoryPayloadStore()
+	s := NewDynamoStore(nil, "schemas").WithPayloads(payloads, 16)
+
+	// Resultado en caché ya subido a S3 por la conversión de origen; como
+	// FindCompleted, la fuente llega sin resolver
+	schema := strings.Repeat("x", 17)
+	ref, _ := payloads.Put(ctx, payloadKey("source", "noSqlSchema"), []byte(schema))
+	source := &Conversion{ConversionID: "source", Status: StatusCompleted, NoSQLSchemaRef: ref}
+
+	c := NewConversion("CREATE TABLE t;", "balanced", 1, nil)
+	c.ReuseResult(source)
+
+	item, err := s.newItem(ctx, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := item["noSqlSchema"]; ok {
+		t.Error("the offloaded result must not be stored inline")
+	}
+
+	// El registro nuevo apunta a su propia copia, que caduca con él
+	want := "mem://" + payloadKey(c.ConversionID, "noSqlSchema")
+	if got, ok := item["noSqlSchemaRef"].(*types.AttributeValueMemberS); !ok || got.Value != want {
+		t.Errorf("noSqlSchemaRef = %v, want %q", item["noSqlSchemaRef"], want)
+	}
+	if c.NoSQLSchemaRef != want {
+		t.Errorf("c.NoSQLSchemaRef = %q, want %q", c.NoSQLSchemaRef, want)
+	}
+	if data, err := payloads.Get(ctx, want); err != nil || string(data) != schema {
+		t.Errorf("copied payload = %q, %v", data, err)
+	}
+}
+
+func TestInlinePayload_Compression(t *testing.T) {
+	large := strings.Repeat("CREATE TABLE t (id INT);\n", 100)
+
+	tests := []struct {
+		name           string
+		value          string
+		wantCompressed bool
+	}{
+		{"pequeño queda como string", "CREATE TABLE t (id INT);", false},
+		{"grande se comprime", large, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attrs, err := inlinePayload("sqlContent", tt.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, compressed := attrs["sqlContent"].(*types.AttributeValueMemberB)
+			if compressed != tt.wantCompressed {
+				t.Fatalf("compressed = %v, want %v", compressed, tt.wantCompressed)
+			}
+			if compressed && len(b.Value) >= len(tt.value) {
+				t.Errorf("compressed size %d not below %d", len(b.Value), len(tt.value))
+			}
+
+			attrs["conversionId"] = &types.AttributeValueMemberS{Value: "conv-1"}
+			if got := unmarshalConversion(attrs).SQLContent; got != tt.value {
+				t.Errorf("decoded sqlContent mismatch (%d bytes, want %d)", len(got), len(tt.value))
+			}
+		})
+	}
+
+	// Registros anteriores: el string sin comprimir se sigue leyendo
+	legacy := unmarshalConversion(map[string]types.AttributeValue{
+		"noSqlSchema": &types.AttributeValueMemberS{Value: large},
+	})
+	if legacy.NoSQLSchema != large {
+		t.Error("uncompressed string payload not read")
+	}
+
+	unknown := unmarshalConversion(map[string]types.AttributeValue{
+		"noSqlSchema":     &types.AttributeValueMemberB{Value: []byte("data")},
+		"contentEncoding": &types.AttributeValueMemberS{Value: "br"},
+	})
+	if unknown.NoSQLSchema != "" {
+		t.Errorf("unknown encoding decoded as %q", unknown.NoSQLSchema)
+	}
+}
+
+func TestNewQueueMessage_ClaimCheck(t *testing.T) {
+	tests := []struct {
+		name        string
+		ref         string
+		wantSQL     bool
+		wantRefSent bool
+	}{
+		{"SQL en línea", "", true, false},
+		{"SQL en S3 viaja como referencia", "s3://bucket/conversions/x/sqlContent", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record := NewConversion("CREATE TABLE t (id INT);", "balanced", 1, nil)
+			record.SQLContentRef = tt.ref
+
+			msg := NewQueueMessage(record)
+			if (msg.SQLContent != "") != tt.wantSQL || (msg.SQLContentRef != "") != tt.wantRefSent {
+				t.Errorf("unexpected message: %+v", msg)
+			}
+		})
+	}
+}